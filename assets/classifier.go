@@ -1,6 +1,7 @@
 package assets
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/nyaruka/gocommon/uuids"
@@ -9,7 +10,8 @@ import (
 // ClassifierUUID is the UUID of an NLU classifier
 type ClassifierUUID uuids.UUID
 
-// Classifier is an NLU classifier.
+// Classifier is an NLU classifier. Classifiers which are evaluated locally rather than by an external service
+// can include type specific configuration.
 //
 //   {
 //     "uuid": "37657cf7-5eab-4286-9cb0-bbf270587bad",
@@ -24,6 +26,7 @@ type Classifier interface {
 	Name() string
 	Type() string
 	Intents() []string
+	Config() json.RawMessage
 }
 
 // ClassifierReference is used to reference a classifier
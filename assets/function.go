@@ -0,0 +1,50 @@
+package assets
+
+import "fmt"
+
+// Function is a reusable function which can be called from any expression. Its body is an Excellent expression in
+// which its parameters are available by name, along with builtin functions and other functions.
+//
+//   {
+//     "name": "format_id",
+//     "parameters": ["id"],
+//     "body": "upper(text_slice(id, 0, 3)) & \"-\" & text_slice(id, 3)"
+//   }
+//
+// @asset function
+type Function interface {
+	Name() string
+	Parameters() []string
+	Body() string
+}
+
+// FunctionReference is a reference to a function
+type FunctionReference struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// NewFunctionReference creates a new function reference with the given name
+func NewFunctionReference(name string) *FunctionReference {
+	return &FunctionReference{Name: name}
+}
+
+// Type returns the name of the asset type
+func (r *FunctionReference) Type() string {
+	return "function"
+}
+
+// Identity returns the unique identity of the asset
+func (r *FunctionReference) Identity() string {
+	return r.Name
+}
+
+// Variable returns whether this a variable (vs concrete) reference
+func (r *FunctionReference) Variable() bool {
+	return false
+}
+
+func (r *FunctionReference) String() string {
+	return fmt.Sprintf("%s[name=%s]", r.Type(), r.Identity())
+}
+
+var _ Reference = (*FunctionReference)(nil)
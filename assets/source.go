@@ -7,6 +7,7 @@ type Source interface {
 	Fields() ([]Field, error)
 	FlowByUUID(FlowUUID) (Flow, error)
 	FlowByName(string) (Flow, error)
+	Functions() ([]Function, error)
 	Globals() ([]Global, error)
 	Groups() ([]Group, error)
 	Labels() ([]Label, error)
@@ -1,6 +1,8 @@
 package static
 
 import (
+	"encoding/json"
+
 	"github.com/developc3ntro/omni-goflow/assets"
 )
 
@@ -10,6 +12,7 @@ type Classifier struct {
 	Name_    string                `json:"name"`
 	Type_    string                `json:"type"`
 	Intents_ []string              `json:"intents"`
+	Config_  json.RawMessage       `json:"config,omitempty"`
 }
 
 // NewClassifier creates a new classifier
@@ -22,6 +25,17 @@ func NewClassifier(uuid assets.ClassifierUUID, name string, type_ string, intent
 	}
 }
 
+// NewClassifierWithConfig creates a new classifier with type specific configuration
+func NewClassifierWithConfig(uuid assets.ClassifierUUID, name string, type_ string, intents []string, config json.RawMessage) assets.Classifier {
+	return &Classifier{
+		UUID_:    uuid,
+		Name_:    name,
+		Type_:    type_,
+		Intents_: intents,
+		Config_:  config,
+	}
+}
+
 // UUID returns the UUID of this channel
 func (c *Classifier) UUID() assets.ClassifierUUID { return c.UUID_ }
 
@@ -33,3 +47,6 @@ func (c *Classifier) Type() string { return c.Type_ }
 
 // Intents returns the intents of this classifier
 func (c *Classifier) Intents() []string { return c.Intents_ }
+
+// Config returns the type specific configuration of this classifier
+func (c *Classifier) Config() json.RawMessage { return c.Config_ }
@@ -1,6 +1,7 @@
 package static_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/developc3ntro/omni-goflow/assets"
@@ -20,4 +21,15 @@ func TestClassifier(t *testing.T) {
 	assert.Equal(t, "Booking", classifier.Name())
 	assert.Equal(t, "wit", classifier.Type())
 	assert.Equal(t, []string{"book_flight", "book_hotel"}, classifier.Intents())
+	assert.Nil(t, classifier.Config())
+
+	classifier = static.NewClassifierWithConfig(
+		assets.ClassifierUUID("37657cf7-5eab-4286-9cb0-bbf270587bad"),
+		"Booking",
+		"rules",
+		[]string{"book_flight"},
+		json.RawMessage(`{"intents": [{"name": "book_flight", "keywords": ["flight"]}]}`),
+	)
+	assert.Equal(t, "rules", classifier.Type())
+	assert.Equal(t, json.RawMessage(`{"intents": [{"name": "book_flight", "keywords": ["flight"]}]}`), classifier.Config())
 }
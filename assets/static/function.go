@@ -0,0 +1,30 @@
+package static
+
+import (
+	"github.com/developc3ntro/omni-goflow/assets"
+)
+
+// Function is a JSON serializable implementation of a function asset
+type Function struct {
+	Name_       string   `json:"name" validate:"required"`
+	Parameters_ []string `json:"parameters" validate:"dive,required"`
+	Body_       string   `json:"body" validate:"required"`
+}
+
+// NewFunction creates a new function
+func NewFunction(name string, parameters []string, body string) assets.Function {
+	return &Function{
+		Name_:       name,
+		Parameters_: parameters,
+		Body_:       body,
+	}
+}
+
+// Name returns the name of this function
+func (f *Function) Name() string { return f.Name_ }
+
+// Parameters returns the names of the parameters of this function
+func (f *Function) Parameters() []string { return f.Parameters_ }
+
+// Body returns the expression which is the body of this function
+func (f *Function) Body() string { return f.Body_ }
@@ -0,0 +1,15 @@
+package static_test
+
+import (
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/assets/static"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunction(t *testing.T) {
+	function := static.NewFunction("format_id", []string{"id"}, `upper(text_slice(id, 0, 3)) & "-" & text_slice(id, 3)`)
+	assert.Equal(t, "format_id", function.Name())
+	assert.Equal(t, []string{"id"}, function.Parameters())
+	assert.Equal(t, `upper(text_slice(id, 0, 3)) & "-" & text_slice(id, 3)`, function.Body())
+}
@@ -20,6 +20,7 @@ type StaticSource struct {
 		Classifiers []*Classifier             `json:"classifiers" validate:"omitempty,dive"`
 		Fields      []*Field                  `json:"fields" validate:"omitempty,dive"`
 		Flows       []*Flow                   `json:"flows" validate:"omitempty,dive"`
+		Functions   []*Function               `json:"functions" validate:"omitempty,dive"`
 		Globals     []*Global                 `json:"globals" validate:"omitempty,dive"`
 		Groups      []*Group                  `json:"groups" validate:"omitempty,dive"`
 		Labels      []*Label                  `json:"labels" validate:"omitempty,dive"`
@@ -102,6 +103,15 @@ func (s *StaticSource) FlowByName(name string) (assets.Flow, error) {
 	return nil, errors.Errorf("no such flow with name '%s'", name)
 }
 
+// Functions returns all function assets
+func (s *StaticSource) Functions() ([]assets.Function, error) {
+	set := make([]assets.Function, len(s.s.Functions))
+	for i := range s.s.Functions {
+		set[i] = s.s.Functions[i]
+	}
+	return set, nil
+}
+
 // Globals returns all global assets
 func (s *StaticSource) Globals() ([]assets.Global, error) {
 	set := make([]assets.Global, len(s.s.Globals))
@@ -23,6 +23,9 @@ var assetsJSON = `{
         {"uuid": "d66a7823-eada-40e5-9a3a-57239d4690bf", "key": "gender", "name": "Gender", "type": "text"},
         {"uuid": "f1b5aea6-6586-41c7-9020-1a6326cc6565", "key": "age", "name": "Age", "type": "number"}
     ],
+	"functions": [
+		{"name": "format_id", "parameters": ["id"], "body": "upper(id)"}
+	],
 	"groups": [
 		{
 			"uuid": "2aad21f6-30b7-42c5-bd7f-1b720c154817",
@@ -77,6 +80,10 @@ func TestSource(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Empty", flow.Name())
 
+	functions, err := src.Functions()
+	assert.NoError(t, err)
+	assert.Len(t, functions, 1)
+
 	globals, err := src.Globals()
 	assert.NoError(t, err)
 	assert.Len(t, globals, 0)
@@ -0,0 +1,198 @@
+package main
+
+// go install github.com/developc3ntro/omni-goflow/cmd/flowdiff
+// flowdiff old_export.json new_export.json
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/buger/jsonparser"
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/definition"
+	"github.com/developc3ntro/omni-goflow/flows/definition/migrations"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/pkg/errors"
+)
+
+const usage = `usage: flowdiff [flags] <old.json> <new.json>`
+
+// possible statuses of a flow in a diff
+const (
+	StatusAdded   = "added"
+	StatusRemoved = "removed"
+	StatusChanged = "changed"
+)
+
+// FlowDiff is the differences between the old and new versions of a single flow
+type FlowDiff struct {
+	Flow    *assets.FlowReference `json:"flow"`
+	Status  string                `json:"status"`
+	Changes []*definition.Change  `json:"changes,omitempty"`
+}
+
+func main() {
+	var format, baseMediaURL string
+	flags := flag.NewFlagSet("", flag.ExitOnError)
+	flags.StringVar(&format, "format", "text", "output format: text or json")
+	flags.StringVar(&baseMediaURL, "base-media-url", "", "base URL for media files in legacy flows")
+	flags.Parse(os.Args[1:])
+	args := flags.Args()
+
+	if len(args) != 2 {
+		fmt.Println(usage)
+		flags.PrintDefaults()
+		os.Exit(2)
+	}
+
+	var migrationConfig *migrations.Config
+	if baseMediaURL != "" {
+		migrationConfig = &migrations.Config{BaseMediaURL: baseMediaURL}
+	}
+
+	oldFlows, err := readFlowsFile(args[0], migrationConfig)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	newFlows, err := readFlowsFile(args[1], migrationConfig)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	diffs := DiffFlows(oldFlows, newFlows)
+
+	if err := WriteDiffs(os.Stdout, format, diffs); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	// like diff, exit with 1 if there are differences
+	if len(diffs) > 0 {
+		os.Exit(1)
+	}
+}
+
+func readFlowsFile(path string, mc *migrations.Config) ([]flows.Flow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading file '%s'", path)
+	}
+
+	return ReadFlows(data, mc)
+}
+
+// ReadFlows reads either a single flow definition or all the flows in an assets file, migrating them to the current
+// spec version
+func ReadFlows(data json.RawMessage, mc *migrations.Config) ([]flows.Flow, error) {
+	flowsJSON, _, _, err := jsonparser.Get(data, "flows")
+	if err == jsonparser.KeyPathNotFoundError {
+		flow, err := definition.ReadFlow(data, mc)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read flow")
+		}
+		return []flows.Flow{flow}, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "unable to read flows")
+	}
+
+	var definitions []json.RawMessage
+	if err := jsonx.Unmarshal(flowsJSON, &definitions); err != nil {
+		return nil, errors.Wrap(err, "unable to read flows")
+	}
+
+	all := make([]flows.Flow, len(definitions))
+	for i, d := range definitions {
+		if all[i], err = definition.ReadFlow(d, mc); err != nil {
+			return nil, errors.Wrapf(err, "unable to read flow[%d]", i)
+		}
+	}
+	return all, nil
+}
+
+// DiffFlows matches the old and new flows by UUID and returns the differences for every flow which was added, removed
+// or changed. If there's only a single flow on each side then they're compared regardless of UUID.
+func DiffFlows(old, new []flows.Flow) []*FlowDiff {
+	diffs := make([]*FlowDiff, 0)
+
+	if len(old) == 1 && len(new) == 1 {
+		if changes := definition.Diff(old[0], new[0]); len(changes) > 0 {
+			diffs = append(diffs, &FlowDiff{Flow: new[0].Reference(), Status: StatusChanged, Changes: changes})
+		}
+		return diffs
+	}
+
+	oldByUUID := make(map[assets.FlowUUID]flows.Flow, len(old))
+	for _, f := range old {
+		oldByUUID[f.UUID()] = f
+	}
+	inNew := make(map[assets.FlowUUID]bool, len(new))
+
+	for _, newFlow := range new {
+		inNew[newFlow.UUID()] = true
+
+		oldFlow := oldByUUID[newFlow.UUID()]
+		if oldFlow == nil {
+			diffs = append(diffs, &FlowDiff{Flow: newFlow.Reference(), Status: StatusAdded})
+		} else if changes := definition.Diff(oldFlow, newFlow); len(changes) > 0 {
+			diffs = append(diffs, &FlowDiff{Flow: newFlow.Reference(), Status: StatusChanged, Changes: changes})
+		}
+	}
+
+	for _, oldFlow := range old {
+		if !inNew[oldFlow.UUID()] {
+			diffs = append(diffs, &FlowDiff{Flow: oldFlow.Reference(), Status: StatusRemoved})
+		}
+	}
+
+	return diffs
+}
+
+// WriteDiffs writes the given flow differences in the given format
+func WriteDiffs(w io.Writer, format string, diffs []*FlowDiff) error {
+	switch format {
+	case "text":
+		return writeText(w, diffs)
+	case "json":
+		return writeJSON(w, diffs)
+	}
+	return errors.Errorf("unknown output format: %s", format)
+}
+
+func writeText(w io.Writer, diffs []*FlowDiff) error {
+	lines := make([]string, 0)
+
+	for _, d := range diffs {
+		heading := fmt.Sprintf("%s (%s): %s", d.Flow.Name, d.Flow.UUID, d.Status)
+		if d.Status == StatusChanged {
+			heading = fmt.Sprintf("%s (%s): %d changes", d.Flow.Name, d.Flow.UUID, len(d.Changes))
+		}
+		lines = append(lines, heading)
+
+		for _, c := range d.Changes {
+			lines = append(lines, "  "+c.Description())
+		}
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, "no differences")
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func writeJSON(w io.Writer, diffs []*FlowDiff) error {
+	marshaled, err := jsonx.MarshalPretty(diffs)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(marshaled))
+	return err
+}
@@ -0,0 +1,67 @@
+package main_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	main "github.com/developc3ntro/omni-goflow/cmd/flowdiff"
+	"github.com/developc3ntro/omni-goflow/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffFlows(t *testing.T) {
+	readFlows := func(path string) []byte {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return data
+	}
+
+	oldFlows, err := main.ReadFlows(readFlows("testdata/old.json"), nil)
+	require.NoError(t, err)
+	newFlows, err := main.ReadFlows(readFlows("testdata/new.json"), nil)
+	require.NoError(t, err)
+
+	assert.Len(t, main.DiffFlows(oldFlows, oldFlows), 0)
+
+	diffs := main.DiffFlows(oldFlows, newFlows)
+
+	statuses := make([]string, len(diffs))
+	for i, d := range diffs {
+		statuses[i] = d.Flow.Name + ":" + d.Status
+	}
+	assert.Equal(t, []string{"Favorite Colors:changed", "Brand New:added", "Retired:removed"}, statuses)
+
+	for _, format := range []string{"text", "json"} {
+		out := &strings.Builder{}
+		err = main.WriteDiffs(out, format, diffs)
+		require.NoError(t, err)
+
+		test.AssertSnapshot(t, format, out.String())
+	}
+
+	out := &strings.Builder{}
+	require.NoError(t, main.WriteDiffs(out, "text", nil))
+	assert.Equal(t, "no differences\n", out.String())
+
+	err = main.WriteDiffs(&strings.Builder{}, "xml", diffs)
+	assert.EqualError(t, err, "unknown output format: xml")
+
+	// single flow definitions are compared regardless of UUID
+	oldFlows, err = main.ReadFlows(readFlows("../../flows/definition/testdata/diff_old.json"), nil)
+	require.NoError(t, err)
+	newFlows, err = main.ReadFlows([]byte(strings.Replace(string(readFlows("../../flows/definition/testdata/diff_new.json")), "a1fe2f3e-8fc5-4cc0-b4d3-48f486d762e3", "5a0b1c2d-3e4f-4a5b-8c6d-7e8f9a0b1c2d", 1)), nil)
+	require.NoError(t, err)
+
+	diffs = main.DiffFlows(oldFlows, newFlows)
+	assert.Len(t, diffs, 1)
+	assert.Equal(t, main.StatusChanged, diffs[0].Status)
+
+	_, err = main.ReadFlows([]byte(`{"flows": [{"uuid": "5a0b1c2d-3e4f-4a5b-8c6d-7e8f9a0b1c2d"}]}`), nil)
+	assert.EqualError(t, err, "unable to read flow[0]: unable to read flow header: field 'spec_version' is required")
+
+	_, err = main.ReadFlows([]byte(`{"flows": {}}`), nil)
+	assert.Error(t, err)
+}
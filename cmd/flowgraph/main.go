@@ -0,0 +1,135 @@
+package main
+
+// go install github.com/developc3ntro/omni-goflow/cmd/flowgraph
+// flowgraph -format mermaid flows.json
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/buger/jsonparser"
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/definition"
+	"github.com/developc3ntro/omni-goflow/flows/definition/migrations"
+	"github.com/developc3ntro/omni-goflow/flows/graph"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/pkg/errors"
+)
+
+const usage = `usage: flowgraph [flags] <flows.json>`
+
+func main() {
+	var format, flowUUID, visitsPath, baseMediaURL string
+	flags := flag.NewFlagSet("", flag.ExitOnError)
+	flags.StringVar(&format, "format", "dot", "output format: dot or mermaid")
+	flags.StringVar(&flowUUID, "flow", "", "UUID of the flow to graph if the file contains more than one")
+	flags.StringVar(&visitsPath, "visits", "", "JSON file of node UUIDs to visit counts used to color nodes")
+	flags.StringVar(&baseMediaURL, "base-media-url", "", "base URL for media files in legacy flows")
+	flags.Parse(os.Args[1:])
+	args := flags.Args()
+
+	if len(args) != 1 {
+		fmt.Println(usage)
+		flags.PrintDefaults()
+		os.Exit(2)
+	}
+
+	var migrationConfig *migrations.Config
+	if baseMediaURL != "" {
+		migrationConfig = &migrations.Config{BaseMediaURL: baseMediaURL}
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Printf("error reading file '%s': %s\n", args[0], err)
+		os.Exit(2)
+	}
+
+	flow, err := ReadFlow(data, assets.FlowUUID(flowUUID), migrationConfig)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	var visits map[flows.NodeUUID]int
+	if visitsPath != "" {
+		if visits, err = readVisitsFile(visitsPath); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	if err := WriteGraph(os.Stdout, format, flow, visits); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+}
+
+func readVisitsFile(path string) (map[flows.NodeUUID]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading file '%s'", path)
+	}
+
+	visits := make(map[flows.NodeUUID]int)
+	if err := jsonx.Unmarshal(data, &visits); err != nil {
+		return nil, errors.Wrapf(err, "unable to read visits from '%s'", path)
+	}
+	return visits, nil
+}
+
+// ReadFlow reads either a single flow definition or the flow with the given UUID from an assets file, migrating it to
+// the current spec version. If the assets file only contains one flow then the UUID can be omitted.
+func ReadFlow(data json.RawMessage, uuid assets.FlowUUID, mc *migrations.Config) (flows.Flow, error) {
+	flowsJSON, _, _, err := jsonparser.Get(data, "flows")
+	if err == jsonparser.KeyPathNotFoundError {
+		flow, err := definition.ReadFlow(data, mc)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read flow")
+		}
+		return flow, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "unable to read flows")
+	}
+
+	var definitions []json.RawMessage
+	if err := jsonx.Unmarshal(flowsJSON, &definitions); err != nil {
+		return nil, errors.Wrap(err, "unable to read flows")
+	}
+
+	if uuid == "" {
+		if len(definitions) != 1 {
+			return nil, errors.Errorf("file contains %d flows, use -flow to select one", len(definitions))
+		}
+		flow, err := definition.ReadFlow(definitions[0], mc)
+		return flow, errors.Wrap(err, "unable to read flow[0]")
+	}
+
+	for i, d := range definitions {
+		flowUUID, _ := jsonparser.GetString(d, "uuid")
+		if assets.FlowUUID(flowUUID) == uuid {
+			flow, err := definition.ReadFlow(d, mc)
+			return flow, errors.Wrapf(err, "unable to read flow[%d]", i)
+		}
+	}
+
+	return nil, errors.Errorf("no flow with UUID %s", uuid)
+}
+
+// WriteGraph writes a graph of the given flow in the given format. If visits isn't nil then nodes are colored by how
+// often they were visited.
+func WriteGraph(w io.Writer, format string, flow flows.Flow, visits map[flows.NodeUUID]int) error {
+	g := graph.New(flow, visits)
+
+	switch format {
+	case "dot":
+		return graph.WriteDOT(w, g)
+	case "mermaid":
+		return graph.WriteMermaid(w, g)
+	}
+	return errors.Errorf("unknown output format: %s", format)
+}
@@ -0,0 +1,53 @@
+package main_test
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	main "github.com/developc3ntro/omni-goflow/cmd/flowgraph"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteGraph(t *testing.T) {
+	readFile := func(path string) []byte {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return data
+	}
+
+	flow, err := main.ReadFlow(readFile("../../flows/graph/testdata/favorites.json"), "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Favorites", flow.Name())
+
+	visits := map[flows.NodeUUID]int{}
+	require.NoError(t, json.Unmarshal(readFile("testdata/visits.json"), &visits))
+
+	for _, format := range []string{"dot", "mermaid"} {
+		out := &strings.Builder{}
+		require.NoError(t, main.WriteGraph(out, format, flow, visits))
+
+		test.AssertSnapshot(t, format, out.String())
+	}
+
+	err = main.WriteGraph(&strings.Builder{}, "xml", flow, nil)
+	assert.EqualError(t, err, "unknown output format: xml")
+
+	// assets files with more than one flow require a flow UUID
+	assetsJSON := readFile("../flowdiff/testdata/new.json")
+
+	_, err = main.ReadFlow(assetsJSON, "", nil)
+	assert.EqualError(t, err, "file contains 3 flows, use -flow to select one")
+
+	flow, err = main.ReadFlow(assetsJSON, "a1fe2f3e-8fc5-4cc0-b4d3-48f486d762e3", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Favorite Colors", flow.Name())
+
+	_, err = main.ReadFlow(assetsJSON, "00000000-0000-0000-0000-000000000000", nil)
+	assert.EqualError(t, err, "no flow with UUID 00000000-0000-0000-0000-000000000000")
+}
@@ -0,0 +1,301 @@
+package main
+
+// go install github.com/developc3ntro/omni-goflow/cmd/flowlint
+// flowlint -format sarif -fail-on warning -languages eng,spa export.json > flowlint.sarif
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/assets/static"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/definition/migrations"
+	"github.com/developc3ntro/omni-goflow/flows/engine"
+	"github.com/developc3ntro/omni-goflow/flows/inspect/issues"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/pkg/errors"
+)
+
+const usage = `usage: flowlint [flags] <assets.json>`
+
+// Severity is the severity of a finding
+type Severity string
+
+// possible severities of findings, named to match SARIF levels
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityNote    Severity = "note"
+)
+
+var severityRanks = map[Severity]int{SeverityNote: 1, SeverityWarning: 2, SeverityError: 3}
+
+// AtLeast returns whether this severity is at least as severe as the given severity
+func (s Severity) AtLeast(other Severity) bool { return severityRanks[s] >= severityRanks[other] }
+
+// finding types which aren't flow issues
+const (
+	TypeInvalidFlow      = "invalid_flow"
+	TypeUnresolvedResult = "unresolved_result"
+)
+
+// severities of each type of finding, with any issue types not listed here being warnings
+var typeSeverities = map[string]Severity{
+	TypeInvalidFlow:                     SeverityError,
+	TypeUnresolvedResult:                SeverityWarning,
+	issues.TypeInfiniteLoop:             SeverityError,
+	issues.TypeInvalidRegex:             SeverityError,
+	issues.TypeMissingDependency:        SeverityError,
+	issues.TypeMissingTranslation:       SeverityNote,
+	issues.TypeTemplateVariableMismatch: SeverityError,
+	issues.TypeTypeMismatch:             SeverityWarning,
+	issues.TypeUnknownContextPath:       SeverityError,
+	issues.TypeUnreachableNode:          SeverityWarning,
+	issues.TypeUnsetResult:              SeverityWarning,
+	issues.TypeWaitInBackground:         SeverityWarning,
+	issues.TypeWrongArgCount:            SeverityError,
+}
+
+func severityOf(typeName string) Severity {
+	if s, ok := typeSeverities[typeName]; ok {
+		return s
+	}
+	return SeverityWarning
+}
+
+// Finding is a single problem found in a flow
+type Finding struct {
+	Flow       *assets.FlowReference `json:"flow"`
+	Type       string                `json:"type"`
+	Severity   Severity              `json:"severity"`
+	NodeUUID   flows.NodeUUID        `json:"node_uuid,omitempty"`
+	ActionUUID flows.ActionUUID      `json:"action_uuid,omitempty"`
+	Language   envs.Language         `json:"language,omitempty"`
+	Message    string                `json:"message"`
+}
+
+func main() {
+	var format, failOn, baseMediaURL, languages string
+	flags := flag.NewFlagSet("", flag.ExitOnError)
+	flags.StringVar(&format, "format", "text", "output format: text, json or sarif")
+	flags.StringVar(&failOn, "fail-on", "error", "lowest severity which causes a non-zero exit: error, warning, note or none")
+	flags.StringVar(&baseMediaURL, "base-media-url", "", "base URL for media files in legacy flows")
+	flags.StringVar(&languages, "languages", "", "comma separated allowed languages which flows should be translated into")
+	flags.Parse(os.Args[1:])
+	args := flags.Args()
+
+	if len(args) != 1 || (failOn != "none" && severityRanks[Severity(failOn)] == 0) {
+		fmt.Fprintln(os.Stderr, usage)
+		flags.PrintDefaults()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	allowedLanguages, err := parseLanguages(languages)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	findings, err := Lint(data, baseMediaURL, allowedLanguages)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if err := WriteFindings(os.Stdout, format, args[0], findings); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if failOn != "none" && HasSeverity(findings, Severity(failOn)) {
+		os.Exit(1)
+	}
+}
+
+// Lint reads the flows in the given assets, migrating them to the current spec version, and returns all findings.
+// If allowed languages are given, flows are checked for translations missing in any of them.
+func Lint(data json.RawMessage, baseMediaURL string, languages []envs.Language) ([]*Finding, error) {
+	source, err := static.NewSource(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// static sources don't expose a list of all flows so read that separately
+	flowList := &struct {
+		Flows []*static.Flow `json:"flows"`
+	}{}
+	if err := jsonx.Unmarshal(data, flowList); err != nil {
+		return nil, errors.Wrap(err, "unable to read flows")
+	}
+
+	var migrationConfig *migrations.Config
+	if baseMediaURL != "" {
+		migrationConfig = &migrations.Config{BaseMediaURL: baseMediaURL}
+	}
+
+	sa, err := engine.NewSessionAssets(envs.NewBuilder().WithAllowedLanguages(languages).Build(), source, migrationConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]*Finding, 0)
+	inspected := make([]flows.Flow, 0, len(flowList.Flows))
+	inspections := make(map[assets.FlowUUID]*flows.Inspection, len(flowList.Flows))
+
+	for _, a := range flowList.Flows {
+		flow, err := sa.Flows().Get(a.UUID())
+		if err != nil {
+			ref := assets.NewFlowReference(a.UUID(), a.Name())
+			findings = append(findings, &Finding{Flow: ref, Type: TypeInvalidFlow, Severity: severityOf(TypeInvalidFlow), Message: err.Error()})
+			continue
+		}
+
+		inspected = append(inspected, flow)
+		inspections[flow.UUID()] = flow.Inspect(sa, languages...)
+	}
+
+	for _, flow := range inspected {
+		for _, issue := range inspections[flow.UUID()].Issues {
+			findings = append(findings, &Finding{
+				Flow:       flow.Reference(),
+				Type:       issue.Type(),
+				Severity:   severityOf(issue.Type()),
+				NodeUUID:   issue.NodeUUID(),
+				ActionUUID: issue.ActionUUID(),
+				Language:   issue.Language(),
+				Message:    issue.Description(),
+			})
+		}
+
+		for _, key := range unresolvedResults(flow, inspected, inspections) {
+			findings = append(findings, &Finding{
+				Flow:     flow.Reference(),
+				Type:     TypeUnresolvedResult,
+				Severity: severityOf(TypeUnresolvedResult),
+				Message:  fmt.Sprintf("parent result '%s' isn't set by any flow which enters this flow", key),
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// finds the parent results referenced by the given flow which aren't set by any of the flows which enter it
+func unresolvedResults(flow flows.Flow, all []flows.Flow, inspections map[assets.FlowUUID]*flows.Inspection) []string {
+	parentRefs := inspections[flow.UUID()].ParentRefs
+	if len(parentRefs) == 0 {
+		return nil
+	}
+
+	setByParents := make(map[string]bool)
+	for _, other := range all {
+		info := inspections[other.UUID()]
+
+		for _, dep := range info.Dependencies {
+			if dep.Reference().Type() == "flow" && dep.Reference().Identity() == string(flow.UUID()) {
+				for _, result := range info.Results {
+					setByParents[result.Key] = true
+				}
+				break
+			}
+		}
+	}
+
+	unresolved := make([]string, 0)
+	for _, key := range parentRefs {
+		if !setByParents[key] {
+			unresolved = append(unresolved, key)
+		}
+	}
+	sort.Strings(unresolved)
+	return unresolved
+}
+
+// parses a comma separated list of language codes
+func parseLanguages(s string) ([]envs.Language, error) {
+	languages := make([]envs.Language, 0)
+	for _, code := range strings.Split(s, ",") {
+		if code = strings.TrimSpace(code); code == "" {
+			continue
+		}
+		lang, err := envs.ParseLanguage(code)
+		if err != nil {
+			return nil, err
+		}
+		languages = append(languages, lang)
+	}
+	return languages, nil
+}
+
+// HasSeverity returns whether any of the given findings are at least as severe as the given severity
+func HasSeverity(findings []*Finding, severity Severity) bool {
+	for _, f := range findings {
+		if f.Severity.AtLeast(severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteFindings writes the given findings in the given format
+func WriteFindings(w io.Writer, format string, path string, findings []*Finding) error {
+	switch format {
+	case "text":
+		return writeText(w, path, findings)
+	case "json":
+		return writeJSON(w, findings)
+	case "sarif":
+		return writeSARIF(w, path, findings)
+	}
+	return errors.Errorf("unknown output format: %s", format)
+}
+
+func writeText(w io.Writer, path string, findings []*Finding) error {
+	counts := make(map[Severity]int)
+
+	for _, f := range findings {
+		location := make([]string, 0, 3)
+		if f.NodeUUID != "" {
+			location = append(location, "node="+string(f.NodeUUID))
+		}
+		if f.ActionUUID != "" {
+			location = append(location, "action="+string(f.ActionUUID))
+		}
+		if f.Language != "" {
+			location = append(location, "language="+string(f.Language))
+		}
+
+		line := fmt.Sprintf("%s: %s: %s: %s [%s]", path, f.Severity, f.Flow.Name, f.Message, f.Type)
+		if len(location) > 0 {
+			line += " " + strings.Join(location, " ")
+		}
+		fmt.Fprintln(w, line)
+
+		counts[f.Severity]++
+	}
+
+	_, err := fmt.Fprintf(w, "%d errors, %d warnings, %d notes\n", counts[SeverityError], counts[SeverityWarning], counts[SeverityNote])
+	return err
+}
+
+func writeJSON(w io.Writer, findings []*Finding) error {
+	marshaled, err := jsonx.MarshalPretty(findings)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(marshaled))
+	return err
+}
@@ -0,0 +1,64 @@
+package main_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	main "github.com/developc3ntro/omni-goflow/cmd/flowlint"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	data, err := os.ReadFile("testdata/flows.json")
+	require.NoError(t, err)
+
+	findings, err := main.Lint(data, "", nil)
+	require.NoError(t, err)
+
+	types := make([]string, len(findings))
+	for i, f := range findings {
+		types[i] = string(f.Severity) + ":" + f.Type
+	}
+
+	assert.Equal(t, []string{
+		"error:invalid_flow",
+		"error:missing_dependency",
+		"warning:unreachable_node",
+		"warning:unresolved_result",
+	}, types)
+
+	assert.True(t, main.HasSeverity(findings, main.SeverityError))
+	assert.True(t, main.HasSeverity(findings, main.SeverityNote))
+	assert.False(t, main.HasSeverity(findings[2:], main.SeverityError))
+	assert.True(t, main.HasSeverity(findings[2:], main.SeverityWarning))
+
+	for _, format := range []string{"text", "json", "sarif"} {
+		out := &strings.Builder{}
+		err = main.WriteFindings(out, format, "flows.json", findings)
+		require.NoError(t, err)
+
+		test.AssertSnapshot(t, format, out.String())
+	}
+
+	err = main.WriteFindings(&strings.Builder{}, "xml", "flows.json", findings)
+	assert.EqualError(t, err, "unknown output format: xml")
+
+	// flows are also checked for missing translations in any allowed languages
+	findings, err = main.Lint(data, "", []envs.Language{"eng", "spa"})
+	require.NoError(t, err)
+
+	types = make([]string, len(findings))
+	for i, f := range findings {
+		types[i] = string(f.Severity) + ":" + f.Type
+	}
+	assert.Contains(t, types, "note:missing_translation")
+
+	// error if assets can't be read
+	_, err = main.Lint([]byte(`{"flows": [{"name": "No UUID"}]}`), "", nil)
+	assert.EqualError(t, err, "unable to read assets: can't parse UUID from flow asset")
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/nyaruka/gocommon/jsonx"
+)
+
+// minimal subset of SARIF 2.1.0 needed to report findings to code scanning tools
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string       `json:"name"`
+	Rules []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string            `json:"id"`
+	DefaultConfiguration sarifRuleDefaults `json:"defaultConfiguration"`
+}
+
+type sarifRuleDefaults struct {
+	Level Severity `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID    string           `json:"ruleId"`
+	Level     Severity         `json:"level"`
+	Message   sarifMessage     `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation   `json:"physicalLocation"`
+	LogicalLocations []*sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func writeSARIF(w io.Writer, path string, findings []*Finding) error {
+	ruleIDs := make(map[string]bool)
+	results := make([]*sarifResult, len(findings))
+
+	for i, f := range findings {
+		ruleIDs[f.Type] = true
+
+		// locate the finding as precisely as we can within the flow
+		location := &sarifLogicalLocation{Name: f.Flow.Name, FullyQualifiedName: string(f.Flow.UUID), Kind: "module"}
+		if f.NodeUUID != "" {
+			location = &sarifLogicalLocation{Name: string(f.NodeUUID), FullyQualifiedName: fmt.Sprintf("%s/%s", f.Flow.UUID, f.NodeUUID), Kind: "function"}
+		}
+		if f.ActionUUID != "" {
+			location = &sarifLogicalLocation{Name: string(f.ActionUUID), FullyQualifiedName: fmt.Sprintf("%s/%s/%s", f.Flow.UUID, f.NodeUUID, f.ActionUUID), Kind: "member"}
+		}
+
+		results[i] = &sarifResult{
+			RuleID:  f.Type,
+			Level:   f.Severity,
+			Message: sarifMessage{Text: fmt.Sprintf("%s: %s", f.Flow.Name, f.Message)},
+			Locations: []*sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: path}},
+				LogicalLocations: []*sarifLogicalLocation{location},
+			}},
+		}
+	}
+
+	rules := make([]*sarifRule, 0, len(ruleIDs))
+	for id := range ruleIDs {
+		rules = append(rules, &sarifRule{ID: id, DefaultConfiguration: sarifRuleDefaults{Level: severityOf(id)}})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	log := &sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []*sarifRun{{Tool: sarifTool{Driver: sarifDriver{Name: "flowlint", Rules: rules}}, Results: results}},
+	}
+
+	marshaled, err := jsonx.MarshalPretty(log)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(marshaled))
+	return err
+}
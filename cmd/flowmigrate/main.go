@@ -3,6 +3,7 @@ package main
 // go install github.com/developc3ntro/omni-goflow/cmd/flowmigrate
 // cat legacy_flow.json | flowmigrate
 // cat legacy_export.json | jq '.flows[0]' | flowmigrate
+// cat flow.json | flowmigrate -to 13.0.0
 
 import (
 	"bufio"
@@ -13,6 +14,7 @@ import (
 	"os"
 
 	"github.com/Masterminds/semver"
+	"github.com/buger/jsonparser"
 	"github.com/developc3ntro/omni-goflow/flows/definition"
 	"github.com/developc3ntro/omni-goflow/flows/definition/migrations"
 	"github.com/nyaruka/gocommon/jsonx"
@@ -30,19 +32,24 @@ func main() {
 
 	reader := bufio.NewReader(os.Stdin)
 
-	output, err := Migrate(reader, semver.MustParse(toVersion), baseMediaURL, pretty)
+	output, incompatibilities, err := Migrate(reader, semver.MustParse(toVersion), baseMediaURL, pretty)
 	if err != nil {
 		fmt.Println(err)
 	} else {
 		fmt.Println(string(output))
 	}
+
+	for _, inc := range incompatibilities {
+		fmt.Fprintf(os.Stderr, "warning: %s (%s, requires %s)\n", inc.Description, inc.UUID, inc.Version)
+	}
 }
 
-// Migrate reads a flow definition as JSON and migrates it
-func Migrate(reader io.Reader, toVersion *semver.Version, baseMediaURL string, pretty bool) ([]byte, error) {
+// Migrate reads a flow definition as JSON and migrates it. If the definition is newer than the target version then it
+// is downgraded and any features which couldn't be represented in that version are returned.
+func Migrate(reader io.Reader, toVersion *semver.Version, baseMediaURL string, pretty bool) ([]byte, []*migrations.Incompatibility, error) {
 	data, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var migConfig *migrations.Config
@@ -52,20 +59,35 @@ func Migrate(reader io.Reader, toVersion *semver.Version, baseMediaURL string, p
 
 	migrated, err := migrations.MigrateToVersion(data, toVersion, migConfig)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// if the flow is newer than the target version, downgrade it
+	var incompatibilities []*migrations.Incompatibility
+	if toVersion != nil {
+		specVersion, _ := jsonparser.GetString(migrated, "spec_version")
+		if current, err := semver.NewVersion(specVersion); err == nil && current.GreaterThan(toVersion) {
+			migrated, incompatibilities, err = migrations.DowngradeToVersion(migrated, toVersion)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
 	}
 
 	// if we've migrated to the engine version, validate the flow can be read by the engine
 	if toVersion == nil || toVersion.Equal(definition.CurrentSpecVersion) {
 		_, err = definition.ReadFlow(migrated, nil)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	if pretty {
-		return jsonx.MarshalPretty(json.RawMessage(migrated))
+		migrated, err = jsonx.MarshalPretty(json.RawMessage(migrated))
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	return migrated, nil
+	return migrated, incompatibilities, nil
 }
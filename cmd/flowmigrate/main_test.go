@@ -5,9 +5,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/Masterminds/semver"
 	main "github.com/developc3ntro/omni-goflow/cmd/flowmigrate"
 	"github.com/developc3ntro/omni-goflow/flows/definition"
 	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -79,9 +81,77 @@ func TestMigrate(t *testing.T) {
 	for _, tc := range testCases {
 		input := strings.NewReader(tc.input)
 
-		migrated, err := main.Migrate(input, nil, "http://temba.io/", true)
+		migrated, incompatibilities, err := main.Migrate(input, nil, "http://temba.io/", true)
 		require.NoError(t, err)
+		assert.Len(t, incompatibilities, 0)
 
 		test.AssertEqualJSON(t, []byte(tc.output), migrated, "Migrated flow mismatch")
 	}
 }
+
+func TestMigrateToOlderVersion(t *testing.T) {
+	input := strings.NewReader(`{
+		"uuid": "76f0a02f-3b75-4b86-9064-e9195e1b3a02",
+		"name": "Simple",
+		"spec_version": "13.1.0",
+		"language": "eng",
+		"type": "messaging",
+		"nodes": [
+			{
+				"uuid": "365293c7-633c-45bd-96b7-0b059766588d",
+				"actions": [
+					{
+						"uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+						"type": "send_msg",
+						"text": "Hi @contact.name",
+						"templating": {
+							"uuid": "d2f852ec-7b4e-457f-ae7f-f8b243c49ff5",
+							"template": {"uuid": "3ce100b7-a734-4b4e-891b-350b1279ade2", "name": "greeting"},
+							"variables": ["@contact.name"]
+						}
+					}
+				],
+				"exits": [{"uuid": "b6f4caf3-ec99-44d5-a40c-8600ac0e2eac"}]
+			}
+		],
+		"localization": {
+			"spa": {
+				"d2f852ec-7b4e-457f-ae7f-f8b243c49ff5": {"variables": ["@contact.first_name"]}
+			}
+		}
+	}`)
+
+	migrated, incompatibilities, err := main.Migrate(input, semver.MustParse("13.0.0"), "", false)
+	require.NoError(t, err)
+
+	test.AssertEqualJSON(t, []byte(`{
+		"uuid": "76f0a02f-3b75-4b86-9064-e9195e1b3a02",
+		"name": "Simple",
+		"spec_version": "13.0.0",
+		"language": "eng",
+		"type": "messaging",
+		"nodes": [
+			{
+				"uuid": "365293c7-633c-45bd-96b7-0b059766588d",
+				"actions": [
+					{
+						"uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+						"type": "send_msg",
+						"text": "Hi @contact.name",
+						"templating": {
+							"template": {"uuid": "3ce100b7-a734-4b4e-891b-350b1279ade2", "name": "greeting"},
+							"variables": ["@contact.name"]
+						}
+					}
+				],
+				"exits": [{"uuid": "b6f4caf3-ec99-44d5-a40c-8600ac0e2eac"}]
+			}
+		],
+		"localization": {
+			"spa": {}
+		}
+	}`), migrated, "Downgraded flow mismatch")
+
+	require.Len(t, incompatibilities, 1)
+	assert.Equal(t, "translation of template variables to 'spa' isn't supported", incompatibilities[0].Description)
+}
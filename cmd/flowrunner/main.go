@@ -20,7 +20,10 @@ import (
 	"github.com/developc3ntro/omni-goflow/flows/events"
 	"github.com/developc3ntro/omni-goflow/flows/resumes"
 	"github.com/developc3ntro/omni-goflow/flows/triggers"
+	"github.com/developc3ntro/omni-goflow/services/classification/rules"
 	"github.com/developc3ntro/omni-goflow/services/classification/wit"
+	"github.com/developc3ntro/omni-goflow/services/email/sendgrid"
+	"github.com/developc3ntro/omni-goflow/services/tickets/helpdesk"
 	"github.com/developc3ntro/omni-goflow/services/webhooks"
 	"github.com/developc3ntro/omni-goflow/utils"
 	"github.com/nyaruka/gocommon/jsonx"
@@ -47,12 +50,18 @@ const contactJSON = `{
 const usage = `usage: flowrunner [flags] <assets.json> [flow_uuid]`
 
 func main() {
-	var initialMsg, contactLang, witToken string
+	var initialMsg, scenarioPath, contactLang, witToken, helpdeskURL, helpdeskToken, sendgridURL, sendgridKey, sendgridFrom string
 	var printRepro bool
 	flags := flag.NewFlagSet("", flag.ExitOnError)
 	flags.StringVar(&initialMsg, "msg", "", "initial message to trigger session with")
+	flags.StringVar(&scenarioPath, "scenario", "", "YAML or JSON scenario to run non-interactively, checking expected outcomes")
 	flags.StringVar(&contactLang, "lang", "eng", "initial language of the contact")
 	flags.StringVar(&witToken, "wit.token", "", "access token for wit.ai")
+	flags.StringVar(&helpdeskURL, "helpdesk.url", "", "base URL of helpdesk API for tickets (uses a local mock if not provided)")
+	flags.StringVar(&helpdeskToken, "helpdesk.token", "", "access token for helpdesk API")
+	flags.StringVar(&sendgridURL, "sendgrid.url", "https://api.sendgrid.com", "base URL of SendGrid compatible API for emails")
+	flags.StringVar(&sendgridKey, "sendgrid.key", "", "API key for SendGrid compatible API (emails can't be sent if not provided)")
+	flags.StringVar(&sendgridFrom, "sendgrid.from", "flows@example.com", "address to send emails from")
 	flags.BoolVar(&printRepro, "repro", false, "print repro afterwards")
 	flags.Parse(os.Args[1:])
 	args := flags.Args()
@@ -69,7 +78,29 @@ func main() {
 		flowUUID = assets.FlowUUID(args[1])
 	}
 
-	engine := createEngine(witToken)
+	// if we weren't given a helpdesk to open tickets in, use a local mock one
+	if helpdeskURL == "" {
+		mockHelpdesk := helpdesk.NewMockServer(helpdeskToken)
+		defer mockHelpdesk.Close()
+
+		helpdeskURL = mockHelpdesk.URL
+	}
+
+	engine := createEngine(witToken, helpdeskURL, helpdeskToken, sendgridURL, sendgridKey, sendgridFrom)
+
+	if scenarioPath != "" {
+		results, err := RunScenario(engine, assetsPath, flowUUID, scenarioPath, envs.Language(contactLang), os.Stdout)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		for _, r := range results {
+			if !r.Passed() {
+				os.Exit(1)
+			}
+		}
+		return
+	}
 
 	repro, err := RunFlow(engine, assetsPath, flowUUID, initialMsg, envs.Language(contactLang), os.Stdin, os.Stdout)
 
@@ -85,24 +116,97 @@ func main() {
 	}
 }
 
-func createEngine(witToken string) flows.Engine {
+func createEngine(witToken, helpdeskURL, helpdeskToken, sendgridURL, sendgridKey, sendgridFrom string) flows.Engine {
 	builder := engine.NewBuilder().
-		WithWebhookServiceFactory(webhooks.NewServiceFactory(http.DefaultClient, nil, nil, map[string]string{"User-Agent": "goflow-runner"}, 10000))
+		WithWebhookServiceFactory(webhooks.NewServiceFactory(http.DefaultClient, nil, nil, map[string]string{"User-Agent": "goflow-runner"}, 10000)).
+		WithTicketServiceFactory(func(session flows.Session, ticketer *flows.Ticketer) (flows.TicketService, error) {
+			return helpdesk.NewService(http.DefaultClient, nil, ticketer, helpdeskURL, helpdeskToken), nil
+		})
 
-	if witToken != "" {
-		builder.WithClassificationServiceFactory(func(session flows.Session, classifier *flows.Classifier) (flows.ClassificationService, error) {
-			if classifier.Type() == "wit" {
-				return wit.NewService(http.DefaultClient, nil, classifier, witToken), nil
-			}
-			return nil, errors.New("only classifiers of type wit supported")
+	if sendgridKey != "" {
+		builder.WithEmailServiceFactory(func(session flows.Session) (flows.EmailService, error) {
+			return sendgrid.NewService(http.DefaultClient, sendgrid.NewRetries(time.Second, 5*time.Second), nil, sendgridURL, sendgridKey, sendgridFrom, 1000000), nil
 		})
 	}
 
+	builder.WithClassificationServiceFactory(func(session flows.Session, classifier *flows.Classifier) (flows.ClassificationService, error) {
+		switch classifier.Type() {
+		case rules.TypeRules:
+			return rules.NewService(classifier)
+		case "wit":
+			if witToken != "" {
+				return wit.NewService(http.DefaultClient, nil, classifier, witToken), nil
+			}
+		}
+		return nil, errors.Errorf("no classification service available for classifiers of type %s", classifier.Type())
+	})
+
 	return builder.Build()
 }
 
 // RunFlow steps through a flow
 func RunFlow(eng flows.Engine, assetsPath string, flowUUID assets.FlowUUID, initialMsg string, contactLang envs.Language, in io.Reader, out io.Writer) (*Repro, error) {
+	setup, err := newSessionSetup(assetsPath, flowUUID, contactLang)
+	if err != nil {
+		return nil, err
+	}
+
+	contact, flow := setup.contact, setup.flow
+	repro := &Repro{Trigger: setup.trigger(initialMsg)}
+
+	fmt.Fprintf(out, "Starting flow '%s'....\n---------------------------------------\n", flow.Name())
+
+	// start our session
+	session, sprint, err := eng.NewSession(setup.sa, repro.Trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	printEvents(sprint.Events(), out)
+	scanner := bufio.NewScanner(in)
+
+	for session.Status() == flows.SessionStatusWaiting {
+
+		// ask for input
+		fmt.Fprintf(out, "> ")
+		scanner.Scan()
+
+		text := scanner.Text()
+		var resume flows.Resume
+
+		// create our resume
+		if text == "/timeout" {
+			resume = resumes.NewWaitTimeout(nil, nil)
+		} else if strings.HasPrefix(text, "/dial") {
+			status := flows.DialStatus(strings.TrimSpace(text[5:]))
+			resume = resumes.NewDial(nil, nil, flows.NewDial(status, 10))
+		} else {
+			msg := createMessage(contact, scanner.Text())
+			resume = resumes.NewMsg(nil, nil, msg)
+		}
+
+		repro.Resumes = append(repro.Resumes, resume)
+
+		sprint, err := session.Resume(resume)
+		if err != nil {
+			return nil, err
+		}
+
+		printEvents(sprint.Events(), out)
+	}
+
+	return repro, nil
+}
+
+// the assets, flow, contact and environment needed to start a session
+type sessionSetup struct {
+	sa      flows.SessionAssets
+	flow    flows.Flow
+	contact *flows.Contact
+	env     envs.Environment
+}
+
+func newSessionSetup(assetsPath string, flowUUID assets.FlowUUID, contactLang envs.Language) (*sessionSetup, error) {
 	assetsJSON, err := os.ReadFile(assetsPath)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error reading assets file '%s'", assetsPath)
@@ -143,64 +247,25 @@ func RunFlow(eng flows.Engine, assetsPath string, flowUUID assets.FlowUUID, init
 	languages := []envs.Language{flow.Language(), contact.Language()}
 	env := envs.NewBuilder().WithTimezone(la).WithAllowedLanguages(languages).Build()
 
-	repro := &Repro{}
+	return &sessionSetup{sa: sa, flow: flow, contact: contact, env: env}, nil
+}
 
+// creates a trigger to start the flow, which will be a msg trigger if an initial message is provided
+func (s *sessionSetup) trigger(initialMsg string) flows.Trigger {
 	if initialMsg != "" {
-		msg := createMessage(contact, initialMsg)
-		repro.Trigger = triggers.NewBuilder(env, flow.Reference(), contact).Msg(msg).Build()
-	} else {
-		tb := triggers.NewBuilder(env, flow.Reference(), contact).Manual()
-
-		// if we're starting a voice flow we need a channel connection
-		if flow.Type() == flows.FlowTypeVoice {
-			channel := sa.Channels().GetForURN(flows.NewContactURN(urns.URN("tel:+12065551212"), nil), assets.ChannelRoleCall)
-			tb = tb.WithConnection(channel.Reference(), urns.URN("tel:+12065551212"))
-		}
-
-		repro.Trigger = tb.Build()
+		msg := createMessage(s.contact, initialMsg)
+		return triggers.NewBuilder(s.env, s.flow.Reference(), s.contact).Msg(msg).Build()
 	}
-	fmt.Fprintf(out, "Starting flow '%s'....\n---------------------------------------\n", flow.Name())
 
-	// start our session
-	session, sprint, err := eng.NewSession(sa, repro.Trigger)
-	if err != nil {
-		return nil, err
-	}
+	tb := triggers.NewBuilder(s.env, s.flow.Reference(), s.contact).Manual()
 
-	printEvents(sprint.Events(), out)
-	scanner := bufio.NewScanner(in)
-
-	for session.Status() == flows.SessionStatusWaiting {
-
-		// ask for input
-		fmt.Fprintf(out, "> ")
-		scanner.Scan()
-
-		text := scanner.Text()
-		var resume flows.Resume
-
-		// create our resume
-		if text == "/timeout" {
-			resume = resumes.NewWaitTimeout(nil, nil)
-		} else if strings.HasPrefix(text, "/dial") {
-			status := flows.DialStatus(strings.TrimSpace(text[5:]))
-			resume = resumes.NewDial(nil, nil, flows.NewDial(status, 10))
-		} else {
-			msg := createMessage(contact, scanner.Text())
-			resume = resumes.NewMsg(nil, nil, msg)
-		}
-
-		repro.Resumes = append(repro.Resumes, resume)
-
-		sprint, err := session.Resume(resume)
-		if err != nil {
-			return nil, err
-		}
-
-		printEvents(sprint.Events(), out)
+	// if we're starting a voice flow we need a channel connection
+	if s.flow.Type() == flows.FlowTypeVoice {
+		channel := s.sa.Channels().GetForURN(flows.NewContactURN(urns.URN("tel:+12065551212"), nil), assets.ChannelRoleCall)
+		tb = tb.WithConnection(channel.Reference(), urns.URN("tel:+12065551212"))
 	}
 
-	return repro, nil
+	return tb.Build()
 }
 
 func createMessage(contact *flows.Contact, text string) *flows.MsgIn {
@@ -294,11 +359,23 @@ func PrintEvent(event flows.Event, out io.Writer) {
 		switch typed.Service {
 		case "classifier":
 			msg = fmt.Sprintf("👁️‍🗨️ NLU classifier '%s' called", typed.Classifier.Name)
+		case "ticketer":
+			msg = fmt.Sprintf("🎫 ticketer '%s' called", typed.Ticketer.Name)
+		case "email":
+			msg = "📧 email service called"
 		}
 	case *events.SessionTriggeredEvent:
 		msg = fmt.Sprintf("🏁 session triggered for '%s'", typed.Flow.Name)
+	case *events.TicketAssignedEvent:
+		msg = fmt.Sprintf("🎟️ ticket assigned to %s", typed.Ticket.Assignee.Email)
+	case *events.TicketClosedEvent:
+		msg = "🎟️ ticket closed"
+	case *events.TicketNoteAddedEvent:
+		msg = fmt.Sprintf("🎟️ ticket note added \"%s\"", typed.Note)
 	case *events.TicketOpenedEvent:
 		msg = fmt.Sprintf("🎟️ ticket opened with topic \"%s\"", typed.Ticket.Topic.Name)
+	case *events.TicketReopenedEvent:
+		msg = "🎟️ ticket reopened"
 	case *events.WaitTimedOutEvent:
 		msg = "⏲️ resuming due to wait timeout"
 	case *events.WebhookCalledEvent:
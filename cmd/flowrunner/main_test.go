@@ -54,6 +54,82 @@ func TestRunFlow(t *testing.T) {
 	assert.Contains(t, out.String(), "Starting flow 'Two Questions'")
 }
 
+func TestReadScenario(t *testing.T) {
+	scenario, err := main.ReadScenario([]byte(`resumes: [{type: msg, text: "yes", expect: {results: {answer: "yes"}}}]`))
+	require.NoError(t, err)
+	assert.Equal(t, "manual", scenario.Trigger.Type)
+	assert.Equal(t, 1, len(scenario.Resumes))
+	assert.Equal(t, map[string]string{"answer": "yes"}, scenario.Resumes[0].Expect.Results)
+
+	scenario, err = main.ReadScenario([]byte(`{"trigger": {"type": "msg", "text": "hi"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "msg", scenario.Trigger.Type)
+	assert.Equal(t, "hi", scenario.Trigger.Text)
+
+	_, err = main.ReadScenario([]byte(`resumes: [{text: "yes"}]`))
+	assert.EqualError(t, err, "unable to read scenario: field 'resumes[0].type' is required")
+
+	_, err = main.ReadScenario([]byte(`resumes: [`))
+	assert.EqualError(t, err, "unable to parse scenario: yaml: line 1: did not find expected node content")
+}
+
+func TestRunScenario(t *testing.T) {
+	out := &strings.Builder{}
+	results, err := main.RunScenario(test.NewEngine(), "testdata/two_questions.json", "", "testdata/two_questions_scenario.yaml", "eng", out)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, len(results))
+	for _, r := range results {
+		assert.True(t, r.Passed(), "expected step '%s' to pass", r.Name)
+	}
+	assert.Equal(t, []string{
+		"Running scenario 'testdata/two_questions_scenario.yaml' with flow 'Two Questions'....",
+		"---------------------------------------",
+		"✅ trigger (manual)",
+		"✅ resume 1 (msg \"I like red\")",
+		"✅ resume 2 (msg \"pepsi\")",
+		"---------------------------------------",
+		"3 of 3 steps passed",
+		"",
+	}, strings.Split(out.String(), "\n"))
+
+	out = &strings.Builder{}
+	results, err = main.RunScenario(test.NewEngine(), "testdata/two_questions.json", "", "testdata/two_questions_failing.json", "eng", out)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, len(results))
+	assert.False(t, results[0].Passed())
+	assert.False(t, results[1].Passed())
+	assert.False(t, results[2].Passed())
+	assert.Equal(t, []string{
+		"Running scenario 'testdata/two_questions_failing.json' with flow 'Two Questions'....",
+		"---------------------------------------",
+		"❌ trigger (msg \"hi\")",
+		"   expected messages [\"What is your favorite color?\"], got [\"Hi Ben Haggerty! What is your favorite color? (red/blue)\",\"Hi Ben Haggerty! What is your favorite color? (red/blue)\"]",
+		"❌ resume 1 (timeout)",
+		"   expected result 'favorite_color' to have category 'Blue', got 'No Response'",
+		"   expected session status 'waiting', got 'completed'",
+		"❌ resume 2 (msg \"blue\")",
+		"   session is completed and can't be resumed",
+		"---------------------------------------",
+		"0 of 3 steps passed",
+		"",
+	}, strings.Split(out.String(), "\n"))
+
+	// results of subflows are checked on the run of the flow given in the step
+	out = &strings.Builder{}
+	results, err = main.RunScenario(test.NewEngine(), "testdata/subflow.json", "", "testdata/subflow_scenario.yaml", "eng", out)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, len(results))
+	for _, r := range results {
+		assert.True(t, r.Passed(), "expected step '%s' to pass", r.Name)
+	}
+
+	_, err = main.RunScenario(test.NewEngine(), "testdata/two_questions.json", "", "testdata/missing.yaml", "eng", out)
+	assert.EqualError(t, err, "error reading scenario file 'testdata/missing.yaml': open testdata/missing.yaml: no such file or directory")
+}
+
 func TestPrintEvent(t *testing.T) {
 	session, _, err := test.CreateTestSession("", envs.RedactionPolicyNone)
 	require.NoError(t, err)
@@ -62,6 +138,8 @@ func TestPrintEvent(t *testing.T) {
 	flow, _ := sa.Flows().Get("50c3706e-fedb-42c0-8eab-dda3335714b7")
 	timeout := 3
 	expiresOn := time.Date(2022, 2, 3, 13, 45, 30, 0, time.UTC)
+	ticketer := sa.Ticketers().Get("19dc6346-9623-4fe4-be80-538d493ecdf5")
+	ticket := flows.OpenTicket(ticketer, sa.Topics().Get("472a7a73-96cb-4736-b567-056d987cc5b4"), "Where are my cookies?", sa.Users().Get("bob@nyaruka.com"))
 
 	tests := []struct {
 		event    flows.Event
@@ -78,7 +156,7 @@ func TestPrintEvent(t *testing.T) {
 		{events.NewContactTimezoneChanged(session.Environment().Timezone()), `🕑 timezone changed to 'America/Guayaquil'`},
 		{events.NewDialEnded(flows.NewDial(flows.DialStatusBusy, 3)), `☎️ dial ended with 'busy'`},
 		{events.NewDialWait(urns.URN(`tel:+1234567890`), nil), `⏳ waiting for dial (type /dial <answered|no_answer|busy|failed>)...`},
-		{events.NewEmailSent([]string{"code@example.com"}, "Hi", "What up?"), `✉️ email sent with subject 'Hi'`},
+		{events.NewEmailSent(&flows.Email{To: []string{"code@example.com"}, Subject: "Hi", Body: "What up?"}), `✉️ email sent with subject 'Hi'`},
 		{events.NewEnvironmentRefreshed(session.Environment()), `⚙️ environment refreshed on resume`},
 		{events.NewErrorf("this didn't work"), `⚠️ this didn't work`},
 		{events.NewFailure(errors.New("this really didn't work")), `🛑 this really didn't work`},
@@ -86,6 +164,13 @@ func TestPrintEvent(t *testing.T) {
 		{events.NewInputLabelsAdded("2a786bbc-2314-4d57-a0c9-b66e1642e5e2", []*flows.Label{sa.Labels().FindByName("Spam")}), `🏷️ labeled with 'Spam'`},
 		{events.NewMsgWait(nil, nil, nil), `⏳ waiting for message...`},
 		{events.NewMsgWait(&timeout, &expiresOn, nil), `⏳ waiting for message (3 sec timeout, type /timeout to simulate)...`},
+		{events.NewTicketAssigned(ticket), `🎟️ ticket assigned to bob@nyaruka.com`},
+		{events.NewTicketClosed(ticket), `🎟️ ticket closed`},
+		{events.NewTicketNoteAdded(ticket, "Customer was very polite"), `🎟️ ticket note added "Customer was very polite"`},
+		{events.NewTicketOpened(ticket), `🎟️ ticket opened with topic "Weather"`},
+		{events.NewTicketReopened(ticket), `🎟️ ticket reopened`},
+		{events.NewEmailServiceCalled(nil), `📧 email service called`},
+		{events.NewTicketerCalled(ticketer.Reference(), nil), `🎫 ticketer 'Support Tickets' called`},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/events"
+	"github.com/developc3ntro/omni-goflow/flows/resumes"
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a scripted run through a flow with expected outcomes after the trigger and each resume
+type Scenario struct {
+	Flow    assets.FlowUUID `json:"flow,omitempty"`
+	Trigger *ScenarioStep   `json:"trigger,omitempty"`
+	Resumes []*ScenarioStep `json:"resumes,omitempty" validate:"dive"`
+}
+
+// ScenarioStep is the trigger or a resume in a scenario. For triggers the type can be manual or msg, and for resumes
+// it can be msg, timeout, expiration or dial.
+type ScenarioStep struct {
+	Type       string           `json:"type" validate:"required"`
+	Text       string           `json:"text,omitempty"`
+	DialStatus flows.DialStatus `json:"dial_status,omitempty"`
+	Expect     *Expectations    `json:"expect,omitempty"`
+}
+
+// Expectations are the outcomes expected after a step. Anything not specified isn't checked, and an empty list of
+// messages means that no messages should be sent. Fields expected to be empty are given as empty strings. Results and
+// categories are checked on the run of the scenario's flow, unless another flow is given, e.g. a subflow, in which
+// case they're checked on the most recent run of that flow.
+type Expectations struct {
+	Flow       assets.FlowUUID     `json:"flow,omitempty"`
+	Messages   []string            `json:"messages,omitempty"`
+	Results    map[string]string   `json:"results,omitempty"`
+	Categories map[string]string   `json:"categories,omitempty"`
+	Fields     map[string]string   `json:"fields,omitempty"`
+	Status     flows.SessionStatus `json:"status,omitempty"`
+}
+
+// StepResult is the outcome of a single scenario step
+type StepResult struct {
+	Name     string   `json:"name"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+// Passed returns whether all expectations for this step were met
+func (r *StepResult) Passed() bool { return len(r.Failures) == 0 }
+
+// ReadScenario reads a scenario from YAML or JSON
+func ReadScenario(data []byte) (*Scenario, error) {
+	data = bytes.TrimSpace(data)
+
+	// YAML is a superset of JSON but JSON is often indented with tabs which YAML doesn't allow
+	if !bytes.HasPrefix(data, []byte("{")) {
+		var parsed interface{}
+		if err := yaml.Unmarshal(data, &parsed); err != nil {
+			return nil, errors.Wrap(err, "unable to parse scenario")
+		}
+
+		var err error
+		if data, err = jsonx.Marshal(parsed); err != nil {
+			return nil, errors.Wrap(err, "unable to parse scenario")
+		}
+	}
+
+	s := &Scenario{}
+	if err := utils.UnmarshalAndValidate(data, s); err != nil {
+		return nil, errors.Wrap(err, "unable to read scenario")
+	}
+	if s.Trigger == nil {
+		s.Trigger = &ScenarioStep{Type: "manual"}
+	}
+	return s, nil
+}
+
+// RunScenario runs the scenario at the given path against the given assets and reports whether every step passed
+func RunScenario(eng flows.Engine, assetsPath string, flowUUID assets.FlowUUID, scenarioPath string, contactLang envs.Language, out io.Writer) ([]*StepResult, error) {
+	data, err := os.ReadFile(scenarioPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading scenario file '%s'", scenarioPath)
+	}
+
+	scenario, err := ReadScenario(data)
+	if err != nil {
+		return nil, err
+	}
+	if flowUUID == "" {
+		flowUUID = scenario.Flow
+	}
+
+	setup, err := newSessionSetup(assetsPath, flowUUID, contactLang)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(out, "Running scenario '%s' with flow '%s'....\n---------------------------------------\n", scenarioPath, setup.flow.Name())
+
+	var trigger flows.Trigger
+	switch scenario.Trigger.Type {
+	case "manual":
+		trigger = setup.trigger("")
+	case "msg":
+		trigger = setup.trigger(scenario.Trigger.Text)
+	default:
+		return nil, errors.Errorf("unknown trigger type: %s", scenario.Trigger.Type)
+	}
+
+	session, sprint, err := eng.NewSession(setup.sa, trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*StepResult, 0, len(scenario.Resumes)+1)
+	results = append(results, checkStep(fmt.Sprintf("trigger (%s)", describeStep(scenario.Trigger)), scenario.Trigger.Expect, session, sprint, out))
+
+	for i, step := range scenario.Resumes {
+		name := fmt.Sprintf("resume %d (%s)", i+1, describeStep(step))
+
+		if session.Status() != flows.SessionStatusWaiting {
+			result := &StepResult{Name: name, Failures: []string{fmt.Sprintf("session is %s and can't be resumed", session.Status())}}
+			printStepResult(result, out)
+			results = append(results, result)
+			break
+		}
+
+		var resume flows.Resume
+		switch step.Type {
+		case "msg":
+			resume = resumes.NewMsg(nil, nil, createMessage(setup.contact, step.Text))
+		case "timeout":
+			resume = resumes.NewWaitTimeout(nil, nil)
+		case "expiration":
+			resume = resumes.NewRunExpiration(nil, nil)
+		case "dial":
+			resume = resumes.NewDial(nil, nil, flows.NewDial(step.DialStatus, 10))
+		default:
+			return nil, errors.Errorf("unknown resume type: %s", step.Type)
+		}
+
+		sprint, err := session.Resume(resume)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, checkStep(name, step.Expect, session, sprint, out))
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed() {
+			failed++
+		}
+	}
+
+	fmt.Fprintf(out, "---------------------------------------\n%d of %d steps passed\n", len(results)-failed, len(results))
+
+	return results, nil
+}
+
+// describes the given step for output
+func describeStep(s *ScenarioStep) string {
+	switch s.Type {
+	case "msg":
+		return fmt.Sprintf("msg \"%s\"", s.Text)
+	case "dial":
+		return fmt.Sprintf("dial %s", s.DialStatus)
+	}
+	return s.Type
+}
+
+// checks the state of the session after a step against the expectations for that step
+func checkStep(name string, expect *Expectations, session flows.Session, sprint flows.Sprint, out io.Writer) *StepResult {
+	result := &StepResult{Name: name}
+	fail := func(f string, args ...interface{}) {
+		result.Failures = append(result.Failures, fmt.Sprintf(f, args...))
+	}
+
+	if expect != nil {
+		if expect.Messages != nil {
+			sent := sentMessages(sprint)
+			if !slices.Equal(expect.Messages, sent) {
+				fail("expected messages %s, got %s", jsonx.MustMarshal(expect.Messages), jsonx.MustMarshal(sent))
+			}
+		}
+
+		var results flows.Results
+		if run := runToCheck(session, expect.Flow); run != nil {
+			results = run.Results()
+		} else if len(expect.Results) > 0 || len(expect.Categories) > 0 {
+			fail("expected a run of flow '%s', but there isn't one", expect.Flow)
+		}
+
+		for _, key := range sortedKeys(expect.Results) {
+			if actual := results.Get(key); actual == nil {
+				fail("expected result '%s' to be '%s', but it isn't set", key, expect.Results[key])
+			} else if actual.Value != expect.Results[key] {
+				fail("expected result '%s' to be '%s', got '%s'", key, expect.Results[key], actual.Value)
+			}
+		}
+		for _, key := range sortedKeys(expect.Categories) {
+			if actual := results.Get(key); actual == nil {
+				fail("expected result '%s' to have category '%s', but it isn't set", key, expect.Categories[key])
+			} else if actual.Category != expect.Categories[key] {
+				fail("expected result '%s' to have category '%s', got '%s'", key, expect.Categories[key], actual.Category)
+			}
+		}
+
+		for _, key := range sortedKeys(expect.Fields) {
+			var actual string
+			if value := session.Contact().Fields()[key]; value != nil {
+				actual = value.Text.Native()
+			}
+			if actual != expect.Fields[key] {
+				fail("expected field '%s' to be '%s', got '%s'", key, expect.Fields[key], actual)
+			}
+		}
+
+		if expect.Status != "" && session.Status() != expect.Status {
+			fail("expected session status '%s', got '%s'", expect.Status, session.Status())
+		}
+	}
+
+	printStepResult(result, out)
+	return result
+}
+
+// gets the run whose results should be checked, which is the most recent run of the given flow, or the first run
+func runToCheck(session flows.Session, flowUUID assets.FlowUUID) flows.Run {
+	runs := session.Runs()
+	if flowUUID == "" {
+		return runs[0]
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].FlowReference().UUID == flowUUID {
+			return runs[i]
+		}
+	}
+	return nil
+}
+
+func printStepResult(result *StepResult, out io.Writer) {
+	if result.Passed() {
+		fmt.Fprintf(out, "✅ %s\n", result.Name)
+	} else {
+		fmt.Fprintf(out, "❌ %s\n", result.Name)
+		for _, f := range result.Failures {
+			fmt.Fprintf(out, "   %s\n", f)
+		}
+	}
+}
+
+// gets the text of all messages sent during the given sprint
+func sentMessages(sprint flows.Sprint) []string {
+	sent := make([]string, 0)
+	for _, e := range sprint.Events() {
+		switch typed := e.(type) {
+		case *events.MsgCreatedEvent:
+			sent = append(sent, typed.Msg.Text())
+		case *events.IVRCreatedEvent:
+			sent = append(sent, typed.Msg.Text())
+		}
+	}
+	return sent
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 
@@ -13,6 +14,8 @@ import (
 	"github.com/developc3ntro/omni-goflow/flows/engine"
 	"github.com/developc3ntro/omni-goflow/flows/triggers"
 	"github.com/developc3ntro/omni-goflow/services/airtime/dtone"
+	"github.com/developc3ntro/omni-goflow/services/airtime/fake"
+	"github.com/developc3ntro/omni-goflow/services/airtime/reloadly"
 	"github.com/nyaruka/gocommon/httpx"
 	"github.com/nyaruka/gocommon/jsonx"
 	"github.com/nyaruka/gocommon/urns"
@@ -25,10 +28,16 @@ const usage = `usage: transferairtime [flags] <destnumber> <amount> <currency>`
 var verbose bool
 
 func main() {
-	var dtoneKey, dtoneSecret string
+	var provider, dtoneKey, dtoneSecret, reloadlyID, reloadlySecret, fakeOperators string
+	var reloadlySandbox bool
 	flags := flag.NewFlagSet("", flag.ExitOnError)
+	flags.StringVar(&provider, "provider", "dtone", "airtime provider to use: dtone, reloadly or fake")
 	flags.StringVar(&dtoneKey, "dtone.key", "", "API key for DTOne service")
 	flags.StringVar(&dtoneSecret, "dtone.secret", "", "API secret for DTOne service")
+	flags.StringVar(&reloadlyID, "reloadly.id", "", "client ID for Reloadly service")
+	flags.StringVar(&reloadlySecret, "reloadly.secret", "", "client secret for Reloadly service")
+	flags.BoolVar(&reloadlySandbox, "reloadly.sandbox", false, "use the Reloadly sandbox API")
+	flags.StringVar(&fakeOperators, "fake.operators", "", "path of JSON file of operators for fake service")
 	flags.BoolVar(&verbose, "v", false, "enable verbose logging")
 	flags.Parse(os.Args[1:])
 	args := flags.Args()
@@ -51,18 +60,43 @@ func main() {
 		os.Exit(1)
 	}
 
-	if dtoneKey == "" || dtoneSecret == "" {
-		fmt.Println("no airtime service credentials provided")
+	var svc flows.AirtimeService
+
+	switch provider {
+	case "dtone":
+		if dtoneKey == "" || dtoneSecret == "" {
+			fmt.Println("no airtime service credentials provided")
+			os.Exit(1)
+		}
+		svc = dtone.NewService(http.DefaultClient, nil, dtoneKey, dtoneSecret)
+	case "reloadly":
+		if reloadlyID == "" || reloadlySecret == "" {
+			fmt.Println("no airtime service credentials provided")
+			os.Exit(1)
+		}
+		svc = reloadly.NewService(http.DefaultClient, nil, reloadlyID, reloadlySecret, reloadlySandbox)
+	case "fake":
+		operatorsJSON, err := os.ReadFile(fakeOperators)
+		if err != nil {
+			fmt.Printf("unable to read operators file: %s\n", err)
+			os.Exit(1)
+		}
+		operators, err := fake.ReadOperators(operatorsJSON)
+		if err != nil {
+			fmt.Printf("unable to read operators: %s\n", err)
+			os.Exit(1)
+		}
+		svc = fake.NewService(operators)
+	default:
+		fmt.Printf("%s isn't a supported airtime provider\n", provider)
 		os.Exit(1)
 	}
 
 	httpx.SetDebug(verbose)
 
-	svcFactory := func(flows.Session) (flows.AirtimeService, error) {
-		return dtone.NewService(http.DefaultClient, nil, dtoneKey, dtoneSecret), nil
-	}
+	svcFactory := func(flows.Session) (flows.AirtimeService, error) { return svc, nil }
 
-	if err := transferAirtime(destination, amount, args[2], svcFactory); err != nil {
+	if err := transferAirtime(destination, amount, args[2], svcFactory, os.Stdout); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -101,7 +135,7 @@ const assetsTemplate = `
 }
 `
 
-func transferAirtime(destination urns.URN, amount decimal.Decimal, currency string, svcFactory engine.AirtimeServiceFactory) error {
+func transferAirtime(destination urns.URN, amount decimal.Decimal, currency string, svcFactory engine.AirtimeServiceFactory, out io.Writer) error {
 	// create a flow to do make the transfer
 	assetsJSON := fmt.Sprintf(assetsTemplate, currency, amount.String())
 	source, err := static.NewSource([]byte(assetsJSON))
@@ -129,7 +163,7 @@ func transferAirtime(destination urns.URN, amount decimal.Decimal, currency stri
 
 	for _, event := range sprint.Events() {
 		marshaled, _ := jsonx.Marshal(event)
-		fmt.Println(string(marshaled))
+		fmt.Fprintln(out, string(marshaled))
 	}
 
 	return nil
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/services/airtime/fake"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/urns"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferAirtime(t *testing.T) {
+	operators, err := fake.ReadOperators([]byte(`[
+		{"name": "Claro", "prefixes": ["+59397"], "products": [{"currency": "USD", "amount": 1}, {"currency": "USD", "amount": 5}]},
+		{"name": "Movistar", "prefixes": ["+59398"], "products": [{"currency": "USD", "amount": 1}], "fail": true}
+	]`))
+	require.NoError(t, err)
+
+	svc := fake.NewService(operators)
+	svcFactory := func(flows.Session) (flows.AirtimeService, error) { return svc, nil }
+
+	tcs := []struct {
+		destination    urns.URN
+		amount         string
+		currency       string
+		expectedEvents []string
+		expectedResult string
+	}{
+		{"tel:+593979123456", "3", "USD", []string{"airtime_transferred", "run_result_changed"}, "1"},
+		{"tel:+593979123456", "3", "RWF", []string{"airtime_transferred", "error", "run_result_changed"}, "0"},
+		{"tel:+593989123456", "3", "USD", []string{"airtime_transferred", "error", "run_result_changed"}, "0"},
+	}
+
+	for _, tc := range tcs {
+		out := &strings.Builder{}
+		err := transferAirtime(tc.destination, decimal.RequireFromString(tc.amount), tc.currency, svcFactory, out)
+		require.NoError(t, err)
+
+		eventTypes := make([]string, 0)
+		var result string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			event := &struct {
+				Type  string `json:"type"`
+				Value string `json:"value"`
+			}{}
+			require.NoError(t, jsonx.Unmarshal([]byte(line), event))
+
+			eventTypes = append(eventTypes, event.Type)
+			if event.Type == "run_result_changed" {
+				result = event.Value
+			}
+		}
+
+		assert.Equal(t, tc.expectedEvents, eventTypes, "event types mismatch for %s %s to %s", tc.amount, tc.currency, tc.destination)
+		assert.Equal(t, tc.expectedResult, result, "result mismatch for %s %s to %s", tc.amount, tc.currency, tc.destination)
+	}
+
+	assert.Equal(t, 1, len(svc.Transfers()))
+}
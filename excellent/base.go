@@ -36,14 +36,24 @@ func Parse(expression string, contextCallback func([]string)) (Expression, error
 
 // EvaluateTemplate evaluates the passed in template
 func EvaluateTemplate(env envs.Environment, ctx *types.XObject, template string, escaping Escaping) (string, error) {
+	return evaluateTemplate(nil, env, ctx, nil, template, escaping)
+}
+
+// EvaluateTemplate is like the package level EvaluateTemplate but uses this cache for parsed expressions and scanned
+// templates, and resolves names not found in the context using the given parent scope, or as builtin functions if nil
+func (c *Cache) EvaluateTemplate(env envs.Environment, ctx *types.XObject, parent *Scope, template string, escaping Escaping) (string, error) {
+	return evaluateTemplate(c, env, ctx, parent, template, escaping)
+}
+
+func evaluateTemplate(cache *Cache, env envs.Environment, ctx *types.XObject, parent *Scope, template string, escaping Escaping) (string, error) {
 	var buf strings.Builder
 
-	err := VisitTemplate(template, ctx.Properties(), func(tokenType XTokenType, token string) error {
+	err := visitTokens(cache.scan(template, ctx.Properties()), func(tokenType XTokenType, token string) error {
 		switch tokenType {
 		case BODY:
 			buf.WriteString(token)
 		case IDENTIFIER, EXPRESSION:
-			value := EvaluateExpression(env, ctx, token)
+			value := evaluateExpression(cache, env, ctx, parent, token)
 
 			// if we got an error, return that
 			if types.IsXError(value) {
@@ -70,37 +80,51 @@ func EvaluateTemplate(env envs.Environment, ctx *types.XObject, template string,
 // a single identifier or expression, ie: "@contact" or "@(first(contact.urns))". In these cases we return
 // the typed value from EvaluateExpression instead of stringifying the result.
 func EvaluateTemplateValue(env envs.Environment, ctx *types.XObject, template string) (types.XValue, error) {
-	template = strings.TrimSpace(template)
-	scanner := NewXScanner(strings.NewReader(template), ctx.Properties())
+	return evaluateTemplateValue(nil, env, ctx, nil, template)
+}
 
-	// parse our first token
-	tokenType, token := scanner.Scan()
+// EvaluateTemplateValue is like the package level EvaluateTemplateValue but uses this cache for parsed expressions and
+// scanned templates, and resolves names not found in the context using the given parent scope
+func (c *Cache) EvaluateTemplateValue(env envs.Environment, ctx *types.XObject, parent *Scope, template string) (types.XValue, error) {
+	return evaluateTemplateValue(c, env, ctx, parent, template)
+}
 
-	// try to scan to our next token
-	nextTT, _ := scanner.Scan()
+func evaluateTemplateValue(cache *Cache, env envs.Environment, ctx *types.XObject, parent *Scope, template string) (types.XValue, error) {
+	template = strings.TrimSpace(template)
+	tokens := cache.scan(template, ctx.Properties())
 
 	// if we only have an identifier or an expression, evaluate it on its own
-	if nextTT == EOF {
-		switch tokenType {
+	if len(tokens) == 1 {
+		switch tokens[0].tokenType {
 		case IDENTIFIER, EXPRESSION:
-			return EvaluateExpression(env, ctx, token), nil
+			return evaluateExpression(cache, env, ctx, parent, tokens[0].value), nil
 		}
 	}
 
 	// otherwise fallback to full template evaluation
-	asStr, err := EvaluateTemplate(env, ctx, template, nil)
+	asStr, err := evaluateTemplate(cache, env, ctx, parent, template, nil)
 	return types.NewXText(asStr), err
 }
 
 // EvaluateExpression evalutes the passed in Excellent expression, returning the typed value it evaluates to,
 // which might be an error, e.g. "2 / 3" or "contact.fields.age"
 func EvaluateExpression(env envs.Environment, ctx *types.XObject, expression string) types.XValue {
-	parsed, err := Parse(expression, nil)
+	return evaluateExpression(nil, env, ctx, nil, expression)
+}
+
+// EvaluateExpression is like the package level EvaluateExpression but uses this cache for parsed expressions, and
+// resolves names not found in the context using the given parent scope
+func (c *Cache) EvaluateExpression(env envs.Environment, ctx *types.XObject, parent *Scope, expression string) types.XValue {
+	return evaluateExpression(c, env, ctx, parent, expression)
+}
+
+func evaluateExpression(cache *Cache, env envs.Environment, ctx *types.XObject, parent *Scope, expression string) types.XValue {
+	parsed, err := cache.Parse(expression)
 	if err != nil {
 		return types.NewXError(err)
 	}
 
-	scope := NewScope(ctx, nil)
+	scope := NewScope(ctx, parent)
 
 	return parsed.Evaluate(env, scope)
 }
@@ -153,22 +177,21 @@ func resolveLookup(env envs.Environment, container types.XValue, lookup types.XV
 
 // VisitTemplate scans the given template and calls the callback for each token encountered
 func VisitTemplate(template string, allowedTopLevels []string, callback func(XTokenType, string) error) error {
-	// nothing todo for an empty template
-	if template == "" {
-		return nil
-	}
+	return visitTokens(scanTemplate(template, allowedTopLevels), callback)
+}
 
-	scanner := NewXScanner(strings.NewReader(template), allowedTopLevels)
+// calls the callback for each of the given tokens, collecting any errors returned
+func visitTokens(tokens []token, callback func(XTokenType, string) error) error {
 	errors := NewTemplateErrors()
 
-	for tokenType, token := scanner.Scan(); tokenType != EOF; tokenType, token = scanner.Scan() {
-		err := callback(tokenType, token)
+	for _, t := range tokens {
+		err := callback(t.tokenType, t.value)
 		if err != nil {
 			var repr string
-			if tokenType == IDENTIFIER {
-				repr = "@" + token
+			if t.tokenType == IDENTIFIER {
+				repr = "@" + t.value
 			} else {
-				repr = "@(" + token + ")"
+				repr = "@(" + t.value + ")"
 			}
 
 			errors.Add(repr, err.Error())
@@ -0,0 +1,145 @@
+package excellent
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// a token scanned from a template
+type token struct {
+	tokenType XTokenType
+	value     string
+}
+
+// a parsed expression or the error from parsing it
+type parsed struct {
+	expression Expression
+	err        error
+}
+
+// Cache is a bounded, concurrency-safe cache of parsed expressions and scanned templates, keyed by their source text.
+// Parsed expressions are immutable so can be shared between evaluations. A nil cache is valid and caches nothing.
+type Cache struct {
+	expressions *lru
+	templates   *lru
+}
+
+// NewCache creates a new cache which holds up to size parsed expressions and up to size scanned templates
+func NewCache(size int) *Cache {
+	return &Cache{expressions: newLRU(size), templates: newLRU(size)}
+}
+
+// Parse parses the given expression, returning the previously parsed expression if it's in the cache
+func (c *Cache) Parse(expression string) (Expression, error) {
+	if c == nil {
+		return Parse(expression, nil)
+	}
+
+	if cached, ok := c.expressions.get(expression); ok {
+		p := cached.(*parsed)
+		return p.expression, p.err
+	}
+
+	e, err := Parse(expression, nil)
+	c.expressions.add(expression, &parsed{expression: e, err: err})
+	return e, err
+}
+
+// Len returns the number of parsed expressions and scanned templates in the cache
+func (c *Cache) Len() (int, int) {
+	if c == nil {
+		return 0, 0
+	}
+	return c.expressions.len(), c.templates.len()
+}
+
+// scans the given template, returning the previously scanned tokens if it's in the cache
+func (c *Cache) scan(template string, allowedTopLevels []string) []token {
+	if c == nil {
+		return scanTemplate(template, allowedTopLevels)
+	}
+
+	// scanning depends on what top-level identifiers are allowed so that's part of the key
+	key := strings.Join(allowedTopLevels, ",") + "|" + template
+
+	if cached, ok := c.templates.get(key); ok {
+		return cached.([]token)
+	}
+
+	tokens := scanTemplate(template, allowedTopLevels)
+	c.templates.add(key, tokens)
+	return tokens
+}
+
+// scans all the tokens in the given template
+func scanTemplate(template string, allowedTopLevels []string) []token {
+	tokens := make([]token, 0)
+	if template == "" {
+		return tokens
+	}
+
+	scanner := NewXScanner(strings.NewReader(template), allowedTopLevels)
+	for tokenType, value := scanner.Scan(); tokenType != EOF; tokenType, value = scanner.Scan() {
+		tokens = append(tokens, token{tokenType: tokenType, value: value})
+	}
+	return tokens
+}
+
+// a least recently used cache of values by string keys
+type lru struct {
+	size  int
+	order *list.List
+	items map[string]*list.Element
+	mutex sync.Mutex
+}
+
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+func newLRU(size int) *lru {
+	return &lru{size: size, order: list.New(), items: make(map[string]*list.Element, size)}
+}
+
+func (c *lru) get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*lruEntry).value, true
+	}
+	return nil, false
+}
+
+func (c *lru) add(key string, value interface{}) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		c.order.MoveToFront(e)
+		e.Value.(*lruEntry).value = value
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lru) len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
@@ -0,0 +1,140 @@
+package excellent_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/excellent/types"
+	"github.com/developc3ntro/omni-goflow/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	env := envs.NewBuilder().Build()
+	ctx := types.NewXObject(map[string]types.XValue{
+		"name":    types.NewXText("Bob"),
+		"age":     types.NewXNumberFromInt(33),
+		"numbers": types.NewXArray(xi(1), xi(2), xi(3)),
+	})
+
+	cache := excellent.NewCache(100)
+
+	templates := []string{
+		"",
+		"Hello",
+		"Hi @name",
+		"Hi @name, you are @(age + 1) next year",
+		"@age",
+		"@(numbers[1])",
+		"@(foreach(numbers, (n) => n * 2))",
+		"@@name is escaped",
+		"email@example.com",
+		"@(age +)",
+		"@(1 / 0)",
+		"@xxx",
+		"@(",
+	}
+
+	// evaluate everything twice so that second time is from the cache
+	for i := 0; i < 2; i++ {
+		for _, template := range templates {
+			expected, expectedErr := excellent.EvaluateTemplate(env, ctx, template, nil)
+			actual, actualErr := cache.EvaluateTemplate(env, ctx, nil, template, nil)
+
+			assert.Equal(t, expected, actual, "output mismatch for template %s", template)
+			assert.Equal(t, fmt.Sprint(expectedErr), fmt.Sprint(actualErr), "error mismatch for template %s", template)
+
+			expectedValue, expectedErr := excellent.EvaluateTemplateValue(env, ctx, template)
+			actualValue, actualErr := cache.EvaluateTemplateValue(env, ctx, nil, template)
+
+			test.AssertXEqual(t, expectedValue, actualValue, "value mismatch for template %s", template)
+			assert.Equal(t, fmt.Sprint(expectedErr), fmt.Sprint(actualErr), "error mismatch for template %s", template)
+		}
+	}
+
+	numExpressions, numTemplates := cache.Len()
+	assert.Equal(t, 7, numExpressions)
+	assert.Equal(t, 13, numTemplates)
+
+	// parse errors are cached too
+	_, err := cache.Parse("(age +)")
+	assert.EqualError(t, err, "syntax error at )")
+	_, err = cache.Parse("(age +)")
+	assert.EqualError(t, err, "syntax error at )")
+
+	// a nil cache caches nothing
+	var nilCache *excellent.Cache
+	assert.Equal(t, xi(34), nilCache.EvaluateExpression(env, ctx, nil, "age + 1"))
+	numExpressions, numTemplates = nilCache.Len()
+	assert.Equal(t, 0, numExpressions)
+	assert.Equal(t, 0, numTemplates)
+}
+
+func TestCacheEviction(t *testing.T) {
+	env := envs.NewBuilder().Build()
+	ctx := types.NewXObject(map[string]types.XValue{"age": types.NewXNumberFromInt(33)})
+
+	cache := excellent.NewCache(3)
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, xi(33+i), cache.EvaluateExpression(env, ctx, nil, fmt.Sprintf("age + %d", i)))
+	}
+
+	numExpressions, _ := cache.Len()
+	assert.Equal(t, 3, numExpressions)
+
+	// a cache of size zero caches nothing
+	cache = excellent.NewCache(0)
+	assert.Equal(t, xi(34), cache.EvaluateExpression(env, ctx, nil, "age + 1"))
+
+	numExpressions, _ = cache.Len()
+	assert.Equal(t, 0, numExpressions)
+}
+
+func TestCacheConcurrency(t *testing.T) {
+	env := envs.NewBuilder().Build()
+	ctx := types.NewXObject(map[string]types.XValue{"age": types.NewXNumberFromInt(33)})
+
+	cache := excellent.NewCache(5)
+	wg := &sync.WaitGroup{}
+
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 100; i++ {
+				out, err := cache.EvaluateTemplate(env, ctx, nil, fmt.Sprintf("@(age + %d)", i%10), nil)
+				assert.NoError(t, err)
+				assert.Equal(t, fmt.Sprint(33+i%10), out)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkEvaluateTemplate(b *testing.B) {
+	benchmarkEvaluateTemplate(b, nil)
+}
+
+func BenchmarkEvaluateTemplateWithCache(b *testing.B) {
+	benchmarkEvaluateTemplate(b, excellent.NewCache(100))
+}
+
+func benchmarkEvaluateTemplate(b *testing.B, cache *excellent.Cache) {
+	env := envs.NewBuilder().Build()
+	ctx := types.NewXObject(map[string]types.XValue{
+		"name":    types.NewXText("Bob"),
+		"age":     types.NewXNumberFromInt(33),
+		"numbers": types.NewXArray(xi(1), xi(2), xi(3)),
+	})
+
+	for n := 0; n < b.N; n++ {
+		cache.EvaluateTemplate(env, ctx, nil, "Hi @name, you are @(age + 1) next year and your lucky number is @(numbers[1] * 7)", nil)
+	}
+}
@@ -0,0 +1,385 @@
+package excellent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/excellent/functions"
+	"github.com/developc3ntro/omni-goflow/excellent/types"
+)
+
+// Type is the statically inferred type of a value in an expression. Objects can have known properties, and if they
+// can also have other properties then those have the values type. Objects with no known properties and no values type
+// can have any properties.
+type Type struct {
+	Name       string
+	Properties map[string]*Type
+	Values     *Type
+	Items      *Type
+	Signature  *functions.Signature
+}
+
+// AnyType is the type of values which can't be inferred
+var AnyType = &Type{Name: functions.TypeAny}
+
+// NewType creates a new type which isn't an object, array or function
+func NewType(name string) *Type {
+	return &Type{Name: name}
+}
+
+// NewObjectType creates a new object type with the given known properties and type of other properties
+func NewObjectType(properties map[string]*Type, values *Type) *Type {
+	return &Type{Name: functions.TypeObject, Properties: properties, Values: values}
+}
+
+// NewArrayType creates a new array type with the given type of items
+func NewArrayType(items *Type) *Type {
+	return &Type{Name: functions.TypeArray, Items: items}
+}
+
+// NewFunctionType creates a new function type with the given signature which may be nil if it isn't known
+func NewFunctionType(signature *functions.Signature) *Type {
+	return &Type{Name: functions.TypeFunction, Signature: signature}
+}
+
+// creates the type for values returned by functions
+func newReturnType(name string) *Type {
+	switch name {
+	case functions.TypeAny, "":
+		return AnyType
+	case functions.TypeArray:
+		return NewArrayType(AnyType)
+	case functions.TypeObject:
+		return NewObjectType(nil, nil)
+	}
+	return NewType(name)
+}
+
+// kinds of problem that can be found by type checking
+const (
+	ProblemUnknownPath  = "unknown_path"
+	ProblemArgCount     = "arg_count"
+	ProblemTypeMismatch = "type_mismatch"
+)
+
+// Problem is a problem found by statically checking an expression
+type Problem struct {
+	Kind        string
+	Expression  string
+	Description string
+}
+
+// TypeCheck statically checks the given expression against the given type of the root context, calling the callback
+// for any unknown context paths, function calls with the wrong number of arguments and arguments or operands which
+// likely have the wrong type. Expressions which can't be parsed aren't checked.
+func TypeCheck(expression string, root *Type, callback func(*Problem)) {
+	parsed, err := Parse(expression, nil)
+	if err != nil {
+		return
+	}
+
+	c := &checker{root: root, report: callback}
+	c.check(parsed)
+}
+
+// TypeCheckTemplate statically checks each expression in the given template
+func TypeCheckTemplate(template string, allowedTopLevels []string, root *Type, callback func(*Problem)) {
+	VisitTemplate(template, allowedTopLevels, func(tokenType XTokenType, token string) error {
+		switch tokenType {
+		case IDENTIFIER, EXPRESSION:
+			TypeCheck(token, root, callback)
+		}
+		return nil
+	})
+}
+
+type checker struct {
+	root   *Type
+	locals []map[string]bool
+	report func(*Problem)
+}
+
+func (c *checker) problem(kind string, x Expression, format string, args ...interface{}) {
+	c.report(&Problem{Kind: kind, Expression: x.String(), Description: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) check(x Expression) *Type {
+	switch typed := x.(type) {
+	case *ContextReference:
+		return c.checkReference(typed, false)
+	case *DotLookup:
+		return c.checkLookup(typed, typed.container, c.check(typed.container), typed.lookup, true)
+	case *ArrayLookup:
+		container := c.check(typed.container)
+		c.check(typed.lookup)
+
+		if literal, isText := typed.lookup.(*TextLiteral); isText {
+			return c.checkLookup(typed, typed.container, container, literal.val.Native(), false)
+		}
+		return c.checkLookup(typed, typed.container, container, "", false)
+	case *FunctionCall:
+		return c.checkCall(typed)
+	case *AnonFunction:
+		args := make(map[string]bool, len(typed.args))
+		for _, a := range typed.args {
+			args[strings.ToLower(a)] = true
+		}
+		c.locals = append(c.locals, args)
+		c.check(typed.body)
+		c.locals = c.locals[:len(c.locals)-1]
+
+		return NewFunctionType(functions.FixedSignature(functions.TypeAny, repeatType(functions.TypeAny, len(typed.args))...))
+	case *Concatenation:
+		c.check(typed.exp1)
+		c.check(typed.exp2)
+		return NewType(functions.TypeText)
+	case *Addition:
+		return c.checkNumerical(typed, functions.TypeNumber, typed.exp1, typed.exp2)
+	case *Subtraction:
+		return c.checkNumerical(typed, functions.TypeNumber, typed.exp1, typed.exp2)
+	case *Multiplication:
+		return c.checkNumerical(typed, functions.TypeNumber, typed.exp1, typed.exp2)
+	case *Division:
+		return c.checkNumerical(typed, functions.TypeNumber, typed.exp1, typed.exp2)
+	case *Exponent:
+		return c.checkNumerical(typed, functions.TypeNumber, typed.expression, typed.exponent)
+	case *Negation:
+		return c.checkNumerical(typed, functions.TypeNumber, typed.exp)
+	case *Equality:
+		c.check(typed.exp1)
+		c.check(typed.exp2)
+		return NewType(functions.TypeBoolean)
+	case *InEquality:
+		c.check(typed.exp1)
+		c.check(typed.exp2)
+		return NewType(functions.TypeBoolean)
+	case *LessThan:
+		return c.checkNumerical(typed, functions.TypeBoolean, typed.exp1, typed.exp2)
+	case *LessThanOrEqual:
+		return c.checkNumerical(typed, functions.TypeBoolean, typed.exp1, typed.exp2)
+	case *GreaterThan:
+		return c.checkNumerical(typed, functions.TypeBoolean, typed.exp1, typed.exp2)
+	case *GreaterThanOrEqual:
+		return c.checkNumerical(typed, functions.TypeBoolean, typed.exp1, typed.exp2)
+	case *Parentheses:
+		return c.check(typed.exp)
+	case *TextLiteral:
+		return NewType(functions.TypeText)
+	case *NumberLiteral:
+		return NewType(functions.TypeNumber)
+	case *BooleanLiteral:
+		return NewType(functions.TypeBoolean)
+	}
+	return AnyType
+}
+
+// checks a reference to a top-level name. Unknown names which are called might be functions defined as assets, which
+// are checked as dependencies, so those aren't reported.
+func (c *checker) checkReference(x *ContextReference, called bool) *Type {
+	name := strings.ToLower(x.name)
+
+	for i := len(c.locals) - 1; i >= 0; i-- {
+		if c.locals[i][name] {
+			return AnyType
+		}
+	}
+
+	if t := c.root.property(name); t != nil {
+		return t
+	}
+
+	if functions.Lookup(name) != nil {
+		return NewFunctionType(functions.LookupSignature(name))
+	}
+
+	if !called {
+		c.problem(ProblemUnknownPath, x, "context has no property '%s'", name)
+	}
+	return AnyType
+}
+
+// checks a lookup of the given property on a value of the given type. Unlike dot lookups, array lookups of properties
+// which don't exist aren't errors, and if the property isn't a literal it will be empty.
+func (c *checker) checkLookup(x, containerX Expression, container *Type, property string, dot bool) *Type {
+	switch container.Name {
+	case functions.TypeObject:
+		if property == "" {
+			return container.valuesType()
+		}
+		if t := container.property(property); t != nil {
+			return t
+		}
+		if dot && container.isClosed() {
+			c.problem(ProblemUnknownPath, x, "%s has no property '%s'", containerX.String(), strings.ToLower(property))
+		}
+		return AnyType
+	case functions.TypeArray:
+		if dot && !isInteger(property) {
+			c.problem(ProblemUnknownPath, x, "%s has no property '%s'", containerX.String(), strings.ToLower(property))
+			return AnyType
+		}
+		if container.Items != nil {
+			return container.Items
+		}
+		return AnyType
+	case functions.TypeAny:
+		return AnyType
+	}
+
+	c.problem(ProblemTypeMismatch, x, "%s is %s which doesn't support lookups", containerX.String(), container.Name)
+	return AnyType
+}
+
+func (c *checker) checkCall(x *FunctionCall) *Type {
+	var function *Type
+	if ref, isRef := x.function.(*ContextReference); isRef {
+		function = c.checkReference(ref, true)
+	} else {
+		function = c.check(x.function)
+	}
+
+	args := make([]*Type, len(x.params))
+	for i, p := range x.params {
+		args[i] = c.check(p)
+	}
+
+	if function.Name == functions.TypeAny {
+		return AnyType
+	}
+	if function.Name != functions.TypeFunction {
+		c.problem(ProblemTypeMismatch, x, "%s is not a function", x.function.String())
+		return AnyType
+	}
+
+	sig := function.Signature
+	if sig == nil {
+		return AnyType
+	}
+
+	if !sig.Accepts(len(args)) {
+		c.problem(ProblemArgCount, x, "%s takes %s, got %d", x.function.String(), describeArgCount(sig), len(args))
+		return newReturnType(sig.Returns)
+	}
+
+	for i, p := range x.params {
+		expected := sig.ParamType(i)
+		if !isCompatible(p, args[i], expected) {
+			c.problem(ProblemTypeMismatch, x, "argument %d of %s should be %s, got %s", i+1, x.function.String(), expected, args[i].Name)
+		}
+	}
+
+	return newReturnType(sig.Returns)
+}
+
+// checks that the operands of a numerical operator can be numbers
+func (c *checker) checkNumerical(x Expression, result string, operands ...Expression) *Type {
+	for _, o := range operands {
+		t := c.check(o)
+		if !isCompatible(o, t, functions.TypeNumber) {
+			c.problem(ProblemTypeMismatch, x, "%s isn't a number", o.String())
+		}
+	}
+	return NewType(result)
+}
+
+// gets the type of the given property of an object or nil if it doesn't have that property
+func (t *Type) property(name string) *Type {
+	if p, exists := t.Properties[strings.ToLower(name)]; exists {
+		return p
+	}
+	if t.Values != nil {
+		return t.Values
+	}
+	if t.Properties == nil {
+		return AnyType
+	}
+	return nil
+}
+
+// gets the type of an object property whose name isn't known
+func (t *Type) valuesType() *Type {
+	if t.Values != nil && len(t.Properties) == 0 {
+		return t.Values
+	}
+	return AnyType
+}
+
+// whether this object type can't have properties besides its known properties
+func (t *Type) isClosed() bool {
+	return t.Properties != nil && t.Values == nil
+}
+
+// whether a value of the given type is likely to be convertible to the expected type
+func isCompatible(x Expression, actual *Type, expected string) bool {
+	if actual.Name == functions.TypeAny || expected == functions.TypeAny || actual.Name == expected {
+		return true
+	}
+
+	// unwrap parentheses to find literals
+	for {
+		if p, isParens := x.(*Parentheses); isParens {
+			x = p.exp
+		} else {
+			break
+		}
+	}
+
+	switch expected {
+	case functions.TypeText, functions.TypeBoolean:
+		return true
+	case functions.TypeNumber:
+		if literal, isText := x.(*TextLiteral); isText {
+			// env isn't used when converting text to a number
+			_, xerr := types.ToXNumber(nil, literal.val)
+			return xerr == nil
+		}
+		return actual.Name == functions.TypeText || actual.Name == functions.TypeObject
+	case functions.TypeDate, functions.TypeDateTime, functions.TypeTime:
+		switch actual.Name {
+		case functions.TypeText, functions.TypeObject, functions.TypeDate, functions.TypeDateTime, functions.TypeTime:
+			return true
+		}
+		return false
+	case functions.TypeArray, functions.TypeObject, functions.TypeFunction:
+		return false
+	}
+	return true
+}
+
+func isInteger(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+func describeArgCount(sig *functions.Signature) string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", n)
+	}
+
+	if sig.MaxArgs < 0 {
+		return "at least " + plural(sig.MinArgs)
+	}
+	if len(sig.ArgCounts) > 1 {
+		counts := make([]string, len(sig.ArgCounts)-1)
+		for i, n := range sig.ArgCounts[:len(counts)] {
+			counts[i] = strconv.Itoa(n)
+		}
+		return strings.Join(counts, ", ") + " or " + plural(sig.ArgCounts[len(counts)])
+	}
+	if sig.MinArgs == sig.MaxArgs {
+		return plural(sig.MinArgs)
+	}
+	return fmt.Sprintf("%d to %s", sig.MinArgs, plural(sig.MaxArgs))
+}
+
+func repeatType(name string, count int) []string {
+	names := make([]string, count)
+	for i := range names {
+		names[i] = name
+	}
+	return names
+}
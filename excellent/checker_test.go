@@ -0,0 +1,85 @@
+package excellent_test
+
+import (
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/excellent/functions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeCheck(t *testing.T) {
+	text := excellent.NewType(functions.TypeText)
+	number := excellent.NewType(functions.TypeNumber)
+
+	root := excellent.NewObjectType(map[string]*excellent.Type{
+		"name":    text,
+		"age":     number,
+		"tags":    excellent.NewArrayType(text),
+		"extra":   excellent.AnyType,
+		"fields":  excellent.NewObjectType(map[string]*excellent.Type{"dob": excellent.NewType(functions.TypeDate)}, text),
+		"address": excellent.NewObjectType(map[string]*excellent.Type{"city": text}, nil),
+		"meta":    excellent.NewObjectType(nil, nil),
+	}, nil)
+
+	tcs := []struct {
+		expression string
+		problems   []excellent.Problem
+	}{
+		{`name`, nil},
+		{`NAME & " " & age`, nil},
+		{`tags[0] & tags.1 & tags[age]`, nil},
+		{`extra.foo.bar[3].baz(1, 2)`, nil},
+		{`fields.dob & fields.anything & fields["x"]`, nil},
+		{`address.city & address["zip"]`, nil},
+		{`meta.anything.else`, nil},
+		{`upper(name) & word(name, 1) & max(age, 1, "2")`, nil},
+		{`foreach(tags, (t) => upper(t) & t.whatever)`, nil},
+		{`(age + "12") * -age ^ 2 >= 3`, nil},
+		{`format_date(fields.dob) & format_date("2022-01-01")`, nil},
+		{`(foo +`, nil},             // parse errors aren't reported
+		{`format_phone(name)`, nil}, // might be a function defined as an asset
+		{`foo`, []excellent.Problem{
+			{excellent.ProblemUnknownPath, `foo`, `context has no property 'foo'`},
+		}},
+		{`address.zip & tags.first`, []excellent.Problem{
+			{excellent.ProblemUnknownPath, `address.zip`, `address has no property 'zip'`},
+			{excellent.ProblemUnknownPath, `tags.first`, `tags has no property 'first'`},
+		}},
+		{`upper(name, 2) & word(name) & max()`, []excellent.Problem{
+			{excellent.ProblemArgCount, `upper(name, 2)`, `upper takes 1 argument, got 2`},
+			{excellent.ProblemArgCount, `word(name)`, `word takes 2 to 3 arguments, got 1`},
+			{excellent.ProblemArgCount, `max()`, `max takes at least 1 argument, got 0`},
+		}},
+		{`abs("ten") & join(name, ",") & name.first & age(1)`, []excellent.Problem{
+			{excellent.ProblemTypeMismatch, `abs("ten")`, `argument 1 of abs should be number, got text`},
+			{excellent.ProblemTypeMismatch, `join(name, ",")`, `argument 1 of join should be array, got text`},
+			{excellent.ProblemTypeMismatch, `name.first`, `name is text which doesn't support lookups`},
+			{excellent.ProblemTypeMismatch, `age(1)`, `age is not a function`},
+		}},
+		{`tags + 1`, []excellent.Problem{
+			{excellent.ProblemTypeMismatch, `tags + 1`, `tags isn't a number`},
+		}},
+	}
+
+	for _, tc := range tcs {
+		var problems []excellent.Problem
+		excellent.TypeCheck(tc.expression, root, func(p *excellent.Problem) { problems = append(problems, *p) })
+
+		assert.Equal(t, tc.problems, problems, "problems mismatch for expression %s", tc.expression)
+	}
+}
+
+func TestTypeCheckTemplate(t *testing.T) {
+	root := excellent.NewObjectType(map[string]*excellent.Type{"name": excellent.NewType(functions.TypeText)}, nil)
+
+	var problems []excellent.Problem
+	excellent.TypeCheckTemplate(`Hi @name, @nmae @(upper(nmae)) email@example.com`, []string{"name"}, root, func(p *excellent.Problem) {
+		problems = append(problems, *p)
+	})
+
+	assert.Equal(t, []excellent.Problem{
+		{excellent.ProblemUnknownPath, `nmae`, `context has no property 'nmae'`},
+	}, problems)
+}
@@ -0,0 +1,186 @@
+package excellent
+
+import (
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent/functions"
+	"github.com/developc3ntro/omni-goflow/excellent/types"
+)
+
+// FunctionDefinition is the definition of a function whose body is an expression
+type FunctionDefinition interface {
+	Name() string
+	Parameters() []string
+	Body() string
+}
+
+// Functions is a set of functions defined by expressions, which can be called from other expressions. These are
+// resolved after builtin functions so can't replace them.
+type Functions struct {
+	byName map[string]*definedFunction
+}
+
+type definedFunction struct {
+	name   string
+	params []string
+	body   Expression
+	err    error
+}
+
+// NewFunctions creates a new set of functions from the given definitions, parsing their bodies
+func NewFunctions(defs []FunctionDefinition) *Functions {
+	f := &Functions{byName: make(map[string]*definedFunction, len(defs))}
+
+	for _, d := range defs {
+		body, err := Parse(d.Body(), nil)
+
+		f.byName[strings.ToLower(d.Name())] = &definedFunction{name: d.Name(), params: d.Parameters(), body: body, err: err}
+	}
+	return f
+}
+
+// Scope returns a scope in which builtin functions and these functions can be called, for use as the parent scope of
+// a context. Calls to these functions can't be nested deeper than maxDepth, which also limits recursion, and there
+// can't be more than maxCalls calls in total to these functions from expressions evaluated in the returned scope.
+func (f *Functions) Scope(maxDepth, maxCalls int) *Scope {
+	if f == nil || len(f.byName) == 0 {
+		return rootScope
+	}
+	return f.scope(0, &callLimits{maxDepth: maxDepth, maxCalls: maxCalls})
+}
+
+// the limits on calls to defined functions, shared by all the scopes of an evaluation
+type callLimits struct {
+	maxDepth int
+	maxCalls int
+	calls    int
+}
+
+func (f *Functions) scope(depth int, limits *callLimits) *Scope {
+	return &Scope{
+		get: func(name string) (types.XValue, bool) {
+			// builtin functions take precedence
+			if function := functions.Lookup(name); function != nil {
+				return function, true
+			}
+
+			if d := f.byName[strings.ToLower(name)]; d != nil {
+				return f.function(d, depth, limits), true
+			}
+			return nil, false
+		},
+	}
+}
+
+// creates a callable function which evaluates the body of the given defined function at the given depth
+func (f *Functions) function(d *definedFunction, depth int, limits *callLimits) *types.XFunction {
+	fn := func(env envs.Environment, args ...types.XValue) types.XValue {
+		if d.err != nil {
+			return types.NewXError(d.err)
+		}
+		if depth >= limits.maxDepth {
+			return types.NewXErrorf("maximum function call depth of %d exceeded", limits.maxDepth)
+		}
+
+		// limit the total number of calls too, as a body which calls itself more than once would otherwise grow
+		// exponentially with the depth
+		if limits.calls >= limits.maxCalls {
+			return types.NewXErrorf("maximum of %d function calls exceeded", limits.maxCalls)
+		}
+		limits.calls++
+
+		argsMap := make(map[string]types.XValue, len(d.params))
+		for i := range d.params {
+			argsMap[d.params[i]] = args[i]
+		}
+
+		// bodies can only access their arguments and other functions, and not the context they're called from
+		return d.body.Evaluate(env, NewScope(types.NewXObject(argsMap), f.scope(depth+1, limits)))
+	}
+
+	return types.NewXFunction(d.name, functions.NumArgsCheck(len(d.params), fn))
+}
+
+// FindFunctionCalls calls the callback with the name of each function called by name in the given expression, which
+// isn't a builtin function or an argument of an anonymous function. Expressions which can't be parsed are ignored.
+func FindFunctionCalls(expression string, callback func(string)) {
+	parsed, err := Parse(expression, nil)
+	if err != nil {
+		return
+	}
+
+	findFunctionCalls(parsed, nil, callback)
+}
+
+func findFunctionCalls(x Expression, locals []string, callback func(string)) {
+	switch typed := x.(type) {
+	case *FunctionCall:
+		if ref, isRef := typed.function.(*ContextReference); isRef {
+			name := strings.ToLower(ref.name)
+			if functions.Lookup(name) == nil && !isLocal(name, locals) {
+				callback(name)
+			}
+		}
+	case *AnonFunction:
+		for _, a := range typed.args {
+			locals = append(locals, strings.ToLower(a))
+		}
+	}
+
+	for _, c := range children(x) {
+		findFunctionCalls(c, locals, callback)
+	}
+}
+
+func isLocal(name string, locals []string) bool {
+	for _, l := range locals {
+		if l == name {
+			return true
+		}
+	}
+	return false
+}
+
+// gets the child expressions of the given expression
+func children(x Expression) []Expression {
+	switch typed := x.(type) {
+	case *DotLookup:
+		return []Expression{typed.container}
+	case *ArrayLookup:
+		return []Expression{typed.container, typed.lookup}
+	case *FunctionCall:
+		return append([]Expression{typed.function}, typed.params...)
+	case *AnonFunction:
+		return []Expression{typed.body}
+	case *Concatenation:
+		return []Expression{typed.exp1, typed.exp2}
+	case *Addition:
+		return []Expression{typed.exp1, typed.exp2}
+	case *Subtraction:
+		return []Expression{typed.exp1, typed.exp2}
+	case *Multiplication:
+		return []Expression{typed.exp1, typed.exp2}
+	case *Division:
+		return []Expression{typed.exp1, typed.exp2}
+	case *Exponent:
+		return []Expression{typed.expression, typed.exponent}
+	case *Negation:
+		return []Expression{typed.exp}
+	case *Equality:
+		return []Expression{typed.exp1, typed.exp2}
+	case *InEquality:
+		return []Expression{typed.exp1, typed.exp2}
+	case *LessThan:
+		return []Expression{typed.exp1, typed.exp2}
+	case *LessThanOrEqual:
+		return []Expression{typed.exp1, typed.exp2}
+	case *GreaterThan:
+		return []Expression{typed.exp1, typed.exp2}
+	case *GreaterThanOrEqual:
+		return []Expression{typed.exp1, typed.exp2}
+	case *Parentheses:
+		return []Expression{typed.exp}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package excellent_test
+
+import (
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/assets/static"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/excellent/types"
+	"github.com/developc3ntro/omni-goflow/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctions(t *testing.T) {
+	env := envs.NewBuilder().Build()
+	ctx := types.NewXObject(map[string]types.XValue{
+		"name": types.NewXText("Bob"),
+	})
+
+	fns := excellent.NewFunctions([]excellent.FunctionDefinition{
+		static.NewFunction("count_down", []string{"n"}, `if(n <= 0, "", n & count_down(n - 1))`),
+		static.NewFunction("Greet", []string{"greeting", "who"}, `greeting & " " & upper(who) & "!"`),
+		static.NewFunction("shout", []string{"text"}, `greet("HEY", text)`),
+		static.NewFunction("forever", []string{}, `forever()`),
+		static.NewFunction("uses_context", []string{}, `name`),
+		static.NewFunction("upper", []string{"text"}, `"not the builtin"`),
+		static.NewFunction("broken", []string{}, `1 +`),
+		static.NewFunction("fan_out", []string{"n"}, `if(n <= 0, 1, fan_out(n - 1) + fan_out(n - 1) + fan_out(n - 1))`),
+		static.NewFunction("triple3", []string{"n"}, `triple2(n) + triple2(n) + triple2(n)`),
+		static.NewFunction("triple2", []string{"n"}, `triple1(n) + triple1(n) + triple1(n)`),
+		static.NewFunction("triple1", []string{"n"}, `n + n + n`),
+	})
+	tcs := []struct {
+		expression string
+		expected   types.XValue
+	}{
+		{`greet("Hi", name)`, xs("Hi BOB!")},
+		{`GREET("Hi", "Ann")`, xs("Hi ANN!")},
+		{`shout(name)`, xs("HEY BOB!")},
+		{`foreach(array("a", "b"), shout)`, types.NewXArray(xs("HEY A!"), xs("HEY B!"))},
+		{`upper(name)`, xs("BOB")},
+		{`name`, xs("Bob")},
+		{`count_down(3)`, xs("321")},
+		{`triple3(1)`, xi(27)},
+		{`greet("Hi")`, ERROR},
+		{`forever()`, ERROR},
+		{`uses_context()`, ERROR},
+		{`broken()`, ERROR},
+		{`fan_out(2)`, ERROR}, // if evaluates both branches so this would make more than 3^10 calls without a limit
+		{`unknown()`, ERROR},
+	}
+
+	cache := excellent.NewCache(10)
+
+	for _, tc := range tcs {
+		actual := cache.EvaluateExpression(env, ctx, fns.Scope(10, 100), tc.expression)
+
+		if tc.expected == ERROR {
+			assert.True(t, types.IsXError(actual), "expecting error, got %T{%s} evaluating expression '%s'", actual, actual, tc.expression)
+		} else {
+			test.AssertXEqual(t, tc.expected, actual, "result mismatch for expression %s", tc.expression)
+		}
+	}
+
+	assert.EqualError(t, cache.EvaluateExpression(env, ctx, fns.Scope(10, 100), `greet("Hi")`).(error), "error calling Greet(...): need 2 argument(s), got 1")
+	assert.EqualError(t, cache.EvaluateExpression(env, ctx, excellent.NewFunctions(nil).Scope(10, 100), `shout(name)`).(error), "context has no property 'shout'")
+	assert.EqualError(t, cache.EvaluateExpression(env, ctx, fns.Scope(1, 100), `shout(name)`).(error), "error calling shout(...): error calling Greet(...): maximum function call depth of 1 exceeded")
+
+	// bodies which call functions more than once are limited by the total number of calls, not just the depth
+	assert.EqualError(t, cache.EvaluateExpression(env, ctx, fns.Scope(10, 12), `triple3(1)`).(error), "error calling triple3(...): error calling triple2(...): error calling triple1(...): maximum of 12 function calls exceeded")
+	test.AssertXEqual(t, xi(27), cache.EvaluateExpression(env, ctx, fns.Scope(10, 13), `triple3(1)`))
+
+	// and the budget is shared by everything evaluated in the same scope
+	scope := fns.Scope(10, 30)
+	test.AssertXEqual(t, xi(27), cache.EvaluateExpression(env, ctx, scope, `triple3(1)`))
+	test.AssertXEqual(t, xi(27), cache.EvaluateExpression(env, ctx, scope, `triple3(1)`))
+	assert.True(t, types.IsXError(cache.EvaluateExpression(env, ctx, scope, `triple3(1)`)))
+
+	// a nil set of functions only has builtins
+	var nilFns *excellent.Functions
+	test.AssertXEqual(t, xs("BOB"), cache.EvaluateExpression(env, ctx, nilFns.Scope(10, 100), `upper(name)`))
+
+	out, err := cache.EvaluateTemplate(env, ctx, fns.Scope(10, 100), `@(greet("Hello", name)) @(count_down(5))`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello BOB! 54321", out)
+}
+
+func TestFindFunctionCalls(t *testing.T) {
+	tcs := []struct {
+		expression string
+		calls      []string
+	}{
+		{`name`, nil},
+		{`upper(name) & LOWER(name)`, nil},
+		{`Foo(name) & bar(1, baz(2)) & x.foo(3)`, []string{"foo", "bar", "baz"}},
+		{`foreach(names, (n) => n(1) & foo(n))`, []string{"foo"}},
+		{`foo(`, nil},
+	}
+
+	for _, tc := range tcs {
+		var calls []string
+		excellent.FindFunctionCalls(tc.expression, func(name string) { calls = append(calls, name) })
+
+		assert.Equal(t, tc.calls, calls, "calls mismatch for expression %s", tc.expression)
+	}
+}
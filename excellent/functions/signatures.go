@@ -0,0 +1,216 @@
+package functions
+
+import (
+	"strings"
+)
+
+// the static types used in function signatures
+const (
+	TypeAny      = "any"
+	TypeText     = "text"
+	TypeNumber   = "number"
+	TypeBoolean  = "boolean"
+	TypeDate     = "date"
+	TypeDateTime = "datetime"
+	TypeTime     = "time"
+	TypeArray    = "array"
+	TypeObject   = "object"
+	TypeFunction = "function"
+)
+
+// Signature describes the arguments a function accepts and the type of value it returns, for use in static checking
+// of expressions. It doesn't affect how the function is called.
+type Signature struct {
+	Params    []string // the types of the params, with the last repeated if the function takes any number of args
+	MinArgs   int
+	MaxArgs   int   // or -1 if there's no maximum
+	ArgCounts []int // or nil if any number of args between the minimum and maximum is accepted
+	Returns   string
+}
+
+// Accepts returns whether a function with this signature can be called with the given number of args
+func (s *Signature) Accepts(numArgs int) bool {
+	if len(s.ArgCounts) > 0 {
+		for _, n := range s.ArgCounts {
+			if n == numArgs {
+				return true
+			}
+		}
+		return false
+	}
+	return numArgs >= s.MinArgs && (s.MaxArgs < 0 || numArgs <= s.MaxArgs)
+}
+
+// ParamType returns the type of the param at the given index
+func (s *Signature) ParamType(i int) string {
+	if len(s.Params) == 0 {
+		return TypeAny
+	}
+	if i >= len(s.Params) {
+		return s.Params[len(s.Params)-1]
+	}
+	return s.Params[i]
+}
+
+// FixedSignature creates a signature for a function which takes a fixed number of args
+func FixedSignature(returns string, params ...string) *Signature {
+	return &Signature{Params: params, MinArgs: len(params), MaxArgs: len(params), Returns: returns}
+}
+
+// OptionalSignature creates a signature for a function whose params after the first min are optional
+func OptionalSignature(min int, returns string, params ...string) *Signature {
+	return &Signature{Params: params, MinArgs: min, MaxArgs: len(params), Returns: returns}
+}
+
+// AlternativeSignature creates a signature for a function which takes one of the given numbers of args, in ascending order
+func AlternativeSignature(counts []int, returns string, params ...string) *Signature {
+	return &Signature{Params: params, MinArgs: counts[0], MaxArgs: counts[len(counts)-1], ArgCounts: counts, Returns: returns}
+}
+
+// VariadicSignature creates a signature for a function which takes at least min args
+func VariadicSignature(min int, returns string, params ...string) *Signature {
+	return &Signature{Params: params, MinArgs: min, MaxArgs: -1, Returns: returns}
+}
+
+var fixed, optional, variadic = FixedSignature, OptionalSignature, VariadicSignature
+
+var signatures = map[string]*Signature{
+	// type conversion
+	"text":     fixed(TypeText, TypeAny),
+	"boolean":  fixed(TypeBoolean, TypeAny),
+	"number":   fixed(TypeNumber, TypeAny),
+	"date":     fixed(TypeDate, TypeAny),
+	"datetime": fixed(TypeDateTime, TypeAny),
+	"time":     fixed(TypeTime, TypeAny),
+	"array":    variadic(0, TypeArray, TypeAny),
+	"object":   variadic(0, TypeObject, TypeAny),
+
+	// text functions
+	"char":              fixed(TypeText, TypeNumber),
+	"code":              fixed(TypeNumber, TypeText),
+	"split":             optional(1, TypeArray, TypeText, TypeText),
+	"trim":              optional(1, TypeText, TypeText, TypeText),
+	"trim_left":         optional(1, TypeText, TypeText, TypeText),
+	"trim_right":        optional(1, TypeText, TypeText, TypeText),
+	"title":             fixed(TypeText, TypeText),
+	"word":              optional(2, TypeText, TypeText, TypeNumber, TypeText),
+	"remove_first_word": fixed(TypeText, TypeText),
+	"word_count":        optional(1, TypeNumber, TypeText, TypeText),
+	"word_slice":        optional(2, TypeText, TypeText, TypeNumber, TypeNumber, TypeText),
+	"field":             fixed(TypeText, TypeText, TypeNumber, TypeText),
+	"clean":             fixed(TypeText, TypeText),
+	"text_slice":        optional(2, TypeText, TypeText, TypeNumber, TypeNumber, TypeAny),
+	"lower":             fixed(TypeText, TypeText),
+	"regex_match":       optional(2, TypeText, TypeText, TypeText, TypeNumber),
+	"text_length":       fixed(TypeNumber, TypeText),
+	"text_compare":      fixed(TypeNumber, TypeText, TypeText),
+	"repeat":            fixed(TypeText, TypeText, TypeNumber),
+	"replace":           optional(3, TypeText, TypeText, TypeText, TypeText, TypeNumber),
+	"upper":             fixed(TypeText, TypeText),
+	"percent":           fixed(TypeText, TypeNumber),
+	"url_encode":        fixed(TypeText, TypeText),
+	"url_decode":        fixed(TypeText, TypeText),
+	"html_encode":       fixed(TypeText, TypeText),
+	"html_decode":       fixed(TypeText, TypeText),
+
+	// bool functions
+	"and": variadic(1, TypeBoolean, TypeAny),
+	"if":  fixed(TypeAny, TypeAny, TypeAny, TypeAny),
+	"or":  variadic(1, TypeBoolean, TypeAny),
+
+	// number functions
+	"round":        optional(1, TypeNumber, TypeNumber, TypeNumber),
+	"round_up":     optional(1, TypeNumber, TypeNumber, TypeNumber),
+	"round_down":   optional(1, TypeNumber, TypeNumber, TypeNumber),
+	"max":          variadic(1, TypeNumber, TypeNumber),
+	"min":          variadic(1, TypeNumber, TypeNumber),
+	"mean":         variadic(1, TypeNumber, TypeNumber),
+	"mod":          fixed(TypeNumber, TypeNumber, TypeNumber),
+	"rand":         fixed(TypeNumber),
+	"rand_between": fixed(TypeNumber, TypeNumber, TypeNumber),
+	"abs":          fixed(TypeNumber, TypeNumber),
+
+	// datetime functions
+	"parse_datetime":      optional(2, TypeDateTime, TypeText, TypeText, TypeText),
+	"datetime_from_epoch": fixed(TypeDateTime, TypeNumber),
+	"datetime_diff":       fixed(TypeNumber, TypeDateTime, TypeDateTime, TypeText),
+	"datetime_add":        fixed(TypeDateTime, TypeDateTime, TypeNumber, TypeText),
+	"replace_time":        fixed(TypeDateTime, TypeDateTime, TypeTime),
+	"tz":                  fixed(TypeText, TypeDateTime),
+	"tz_offset":           fixed(TypeText, TypeDateTime),
+	"now":                 fixed(TypeDateTime),
+	"epoch":               fixed(TypeNumber, TypeDateTime),
+
+	// date functions
+	"date_from_parts": fixed(TypeDate, TypeNumber, TypeNumber, TypeNumber),
+	"weekday":         fixed(TypeNumber, TypeDate),
+	"week_number":     fixed(TypeNumber, TypeDate),
+	"today":           fixed(TypeDate),
+
+	// time functions
+	"parse_time":      fixed(TypeTime, TypeText, TypeText),
+	"time_from_parts": fixed(TypeTime, TypeNumber, TypeNumber, TypeNumber),
+
+	// array functions
+	"join":     fixed(TypeText, TypeArray, TypeText),
+	"reverse":  fixed(TypeArray, TypeArray),
+	"sort":     fixed(TypeArray, TypeArray),
+	"sum":      fixed(TypeNumber, TypeArray),
+	"unique":   fixed(TypeArray, TypeArray),
+	"concat":   fixed(TypeArray, TypeArray, TypeArray),
+	"filter":   variadic(2, TypeArray, TypeArray, TypeFunction, TypeAny),
+	"map":      variadic(2, TypeArray, TypeArray, TypeFunction, TypeAny),
+	"sort_by":  fixed(TypeArray, TypeArray, TypeAny),
+	"group_by": fixed(TypeObject, TypeArray, TypeAny),
+	"slice":    optional(2, TypeArray, TypeArray, TypeNumber, TypeNumber),
+	"index_of": fixed(TypeNumber, TypeArray, TypeAny),
+	"flatten":  fixed(TypeArray, TypeArray),
+	"zip":      fixed(TypeArray, TypeArray, TypeArray),
+
+	// encoded text functions
+	"urn_parts":        fixed(TypeObject, TypeText),
+	"attachment_parts": fixed(TypeObject, TypeText),
+	"base64_encode":    fixed(TypeText, TypeText),
+	"base64_decode":    fixed(TypeText, TypeText),
+	"hex":              fixed(TypeText, TypeText),
+	"hash":             optional(1, TypeText, TypeText, TypeText),
+	"hmac":             optional(2, TypeText, TypeText, TypeText, TypeText),
+	"uuid_v5":          fixed(TypeText, TypeText, TypeText),
+	"jwt_decode":       fixed(TypeObject, TypeText),
+
+	// json functions
+	"json":       fixed(TypeText, TypeAny),
+	"parse_json": fixed(TypeAny, TypeText),
+
+	// formatting functions
+	"format":          fixed(TypeText, TypeAny),
+	"format_date":     optional(1, TypeText, TypeDate, TypeText),
+	"format_datetime": optional(1, TypeText, TypeDateTime, TypeText, TypeText),
+	"format_time":     optional(1, TypeText, TypeTime, TypeText),
+	"format_location": fixed(TypeText, TypeText),
+	"format_number":   optional(1, TypeText, TypeNumber, TypeNumber, TypeAny),
+	"format_urn":      fixed(TypeText, TypeText),
+
+	// utility functions
+	"is_error":       fixed(TypeBoolean, TypeAny),
+	"count":          fixed(TypeNumber, TypeAny),
+	"default":        fixed(TypeAny, TypeAny, TypeAny),
+	"legacy_add":     fixed(TypeAny, TypeAny, TypeAny),
+	"read_chars":     fixed(TypeText, TypeText),
+	"extract":        fixed(TypeAny, TypeObject, TypeText),
+	"extract_object": variadic(2, TypeObject, TypeObject, TypeText),
+	"foreach":        variadic(2, TypeArray, TypeArray, TypeFunction, TypeAny),
+	"foreach_value":  variadic(2, TypeObject, TypeObject, TypeFunction, TypeAny),
+	"keys":           fixed(TypeArray, TypeObject),
+	"values":         fixed(TypeArray, TypeObject),
+}
+
+// RegisterSignature registers the signature of a function so that calls to it can be statically checked
+func RegisterSignature(name string, s *Signature) {
+	signatures[name] = s
+}
+
+// LookupSignature returns the signature of the function with the given name (case-insensitive) or nil
+func LookupSignature(name string) *Signature {
+	return signatures[strings.ToLower(name)]
+}
@@ -0,0 +1,56 @@
+package functions_test
+
+import (
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent/functions"
+	"github.com/developc3ntro/omni-goflow/excellent/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignatures(t *testing.T) {
+	env := envs.NewBuilder().Build()
+
+	callWithArgs := func(f *types.XFunction, num int) types.XValue {
+		args := make([]types.XValue, num)
+		for i := range args {
+			args[i] = types.NewXText("")
+		}
+		return f.Call(env, args)
+	}
+
+	for name, f := range functions.XFUNCTIONS {
+		sig := functions.LookupSignature(name)
+		if !assert.NotNil(t, sig, "missing signature for function %s", name) {
+			continue
+		}
+
+		// check that the argument counts match what the function actually accepts
+		if sig.MinArgs > 0 {
+			assert.Regexp(t, `argument`, callWithArgs(f, sig.MinArgs-1), "expected error calling %s with %d args", name, sig.MinArgs-1)
+		}
+		if sig.MaxArgs >= 0 {
+			assert.Regexp(t, `argument`, callWithArgs(f, sig.MaxArgs+1), "expected error calling %s with %d args", name, sig.MaxArgs+1)
+		}
+		for n := sig.MinArgs; n < sig.MaxArgs; n++ {
+			if !sig.Accepts(n) {
+				assert.Regexp(t, `argument`, callWithArgs(f, n), "expected error calling %s with %d args", name, n)
+			}
+		}
+
+		for i := 0; i < sig.MinArgs; i++ {
+			assert.NotEqual(t, "", sig.ParamType(i))
+		}
+	}
+
+	assert.Equal(t, functions.LookupSignature("upper"), functions.LookupSignature("UPPER"))
+	assert.Nil(t, functions.LookupSignature("xxx"))
+
+	// params of variadic functions repeat the last type
+	sig := functions.LookupSignature("max")
+	assert.Equal(t, functions.TypeNumber, sig.ParamType(0))
+	assert.Equal(t, functions.TypeNumber, sig.ParamType(5))
+	assert.Equal(t, -1, sig.MaxArgs)
+}
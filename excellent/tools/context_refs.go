@@ -1,6 +1,8 @@
 package tools
 
 import (
+	"strings"
+
 	"github.com/developc3ntro/omni-goflow/excellent"
 	"github.com/developc3ntro/omni-goflow/excellent/functions"
 )
@@ -23,3 +25,25 @@ func FindContextRefsInTemplate(template string, allowedTopLevels []string, callb
 		return nil
 	})
 }
+
+// FindFunctionCallsInTemplate audits function calls in the given template, calling the callback with the
+// name of each function called which isn't a builtin function or a context value
+func FindFunctionCallsInTemplate(template string, allowedTopLevels []string, callback func(string)) error {
+	// wrap callback to exclude calls of context values
+	wrapped := func(name string) {
+		for _, t := range allowedTopLevels {
+			if strings.EqualFold(name, t) {
+				return
+			}
+		}
+		callback(name)
+	}
+
+	return excellent.VisitTemplate(template, allowedTopLevels, func(tokenType excellent.XTokenType, token string) error {
+		switch tokenType {
+		case excellent.IDENTIFIER, excellent.EXPRESSION:
+			excellent.FindFunctionCalls(token, wrapped)
+		}
+		return nil
+	})
+}
@@ -42,3 +42,27 @@ func TestFindContextRefsInTemplate(t *testing.T) {
 		assert.Equal(t, tc.paths, actual, "audit context mismatch for input: %s", tc.template)
 	}
 }
+
+func TestFindFunctionCallsInTemplate(t *testing.T) {
+	testCases := []struct {
+		template string
+		calls    []string
+	}{
+		{``, []string{}},
+		{`Hi @foo @(upper(foo))`, []string{}},
+		{`@(format_phone(foo.bar)) @(FORMAT_ID(foo) & foo(1))`, []string{`format_phone`, `format_id`}},
+		{`@(foreach(foo, (f) => f(1) & bar(f)))`, []string{`bar`}},
+		{`@(format_phone(`, []string{}},
+	}
+
+	for _, tc := range testCases {
+		actual := make([]string, 0)
+
+		err := tools.FindFunctionCallsInTemplate(tc.template, []string{"foo"}, func(name string) {
+			actual = append(actual, name)
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.calls, actual, "function calls mismatch for input: %s", tc.template)
+	}
+}
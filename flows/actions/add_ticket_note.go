@@ -0,0 +1,68 @@
+package actions
+
+import (
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/events"
+)
+
+func init() {
+	registerType(TypeAddTicketNote, func() flows.Action { return &AddTicketNoteAction{} })
+}
+
+// TypeAddTicketNote is the type for the add ticket note action
+const TypeAddTicketNote string = "add_ticket_note"
+
+// AddTicketNoteAction is used to add an internal note to the contact's current ticket, i.e. `@ticket`. A
+// [event:ticket_note_added] event will be created if the note is added successfully.
+//
+//	{
+//	  "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//	  "type": "add_ticket_note",
+//	  "note": "Contact said: @input.text"
+//	}
+//
+// @action add_ticket_note
+type AddTicketNoteAction struct {
+	baseAction
+	onlineAction
+
+	Note string `json:"note" validate:"required" engine:"evaluated"`
+}
+
+// NewAddTicketNote creates a new add ticket note action
+func NewAddTicketNote(uuid flows.ActionUUID, note string) *AddTicketNoteAction {
+	return &AddTicketNoteAction{
+		baseAction: newBaseAction(TypeAddTicketNote, uuid),
+		Note:       note,
+	}
+}
+
+// Execute runs this action
+func (a *AddTicketNoteAction) Execute(run flows.Run, step flows.Step, logModifier flows.ModifierCallback, logEvent flows.EventCallback) error {
+	ticket := currentTicket(run, logEvent)
+	if ticket == nil {
+		return nil
+	}
+
+	evaluatedNote, err := run.EvaluateTemplate(a.Note)
+	if err != nil {
+		logEvent(events.NewError(err))
+	}
+	evaluatedNote = strings.TrimSpace(evaluatedNote)
+
+	if evaluatedNote == "" {
+		logEvent(events.NewErrorf("ticket note evaluated to empty string, skipping"))
+		return nil
+	}
+
+	added := callTicketService(run, ticket, logEvent, func(svc flows.TicketService, logHTTP flows.HTTPLogCallback) error {
+		return svc.AddNote(run.Session(), ticket, evaluatedNote, logHTTP)
+	})
+	if added {
+		logEvent(events.NewTicketNoteAdded(ticket, evaluatedNote))
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package actions
+
+import (
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/events"
+)
+
+func init() {
+	registerType(TypeAssignTicket, func() flows.Action { return &AssignTicketAction{} })
+}
+
+// TypeAssignTicket is the type for the assign ticket action
+const TypeAssignTicket string = "assign_ticket"
+
+// AssignTicketAction is used to assign the contact's current ticket, i.e. `@ticket`, to a different user. A
+// [event:ticket_assigned] event will be created if the ticket is assigned successfully.
+//
+//	{
+//	  "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//	  "type": "assign_ticket",
+//	  "assignee": {"email": "bob@nyaruka.com", "name": "Bob McTickets"}
+//	}
+//
+// @action assign_ticket
+type AssignTicketAction struct {
+	baseAction
+	onlineAction
+
+	Assignee *assets.UserReference `json:"assignee" validate:"required,dive"`
+}
+
+// NewAssignTicket creates a new assign ticket action
+func NewAssignTicket(uuid flows.ActionUUID, assignee *assets.UserReference) *AssignTicketAction {
+	return &AssignTicketAction{
+		baseAction: newBaseAction(TypeAssignTicket, uuid),
+		Assignee:   assignee,
+	}
+}
+
+// Execute runs this action
+func (a *AssignTicketAction) Execute(run flows.Run, step flows.Step, logModifier flows.ModifierCallback, logEvent flows.EventCallback) error {
+	ticket := currentTicket(run, logEvent)
+	if ticket == nil {
+		return nil
+	}
+
+	assignee := resolveUser(run, a.Assignee, logEvent)
+	if assignee == nil {
+		return nil
+	}
+	if ticket.Assignee() == assignee {
+		return nil
+	}
+
+	assigned := callTicketService(run, ticket, logEvent, func(svc flows.TicketService, logHTTP flows.HTTPLogCallback) error {
+		return svc.Assign(run.Session(), ticket, assignee, logHTTP)
+	})
+	if assigned {
+		ticket.SetAssignee(assignee)
+		logEvent(events.NewTicketAssigned(ticket))
+	}
+
+	return nil
+}
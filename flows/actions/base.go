@@ -380,6 +380,51 @@ func resolveUser(run flows.Run, ref *assets.UserReference, logEvent flows.EventC
 	return user
 }
 
+// helper function to get the ticket that ticket actions operate on, i.e. the contact's last ticket which is also @ticket
+func currentTicket(run flows.Run, logEvent flows.EventCallback) *flows.Ticket {
+	if run.Contact() == nil {
+		logEvent(events.NewErrorf("can't execute action in session without a contact"))
+		return nil
+	}
+	if run.Session().BatchStart() {
+		logEvent(events.NewErrorf("can't update tickets during batch starts"))
+		return nil
+	}
+
+	ticket := run.Contact().Tickets().Last()
+	if ticket == nil {
+		logEvent(events.NewErrorf("contact has no tickets"))
+		return nil
+	}
+	if ticket.Ticketer() == nil {
+		logEvent(events.NewErrorf("ticketer of ticket %s no longer exists", ticket.UUID()))
+		return nil
+	}
+	return ticket
+}
+
+// helper function to make a call to the ticket service for the given ticket, returning whether it was successful
+func callTicketService(run flows.Run, ticket *flows.Ticket, logEvent flows.EventCallback, call func(flows.TicketService, flows.HTTPLogCallback) error) bool {
+	svc, err := run.Session().Engine().Services().Ticket(run.Session(), ticket.Ticketer())
+	if err != nil {
+		logEvent(events.NewError(err))
+		return false
+	}
+
+	httpLogger := &flows.HTTPLogger{}
+
+	err = call(svc, httpLogger.Log)
+
+	if len(httpLogger.Logs) > 0 {
+		logEvent(events.NewTicketerCalled(ticket.Ticketer().Reference(), httpLogger.Logs))
+	}
+	if err != nil {
+		logEvent(events.NewError(err))
+		return false
+	}
+	return true
+}
+
 //------------------------------------------------------------------------------------------
 // JSON Encoding / Decoding
 //------------------------------------------------------------------------------------------
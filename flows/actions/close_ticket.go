@@ -0,0 +1,60 @@
+package actions
+
+import (
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/events"
+	"github.com/developc3ntro/omni-goflow/flows/modifiers"
+)
+
+func init() {
+	registerType(TypeCloseTicket, func() flows.Action { return &CloseTicketAction{} })
+}
+
+// TypeCloseTicket is the type for the close ticket action
+const TypeCloseTicket string = "close_ticket"
+
+// CloseTicketAction is used to close the contact's current ticket, i.e. `@ticket`. A [event:ticket_closed] event
+// will be created if the ticket is closed successfully.
+//
+//	{
+//	  "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//	  "type": "close_ticket"
+//	}
+//
+// @action close_ticket
+type CloseTicketAction struct {
+	baseAction
+	onlineAction
+}
+
+// NewCloseTicket creates a new close ticket action
+func NewCloseTicket(uuid flows.ActionUUID) *CloseTicketAction {
+	return &CloseTicketAction{
+		baseAction: newBaseAction(TypeCloseTicket, uuid),
+	}
+}
+
+// Execute runs this action
+func (a *CloseTicketAction) Execute(run flows.Run, step flows.Step, logModifier flows.ModifierCallback, logEvent flows.EventCallback) error {
+	ticket := currentTicket(run, logEvent)
+	if ticket == nil {
+		return nil
+	}
+	if ticket.Status() == flows.TicketStatusClosed {
+		logEvent(events.NewErrorf("ticket %s is already closed", ticket.UUID()))
+		return nil
+	}
+
+	closed := callTicketService(run, ticket, logEvent, func(svc flows.TicketService, logHTTP flows.HTTPLogCallback) error {
+		return svc.Close(run.Session(), ticket, logHTTP)
+	})
+	if closed {
+		ticket.SetStatus(flows.TicketStatusClosed)
+		logEvent(events.NewTicketClosed(ticket))
+
+		// need to re-evaluate groups since may have groups that query on tickets
+		modifiers.ReevaluateGroups(run.Environment(), run.Session().Assets(), run.Contact(), logEvent)
+	}
+
+	return nil
+}
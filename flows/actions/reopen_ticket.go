@@ -0,0 +1,60 @@
+package actions
+
+import (
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/events"
+	"github.com/developc3ntro/omni-goflow/flows/modifiers"
+)
+
+func init() {
+	registerType(TypeReopenTicket, func() flows.Action { return &ReopenTicketAction{} })
+}
+
+// TypeReopenTicket is the type for the reopen ticket action
+const TypeReopenTicket string = "reopen_ticket"
+
+// ReopenTicketAction is used to reopen the contact's current ticket, i.e. `@ticket`, if it has been closed. A
+// [event:ticket_reopened] event will be created if the ticket is reopened successfully.
+//
+//	{
+//	  "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
+//	  "type": "reopen_ticket"
+//	}
+//
+// @action reopen_ticket
+type ReopenTicketAction struct {
+	baseAction
+	onlineAction
+}
+
+// NewReopenTicket creates a new reopen ticket action
+func NewReopenTicket(uuid flows.ActionUUID) *ReopenTicketAction {
+	return &ReopenTicketAction{
+		baseAction: newBaseAction(TypeReopenTicket, uuid),
+	}
+}
+
+// Execute runs this action
+func (a *ReopenTicketAction) Execute(run flows.Run, step flows.Step, logModifier flows.ModifierCallback, logEvent flows.EventCallback) error {
+	ticket := currentTicket(run, logEvent)
+	if ticket == nil {
+		return nil
+	}
+	if ticket.Status() == flows.TicketStatusOpen {
+		logEvent(events.NewErrorf("ticket %s is already open", ticket.UUID()))
+		return nil
+	}
+
+	reopened := callTicketService(run, ticket, logEvent, func(svc flows.TicketService, logHTTP flows.HTTPLogCallback) error {
+		return svc.Reopen(run.Session(), ticket, logHTTP)
+	})
+	if reopened {
+		ticket.SetStatus(flows.TicketStatusOpen)
+		logEvent(events.NewTicketReopened(ticket))
+
+		// need to re-evaluate groups since may have groups that query on tickets
+		modifiers.ReevaluateGroups(run.Environment(), run.Session().Assets(), run.Contact(), logEvent)
+	}
+
+	return nil
+}
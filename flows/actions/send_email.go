@@ -1,11 +1,17 @@
 package actions
 
 import (
+	"html"
+	"mime"
+	"net/url"
+	"path"
 	"regexp"
 	"strings"
 
+	"github.com/developc3ntro/omni-goflow/excellent/types"
 	"github.com/developc3ntro/omni-goflow/flows"
 	"github.com/developc3ntro/omni-goflow/flows/events"
+	"github.com/developc3ntro/omni-goflow/utils"
 	"github.com/nyaruka/gocommon/uuids"
 	"github.com/pkg/errors"
 )
@@ -18,9 +24,13 @@ func init() {
 const TypeSendEmail string = "send_email"
 
 // SendEmailAction can be used to send an email to one or more recipients. The subject, body and addresses
-// can all contain expressions.
+// can all contain expressions. Optionally the email can also be copied to `cc` and `bcc` recipients, given a
+// `reply_to` address, an `html` body which is sent as an alternative to the plain text body, and `attachments`.
+// Each attachment can be a URL, a `<content-type>:<url>` string, or an expression like `@input.attachments`
+// which evaluates to a list of those.
 //
-// An [event:email_sent] event will be created if the email could be sent.
+// An [event:email_sent] event will be created if the email could be sent, and a [event:service_called] event
+// will be created if the email service made any HTTP calls, e.g. to fetch attachments.
 //
 //	{
 //	  "uuid": "8eebd020-1af5-431c-b943-aa670fc74da9",
@@ -35,9 +45,14 @@ type SendEmailAction struct {
 	baseAction
 	onlineAction
 
-	Addresses []string `json:"addresses" validate:"required,min=1" engine:"evaluated"`
-	Subject   string   `json:"subject" validate:"required" engine:"localized,evaluated"`
-	Body      string   `json:"body" validate:"required" engine:"localized,evaluated"`
+	Addresses   []string `json:"addresses" validate:"required,min=1" engine:"evaluated"`
+	Cc          []string `json:"cc,omitempty" engine:"evaluated"`
+	Bcc         []string `json:"bcc,omitempty" engine:"evaluated"`
+	ReplyTo     string   `json:"reply_to,omitempty" engine:"evaluated"`
+	Subject     string   `json:"subject" validate:"required" engine:"localized,evaluated"`
+	Body        string   `json:"body" validate:"required" engine:"localized,evaluated"`
+	HTML        string   `json:"html,omitempty" engine:"localized,evaluated"`
+	Attachments []string `json:"attachments,omitempty" engine:"evaluated"`
 }
 
 // NewSendEmail creates a new send email action
@@ -77,41 +92,138 @@ func (a *SendEmailAction) Execute(run flows.Run, step flows.Step, logModifier fl
 		return nil
 	}
 
-	evaluatedAddresses := make([]string, 0)
-
-	for _, address := range a.Addresses {
-		evaluatedAddress, err := run.EvaluateTemplate(address)
+	// expressions in the HTML body are escaped so that values can't inject markup
+	var evaluatedHTML string
+	if a.HTML != "" {
+		localizedHTML := run.GetText(uuids.UUID(a.UUID()), "html", a.HTML)
+		evaluatedHTML, err = run.EvaluateTemplateText(localizedHTML, html.EscapeString, true)
 		if err != nil {
 			logEvent(events.NewError(err))
 		}
-		if evaluatedAddress == "" {
-			logEvent(events.NewErrorf("email address evaluated to empty string, skipping"))
-			continue
-		}
-
-		// strip mailto prefix if this is an email URN
-		evaluatedAddress = strings.TrimPrefix(evaluatedAddress, "mailto:")
-
-		evaluatedAddresses = append(evaluatedAddresses, evaluatedAddress)
 	}
 
+	evaluatedAddresses := a.evaluateAddresses(run, a.Addresses, logEvent)
+
 	// nothing to do if there are no addresses
 	if len(evaluatedAddresses) == 0 {
 		return nil
 	}
 
+	email := &flows.Email{
+		To:          evaluatedAddresses,
+		Cc:          a.evaluateAddresses(run, a.Cc, logEvent),
+		Bcc:         a.evaluateAddresses(run, a.Bcc, logEvent),
+		Subject:     evaluatedSubject,
+		Body:        evaluatedBody,
+		HTML:        evaluatedHTML,
+		Attachments: a.evaluateAttachments(run, logEvent),
+	}
+
+	if a.ReplyTo != "" {
+		replyTo := a.evaluateAddresses(run, []string{a.ReplyTo}, logEvent)
+		if len(replyTo) > 0 {
+			email.ReplyTo = replyTo[0]
+		}
+	}
+
 	svc, err := run.Session().Engine().Services().Email(run.Session())
 	if err != nil {
 		logEvent(events.NewError(err))
 		return nil
 	}
 
-	err = svc.Send(run.Session(), evaluatedAddresses, evaluatedSubject, evaluatedBody)
+	httpLogger := &flows.HTTPLogger{}
+
+	err = svc.Send(run.Session(), email, httpLogger.Log)
+
+	if len(httpLogger.Logs) > 0 {
+		logEvent(events.NewEmailServiceCalled(httpLogger.Logs))
+	}
 	if err != nil {
 		logEvent(events.NewError(errors.Wrap(err, "unable to send email")))
 	} else {
-		logEvent(events.NewEmailSent(evaluatedAddresses, evaluatedSubject, evaluatedBody))
+		logEvent(events.NewEmailSent(email))
 	}
 
 	return nil
 }
+
+func (a *SendEmailAction) evaluateAddresses(run flows.Run, addresses []string, logEvent flows.EventCallback) []string {
+	var evaluatedAddresses []string
+
+	for _, address := range addresses {
+		evaluatedAddress, err := run.EvaluateTemplate(address)
+		if err != nil {
+			logEvent(events.NewError(err))
+		}
+		if evaluatedAddress == "" {
+			logEvent(events.NewErrorf("email address evaluated to empty string, skipping"))
+			continue
+		}
+
+		// strip mailto prefix if this is an email URN
+		evaluatedAddress = strings.TrimPrefix(evaluatedAddress, "mailto:")
+
+		evaluatedAddresses = append(evaluatedAddresses, evaluatedAddress)
+	}
+
+	return evaluatedAddresses
+}
+
+func (a *SendEmailAction) evaluateAttachments(run flows.Run, logEvent flows.EventCallback) []utils.Attachment {
+	var evaluatedAttachments []utils.Attachment
+
+	for _, attachment := range a.Attachments {
+		value, err := run.EvaluateTemplateValue(attachment)
+		if err != nil {
+			logEvent(events.NewError(err))
+		}
+		if types.IsXError(value) {
+			logEvent(events.NewError(value.(error)))
+			continue
+		}
+
+		// an expression like @input.attachments evaluates to an array of attachments
+		items := []types.XValue{value}
+		if asArray, isArray := value.(*types.XArray); isArray {
+			items = make([]types.XValue, asArray.Count())
+			for i := range items {
+				items[i] = asArray.Get(i)
+			}
+		}
+
+		for _, item := range items {
+			asText, _ := types.ToXText(run.Environment(), item)
+			evaluatedAttachment := strings.TrimSpace(asText.Native())
+
+			if evaluatedAttachment == "" {
+				logEvent(events.NewErrorf("email attachment evaluated to empty string, skipping"))
+				continue
+			}
+			if len(evaluatedAttachment) > maxAttachmentLength {
+				logEvent(events.NewErrorf("evaluated attachment is longer than %d limit, skipping", maxAttachmentLength))
+				continue
+			}
+
+			evaluatedAttachments = append(evaluatedAttachments, toEmailAttachment(evaluatedAttachment))
+		}
+	}
+
+	return evaluatedAttachments
+}
+
+// converts an evaluated attachment which may be a plain URL to a <content-type>:<url> attachment
+func toEmailAttachment(s string) utils.Attachment {
+	if !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") {
+		return utils.Attachment(s)
+	}
+
+	contentType := "application/octet-stream"
+	if u, err := url.Parse(s); err == nil {
+		if byExtension := mime.TypeByExtension(path.Ext(u.Path)); byExtension != "" {
+			contentType, _, _ = strings.Cut(byExtension, ";")
+		}
+	}
+
+	return utils.Attachment(contentType + ":" + s)
+}
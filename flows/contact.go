@@ -270,7 +270,7 @@ func (c *Contact) Fields() FieldValues { return c.fields }
 // Groups returns the groups that this contact belongs to
 func (c *Contact) Groups() *GroupList { return c.groups }
 
-// Tickets returns the tickets that this contact has open
+// Tickets returns the tickets of this contact, both open and closed
 func (c *Contact) Tickets() *TicketList { return c.tickets }
 
 // Reference returns a reference to this contact
@@ -314,7 +314,7 @@ func (c *Contact) Format(env envs.Environment) string {
 //	groups:[]group -> the groups the contact belongs to
 //	fields:fields -> the custom field values of the contact
 //	channel:channel -> the preferred channel of the contact
-//	tickets:[]ticket -> the open tickets of the contact
+//	tickets:[]ticket -> the tickets of the contact, both open and closed, so use the status of each to filter
 //
 // @context contact
 func (c *Contact) Context(env envs.Environment) map[string]types.XValue {
@@ -492,7 +492,7 @@ func (c *Contact) QueryProperty(env envs.Environment, key string, propType conta
 			}
 			return vals
 		case contactql.AttributeTickets:
-			return []interface{}{decimal.NewFromInt(int64(c.tickets.Count()))}
+			return []interface{}{decimal.NewFromInt(int64(c.tickets.OpenCount()))}
 		case contactql.AttributeCreatedOn:
 			return []interface{}{c.createdOn}
 		case contactql.AttributeLastSeenOn:
@@ -0,0 +1,243 @@
+// Package coverage records which nodes, exits and categories of flows are exercised across many sessions.
+package coverage
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/buger/jsonparser"
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/events"
+	"github.com/pkg/errors"
+)
+
+// FlowCoverage is the number of times each node, exit and category of a flow was hit
+type FlowCoverage struct {
+	Flow       flows.Flow                 `json:"-"`
+	Nodes      map[flows.NodeUUID]int     `json:"nodes"`
+	Exits      map[flows.ExitUUID]int     `json:"exits"`
+	Categories map[flows.CategoryUUID]int `json:"categories"`
+}
+
+func newFlowCoverage(flow flows.Flow) *FlowCoverage {
+	return &FlowCoverage{
+		Flow:       flow,
+		Nodes:      make(map[flows.NodeUUID]int),
+		Exits:      make(map[flows.ExitUUID]int),
+		Categories: make(map[flows.CategoryUUID]int),
+	}
+}
+
+// NodesCovered returns the number of nodes in the flow which were hit and the total number of nodes
+func (f *FlowCoverage) NodesCovered() (int, int) {
+	hit, total := 0, 0
+	for _, node := range f.Flow.Nodes() {
+		if f.Nodes[node.UUID()] > 0 {
+			hit++
+		}
+		total++
+	}
+	return hit, total
+}
+
+// ExitsCovered returns the number of exits in the flow which were hit and the total number of exits
+func (f *FlowCoverage) ExitsCovered() (int, int) {
+	hit, total := 0, 0
+	for _, node := range f.Flow.Nodes() {
+		for _, exit := range node.Exits() {
+			if f.Exits[exit.UUID()] > 0 {
+				hit++
+			}
+			total++
+		}
+	}
+	return hit, total
+}
+
+// CategoriesCovered returns the number of router categories in the flow which were hit and the total number of categories
+func (f *FlowCoverage) CategoriesCovered() (int, int) {
+	hit, total := 0, 0
+	for _, node := range f.Flow.Nodes() {
+		if node.Router() != nil {
+			for _, category := range node.Router().Categories() {
+				if f.Categories[category.UUID()] > 0 {
+					hit++
+				}
+				total++
+			}
+		}
+	}
+	return hit, total
+}
+
+// AnnotateUI returns the UI JSON of the flow with the hit counts written to a coverage property of each node so that
+// they can be visualized in the editor, e.g.
+//
+//	"e75d55ff-d871-4b51-accd-c4282f8e2757": {
+//	  "position": {"left": 100, "top": 0},
+//	  "coverage": {
+//	    "count": 3,
+//	    "exits": {"bd19e653-5568-48b4-a47b-d92ed3a0d5e9": 1, ...},
+//	    "categories": {"294015f4-2052-4805-a5b8-e57fa2e15dd0": 1, ...}
+//	  }
+//	}
+func (f *FlowCoverage) AnnotateUI() (json.RawMessage, error) {
+	ui := []byte(f.Flow.UI())
+	if len(ui) == 0 {
+		ui = []byte(`{}`)
+	}
+
+	for _, node := range f.Flow.Nodes() {
+		nodeCov := &struct {
+			Count      int                        `json:"count"`
+			Exits      map[flows.ExitUUID]int     `json:"exits"`
+			Categories map[flows.CategoryUUID]int `json:"categories,omitempty"`
+		}{
+			Count: f.Nodes[node.UUID()],
+			Exits: make(map[flows.ExitUUID]int, len(node.Exits())),
+		}
+		for _, exit := range node.Exits() {
+			nodeCov.Exits[exit.UUID()] = f.Exits[exit.UUID()]
+		}
+		if node.Router() != nil {
+			nodeCov.Categories = make(map[flows.CategoryUUID]int, len(node.Router().Categories()))
+			for _, category := range node.Router().Categories() {
+				nodeCov.Categories[category.UUID()] = f.Categories[category.UUID()]
+			}
+		}
+
+		marshaled, err := json.Marshal(nodeCov)
+		if err != nil {
+			return nil, err
+		}
+
+		ui, err = jsonparser.Set(ui, marshaled, "nodes", string(node.UUID()), "coverage")
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to write coverage for node %s to flow UI", node.UUID())
+		}
+	}
+
+	return ui, nil
+}
+
+// Collector accumulates coverage of flows across many sessions
+type Collector struct {
+	flows map[assets.FlowUUID]*FlowCoverage
+}
+
+// NewCollector creates a new empty coverage collector
+func NewCollector() *Collector {
+	return &Collector{flows: make(map[assets.FlowUUID]*FlowCoverage)}
+}
+
+// AddSession records the paths of all runs in the given session. This should be called once per session after it has
+// ended, as calling it again would count the same steps again.
+func (c *Collector) AddSession(session flows.Session) {
+	for _, run := range session.Runs() {
+		c.AddRun(run)
+	}
+}
+
+// AddRun records the path of the given run, using the categories of any results it saved to determine which
+// category was taken where more than one category shares an exit.
+func (c *Collector) AddRun(run flows.Run) {
+	if run.Flow() == nil {
+		return
+	}
+	fc := c.flowCoverage(run.Flow())
+
+	categoriesByStep := make(map[flows.StepUUID]string)
+	for _, e := range run.Events() {
+		if typed, ok := e.(*events.RunResultChangedEvent); ok {
+			categoriesByStep[typed.StepUUID()] = typed.Category
+		}
+	}
+
+	for _, step := range run.Path() {
+		node := run.Flow().GetNode(step.NodeUUID())
+		if node == nil {
+			continue
+		}
+
+		fc.Nodes[node.UUID()]++
+
+		if step.ExitUUID() != "" {
+			fc.Exits[step.ExitUUID()]++
+
+			if category := findCategory(node, step.ExitUUID(), categoriesByStep[step.UUID()]); category != nil {
+				fc.Categories[category.UUID()]++
+			}
+		}
+	}
+}
+
+// AddSprint records the segments of the given sprint. Segments only describe movement from one node to another, so
+// the nodes where runs start and exits which don't lead to another node are not counted, and where more than one
+// category shares an exit, no category is counted. Use AddSession instead when complete sessions are available.
+func (c *Collector) AddSprint(sprint flows.Sprint) {
+	for _, seg := range sprint.Segments() {
+		fc := c.flowCoverage(seg.Flow())
+
+		fc.Exits[seg.Exit().UUID()]++
+		fc.Nodes[seg.Destination().UUID()]++
+
+		if category := findCategory(seg.Node(), seg.Exit().UUID(), ""); category != nil {
+			fc.Categories[category.UUID()]++
+		}
+	}
+}
+
+// Flows returns the coverage of each flow which has been seen, ordered by flow name
+func (c *Collector) Flows() []*FlowCoverage {
+	all := make([]*FlowCoverage, 0, len(c.flows))
+	for _, fc := range c.flows {
+		all = append(all, fc)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Flow.Name() != all[j].Flow.Name() {
+			return all[i].Flow.Name() < all[j].Flow.Name()
+		}
+		return all[i].Flow.UUID() < all[j].Flow.UUID()
+	})
+	return all
+}
+
+// Flow returns the coverage of the flow with the given UUID or nil if it hasn't been seen
+func (c *Collector) Flow(uuid assets.FlowUUID) *FlowCoverage {
+	return c.flows[uuid]
+}
+
+func (c *Collector) flowCoverage(flow flows.Flow) *FlowCoverage {
+	fc := c.flows[flow.UUID()]
+	if fc == nil {
+		fc = newFlowCoverage(flow)
+		c.flows[flow.UUID()] = fc
+	}
+	return fc
+}
+
+// finds the category of the given node which was taken to reach the given exit, using the category name from the
+// saved result if there is more than one possibility
+func findCategory(node flows.Node, exitUUID flows.ExitUUID, name string) flows.Category {
+	if node.Router() == nil {
+		return nil
+	}
+
+	var matches []flows.Category
+	for _, category := range node.Router().Categories() {
+		if category.ExitUUID() == exitUUID {
+			matches = append(matches, category)
+		}
+	}
+
+	if len(matches) == 1 {
+		return matches[0]
+	}
+	for _, category := range matches {
+		if name != "" && category.Name() == name {
+			return category
+		}
+	}
+	return nil
+}
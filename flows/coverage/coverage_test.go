@@ -0,0 +1,81 @@
+package coverage_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/coverage"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	assetsJSON, err := os.ReadFile("testdata/flows.json")
+	require.NoError(t, err)
+
+	fromSessions := coverage.NewCollector()
+	fromSprints := coverage.NewCollector()
+
+	for _, reply := range []string{"red", "blue", "blue"} {
+		session, sprint := test.NewSessionBuilder().WithAssets(assetsJSON).WithFlow("a1fe2f3e-8fc5-4cc0-b4d3-48f486d762e3").MustBuild()
+		fromSprints.AddSprint(sprint)
+
+		session, sprint, err = test.ResumeSession(session, assetsJSON, reply)
+		require.NoError(t, err)
+		require.Equal(t, flows.SessionStatusCompleted, session.Status())
+		fromSprints.AddSprint(sprint)
+
+		fromSessions.AddSession(session)
+	}
+
+	assert.Len(t, fromSessions.Flows(), 1)
+	assert.Nil(t, fromSessions.Flow("11c4f8a2-5b3f-4c93-a1d4-1f1b5e3b5e0e"))
+
+	fc := fromSessions.Flow("a1fe2f3e-8fc5-4cc0-b4d3-48f486d762e3")
+	assert.Equal(t, map[flows.NodeUUID]int{
+		"e75d55ff-d871-4b51-accd-c4282f8e2757": 3,
+		"f5b80e3b-df48-4f74-b083-8f0b4168fd93": 3,
+	}, fc.Nodes)
+	assert.Equal(t, map[flows.ExitUUID]int{
+		"bd19e653-5568-48b4-a47b-d92ed3a0d5e9": 1,
+		"da5645dc-e396-45f6-a52b-b6b365c820b6": 2,
+		"ba00f00c-698e-4d80-806b-0375b8c8256a": 3,
+	}, fc.Exits)
+	assert.Equal(t, map[flows.CategoryUUID]int{
+		"294015f4-2052-4805-a5b8-e57fa2e15dd0": 1, // Red
+		"a412fdc2-a9a3-4aaf-ab06-f6f351ce2ab2": 2, // Blue (shares exit with Green)
+	}, fc.Categories)
+
+	hit, total := fc.NodesCovered()
+	assert.Equal(t, []int{2, 3}, []int{hit, total})
+	hit, total = fc.ExitsCovered()
+	assert.Equal(t, []int{3, 5}, []int{hit, total})
+	hit, total = fc.CategoriesCovered()
+	assert.Equal(t, []int{2, 4}, []int{hit, total})
+
+	report := &strings.Builder{}
+	require.NoError(t, fromSessions.WriteReport(report))
+	test.AssertSnapshot(t, "report", report.String())
+
+	ui, err := fc.AnnotateUI()
+	require.NoError(t, err)
+	ui, err = test.NormalizeJSON(ui)
+	require.NoError(t, err)
+	test.AssertSnapshot(t, "ui", string(ui))
+
+	// segments don't include start nodes, terminal exits or categories which share an exit
+	fc = fromSprints.Flow("a1fe2f3e-8fc5-4cc0-b4d3-48f486d762e3")
+	assert.Equal(t, map[flows.NodeUUID]int{
+		"f5b80e3b-df48-4f74-b083-8f0b4168fd93": 3,
+	}, fc.Nodes)
+	assert.Equal(t, map[flows.ExitUUID]int{
+		"bd19e653-5568-48b4-a47b-d92ed3a0d5e9": 1,
+		"da5645dc-e396-45f6-a52b-b6b365c820b6": 2,
+	}, fc.Exits)
+	assert.Equal(t, map[flows.CategoryUUID]int{
+		"294015f4-2052-4805-a5b8-e57fa2e15dd0": 1,
+	}, fc.Categories)
+}
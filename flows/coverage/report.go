@@ -0,0 +1,79 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+)
+
+// WriteReport writes a report of the coverage of each flow, listing every node, category and exit with the number
+// of times it was hit
+func (c *Collector) WriteReport(w io.Writer) error {
+	for _, fc := range c.Flows() {
+		if err := fc.WriteReport(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteReport writes a report of the coverage of this flow, listing every node, category and exit with the number
+// of times it was hit
+func (f *FlowCoverage) WriteReport(w io.Writer) error {
+	nodesHit, nodes := f.NodesCovered()
+	exitsHit, exits := f.ExitsCovered()
+	categoriesHit, categories := f.CategoriesCovered()
+
+	lines := []string{
+		fmt.Sprintf("%s (%s): %d/%d nodes, %d/%d exits, %d/%d categories", f.Flow.Name(), f.Flow.UUID(), nodesHit, nodes, exitsHit, exits, categoriesHit, categories),
+	}
+
+	for _, node := range f.Flow.Nodes() {
+		lines = append(lines, "  "+describeHits(fmt.Sprintf("node %s (%s)", node.UUID(), describeNode(node)), f.Nodes[node.UUID()]))
+
+		if node.Router() != nil {
+			for _, category := range node.Router().Categories() {
+				lines = append(lines, "    "+describeHits(fmt.Sprintf("category '%s'", category.Name()), f.Categories[category.UUID()]))
+			}
+		}
+		for _, exit := range node.Exits() {
+			lines = append(lines, "    "+describeHits(fmt.Sprintf("exit %s", exit.UUID()), f.Exits[exit.UUID()]))
+		}
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func describeHits(thing string, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("❌ %s never hit", thing)
+	}
+	return fmt.Sprintf("✅ %s hit %s", thing, pluralize(count, "time", "times"))
+}
+
+// describes a node by its router or its actions
+func describeNode(node flows.Node) string {
+	if node.Router() != nil {
+		if node.Router().ResultName() != "" {
+			return fmt.Sprintf("%s router '%s'", node.Router().Type(), node.Router().ResultName())
+		}
+		return fmt.Sprintf("%s router", node.Router().Type())
+	}
+
+	types := make([]string, len(node.Actions()))
+	for i, action := range node.Actions() {
+		types[i] = action.Type()
+	}
+	return strings.Join(types, ", ")
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return strconv.Itoa(n) + " " + singular
+	}
+	return strconv.Itoa(n) + " " + plural
+}
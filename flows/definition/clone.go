@@ -0,0 +1,314 @@
+package definition
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/definition/migrations"
+	"github.com/developc3ntro/omni-goflow/flows/inspect"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/uuids"
+	"github.com/pkg/errors"
+)
+
+// AssetMapping is how a reference to an asset in a flow being cloned was resolved in the target assets. If the asset
+// couldn't be found then New is nil and the reference is left unchanged in the clone.
+type AssetMapping struct {
+	Type string           `json:"type"`
+	Old  assets.Reference `json:"old"`
+	New  assets.Reference `json:"new,omitempty"`
+}
+
+// CloneReport describes how a flow was cloned into a target workspace
+type CloneReport struct {
+	UUIDs      map[uuids.UUID]uuids.UUID `json:"uuids"`
+	Assets     []*AssetMapping           `json:"assets"`
+	Unresolved []*AssetMapping           `json:"unresolved"`
+}
+
+// CloneFlow clones the given flow for use with the given target assets. Every UUID in the flow definition, including
+// node, action, exit, category and localization UUIDs, is replaced with a new UUID. Asset references are resolved in
+// the target by name, or by key for fields and globals, and references which can't be resolved are left unchanged and
+// included in the report as unresolved.
+func CloneFlow(flow flows.Flow, target assets.Source) (json.RawMessage, *CloneReport, error) {
+	report := &CloneReport{Assets: make([]*AssetMapping, 0), Unresolved: make([]*AssetMapping, 0)}
+	depMapping := make(map[uuids.UUID]uuids.UUID)
+	keyMapping := make(map[string]map[string]string)
+
+	for _, ref := range flowReferences(flow) {
+		resolved, err := resolveReference(target, ref)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "error resolving %s", ref)
+		}
+
+		mapping := &AssetMapping{Type: ref.Type(), Old: ref, New: resolved}
+		report.Assets = append(report.Assets, mapping)
+
+		if resolved == nil {
+			report.Unresolved = append(report.Unresolved, mapping)
+
+			// unresolved references keep their UUIDs so that they can be fixed later
+			if uuid := referenceUUID(ref); uuid != "" {
+				depMapping[uuid] = uuid
+			}
+			continue
+		}
+
+		if uuid := referenceUUID(ref); uuid != "" {
+			depMapping[uuid] = referenceUUID(resolved)
+		} else if (ref.Type() == "field" || ref.Type() == "global") && ref.Identity() != resolved.Identity() {
+			if keyMapping[ref.Type()] == nil {
+				keyMapping[ref.Type()] = make(map[string]string)
+			}
+			keyMapping[ref.Type()][ref.Identity()] = resolved.Identity()
+		}
+	}
+
+	definition, err := jsonx.Marshal(flow)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clone, mapping, err := migrations.CloneWithMapping(definition, depMapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(keyMapping) > 0 {
+		if clone, err = remapKeys(clone, keyMapping); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// only report the UUIDs that belong to the flow itself
+	report.UUIDs = make(map[uuids.UUID]uuids.UUID, len(mapping))
+	for old, new := range mapping {
+		if _, isDep := depMapping[old]; !isDep {
+			report.UUIDs[old] = new
+		}
+	}
+
+	return clone, report, nil
+}
+
+// extracts the unique asset references in the given flow, both from actions and routers and from expressions
+func flowReferences(flow flows.Flow) []assets.Reference {
+	refs := make([]assets.Reference, 0)
+	seen := make(map[string]bool)
+
+	include := func(ref assets.Reference) {
+		if ref == nil || ref.Variable() {
+			return
+		}
+
+		// references to the flow itself are remapped along with its other UUIDs
+		if typed, ok := ref.(*assets.FlowReference); ok && typed.UUID == flow.UUID() {
+			return
+		}
+
+		key := fmt.Sprintf("%s:%s", ref.Type(), ref.Identity())
+		if !seen[key] {
+			refs = append(refs, ref)
+			seen[key] = true
+		}
+	}
+
+	for _, n := range flow.Nodes() {
+		n.EnumerateDependencies(flow.Localization(), func(a flows.Action, r flows.Router, l envs.Language, ref assets.Reference) {
+			include(ref)
+		})
+		n.EnumerateTemplates(flow.Localization(), func(a flows.Action, r flows.Router, l envs.Language, t string) {
+			refs, _ := inspect.ExtractFromTemplate(t)
+			for _, ref := range refs {
+				include(ref)
+			}
+		})
+	}
+
+	return refs
+}
+
+// gets the UUID of the given reference if it has one
+func referenceUUID(ref assets.Reference) uuids.UUID {
+	switch typed := ref.(type) {
+	case assets.UUIDReference:
+		return typed.GenericUUID()
+	case *flows.ContactReference:
+		return uuids.UUID(typed.UUID)
+	}
+	return ""
+}
+
+// resolves the given reference in the target assets, returning nil if it can't be found
+func resolveReference(target assets.Source, ref assets.Reference) (assets.Reference, error) {
+	switch typed := ref.(type) {
+	case *assets.ChannelReference:
+		all, err := target.Channels()
+		for _, a := range all {
+			if strings.EqualFold(a.Name(), typed.Name) {
+				return assets.NewChannelReference(a.UUID(), a.Name()), nil
+			}
+		}
+		return nil, err
+	case *assets.ClassifierReference:
+		all, err := target.Classifiers()
+		for _, a := range all {
+			if strings.EqualFold(a.Name(), typed.Name) {
+				return assets.NewClassifierReference(a.UUID(), a.Name()), nil
+			}
+		}
+		return nil, err
+	case *assets.FieldReference:
+		all, err := target.Fields()
+		for _, a := range all {
+			if a.Key() == typed.Key {
+				return assets.NewFieldReference(a.Key(), a.Name()), nil
+			}
+		}
+		for _, a := range all {
+			if typed.Name != "" && strings.EqualFold(a.Name(), typed.Name) {
+				return assets.NewFieldReference(a.Key(), a.Name()), nil
+			}
+		}
+		return nil, err
+	case *assets.FlowReference:
+		a, err := target.FlowByName(typed.Name)
+		if err != nil {
+			return nil, nil // sources return an error if the flow doesn't exist
+		}
+		return assets.NewFlowReference(a.UUID(), a.Name()), nil
+	case *assets.FunctionReference:
+		all, err := target.Functions()
+		for _, a := range all {
+			if strings.EqualFold(a.Name(), typed.Name) {
+				return assets.NewFunctionReference(a.Name()), nil
+			}
+		}
+		return nil, err
+	case *assets.GlobalReference:
+		all, err := target.Globals()
+		for _, a := range all {
+			if a.Key() == typed.Key {
+				return assets.NewGlobalReference(a.Key(), a.Name()), nil
+			}
+		}
+		for _, a := range all {
+			if typed.Name != "" && strings.EqualFold(a.Name(), typed.Name) {
+				return assets.NewGlobalReference(a.Key(), a.Name()), nil
+			}
+		}
+		return nil, err
+	case *assets.GroupReference:
+		all, err := target.Groups()
+		for _, a := range all {
+			if strings.EqualFold(a.Name(), typed.Name) {
+				return assets.NewGroupReference(a.UUID(), a.Name()), nil
+			}
+		}
+		return nil, err
+	case *assets.LabelReference:
+		all, err := target.Labels()
+		for _, a := range all {
+			if strings.EqualFold(a.Name(), typed.Name) {
+				return assets.NewLabelReference(a.UUID(), a.Name()), nil
+			}
+		}
+		return nil, err
+	case *assets.TemplateReference:
+		all, err := target.Templates()
+		for _, a := range all {
+			if strings.EqualFold(a.Name(), typed.Name) {
+				return assets.NewTemplateReference(a.UUID(), a.Name()), nil
+			}
+		}
+		return nil, err
+	case *assets.TicketerReference:
+		all, err := target.Ticketers()
+		for _, a := range all {
+			if strings.EqualFold(a.Name(), typed.Name) {
+				return assets.NewTicketerReference(a.UUID(), a.Name()), nil
+			}
+		}
+		return nil, err
+	case *assets.TopicReference:
+		all, err := target.Topics()
+		for _, a := range all {
+			if strings.EqualFold(a.Name(), typed.Name) {
+				return assets.NewTopicReference(a.UUID(), a.Name()), nil
+			}
+		}
+		return nil, err
+	case *assets.UserReference:
+		all, err := target.Users()
+		for _, a := range all {
+			if strings.EqualFold(a.Email(), typed.Email) {
+				return assets.NewUserReference(a.Email(), a.Name()), nil
+			}
+		}
+		return nil, err
+	}
+
+	// other references like contacts are specific to a workspace and can't be resolved
+	return nil, nil
+}
+
+// replaces field and global keys which are different in the target assets, both in references and in expressions
+func remapKeys(definition []byte, keyMapping map[string]map[string]string) ([]byte, error) {
+	g, err := jsonx.DecodeGeneric(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	// matches things like @fields.age, @contact.fields.age and @(globals.org_name & "!"), replacing all keys in a
+	// single pass so that keys which are swapped or chained, e.g. a -> b and b -> c, are only replaced once
+	contextNames := map[string]string{"field": "fields", "global": "globals"}
+	replacements := make(map[string]string)
+	for refType, keys := range keyMapping {
+		for oldKey, newKey := range keys {
+			replacements[strings.ToLower(contextNames[refType]+"."+oldKey)] = contextNames[refType] + "." + newKey
+		}
+	}
+
+	paths := make([]string, 0, len(replacements))
+	for path := range replacements {
+		paths = append(paths, regexp.QuoteMeta(path))
+	}
+	sort.Strings(paths)
+
+	pathRegex := regexp.MustCompile(`(?i)\b(?:` + strings.Join(paths, "|") + `)\b`)
+	replace := func(s string) string {
+		return pathRegex.ReplaceAllStringFunc(s, func(path string) string { return replacements[strings.ToLower(path)] })
+	}
+
+	var remap func(interface{}) interface{}
+	remap = func(v interface{}) interface{} {
+		switch typed := v.(type) {
+		case map[string]interface{}:
+			for k, child := range typed {
+				typed[k] = remap(child)
+			}
+
+			// field references on actions like set_contact_field
+			if field, ok := typed["field"].(map[string]interface{}); ok {
+				if key, ok := field["key"].(string); ok && keyMapping["field"][key] != "" {
+					field["key"] = keyMapping["field"][key]
+				}
+			}
+		case []interface{}:
+			for i, child := range typed {
+				typed[i] = remap(child)
+			}
+		case string:
+			return replace(typed)
+		}
+		return v
+	}
+
+	return jsonx.Marshal(remap(g))
+}
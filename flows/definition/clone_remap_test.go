@@ -0,0 +1,28 @@
+package definition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemapKeys(t *testing.T) {
+	definition := []byte(`{
+		"text": "@fields.a @contact.fields.b @(FIELDS.C & globals.x) @fields.ab @urns.a",
+		"field": {"key": "a", "name": "A"}
+	}`)
+
+	// keys which are swapped or chained are only replaced once
+	for i := 0; i < 20; i++ {
+		remapped, err := remapKeys(definition, map[string]map[string]string{
+			"field":  {"a": "b", "b": "a", "c": "d", "d": "e"},
+			"global": {"x": "y"},
+		})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"text": "@fields.b @contact.fields.a @(fields.d & globals.y) @fields.ab @urns.a",
+			"field": {"key": "b", "name": "A"}
+		}`, string(remapped))
+	}
+}
@@ -0,0 +1,55 @@
+package definition_test
+
+import (
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/assets/static"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows/definition"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/uuids"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneFlow(t *testing.T) {
+	uuids.SetGenerator(uuids.NewSeededGenerator(12345))
+	defer uuids.SetGenerator(uuids.DefaultGenerator)
+
+	flow, err := test.LoadFlowFromAssets(envs.NewBuilder().Build(), "testdata/clone_source.json", "ac0b7111-bd6d-447c-8a79-f879fdcaf3a6")
+	require.NoError(t, err)
+
+	target, err := static.LoadSource("testdata/clone_target.json")
+	require.NoError(t, err)
+
+	cloneJSON, report, err := definition.CloneFlow(flow, target)
+	require.NoError(t, err)
+
+	clone, err := definition.ReadFlow(cloneJSON, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, flow.Name(), clone.Name())
+	assert.NotEqual(t, flow.UUID(), clone.UUID())
+	assert.Equal(t, uuids.UUID(clone.UUID()), uuids.UUID(report.UUIDs["ac0b7111-bd6d-447c-8a79-f879fdcaf3a6"]))
+
+	// check internal links and translations use the new UUIDs
+	action := clone.Nodes()[0].Actions()[0]
+	assert.Equal(t, report.UUIDs["2c7ba845-c911-4371-b4e3-b1af68f0242b"], uuids.UUID(action.UUID()))
+	assert.Equal(t, []string{"Hola @contact.name, tienes @fields.age_years años"}, clone.Localization().GetItemTranslation("spa", uuids.UUID(action.UUID()), "text"))
+
+	types := make([]string, len(report.Unresolved))
+	for i, m := range report.Unresolved {
+		types[i] = m.Type
+	}
+	assert.Equal(t, []string{"label", "contact"}, types)
+
+	clonePretty, err := jsonx.MarshalPretty(clone)
+	require.NoError(t, err)
+	test.AssertSnapshot(t, "clone", string(clonePretty))
+
+	assetsPretty, err := jsonx.MarshalPretty(report.Assets)
+	require.NoError(t, err)
+	test.AssertSnapshot(t, "assets", string(assetsPretty))
+}
@@ -0,0 +1,487 @@
+package definition
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/buger/jsonparser"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/inspect"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/uuids"
+)
+
+// ChangeType is the type of a change between two versions of a flow
+type ChangeType string
+
+// possible types of change between two versions of a flow
+const (
+	ChangeFlowChanged        ChangeType = "flow_changed"
+	ChangeNodeAdded          ChangeType = "node_added"
+	ChangeNodeRemoved        ChangeType = "node_removed"
+	ChangeNodeMoved          ChangeType = "node_moved"
+	ChangeActionAdded        ChangeType = "action_added"
+	ChangeActionRemoved      ChangeType = "action_removed"
+	ChangeActionMoved        ChangeType = "action_moved"
+	ChangeActionChanged      ChangeType = "action_changed"
+	ChangeRouterAdded        ChangeType = "router_added"
+	ChangeRouterRemoved      ChangeType = "router_removed"
+	ChangeRouterChanged      ChangeType = "router_changed"
+	ChangeCategoryAdded      ChangeType = "category_added"
+	ChangeCategoryRemoved    ChangeType = "category_removed"
+	ChangeCategoryChanged    ChangeType = "category_changed"
+	ChangeExitAdded          ChangeType = "exit_added"
+	ChangeExitRemoved        ChangeType = "exit_removed"
+	ChangeExitChanged        ChangeType = "exit_changed"
+	ChangeTranslationAdded   ChangeType = "translation_added"
+	ChangeTranslationRemoved ChangeType = "translation_removed"
+	ChangeTranslationChanged ChangeType = "translation_changed"
+)
+
+// Change is a single difference between two versions of a flow. Old and new values are JSON encoded, and for node
+// moves the field is index if the node's order in the flow changed or position if it was moved in the editor.
+type Change struct {
+	Type       ChangeType       `json:"type"`
+	NodeUUID   flows.NodeUUID   `json:"node_uuid,omitempty"`
+	ActionUUID flows.ActionUUID `json:"action_uuid,omitempty"`
+	ItemUUID   uuids.UUID       `json:"item_uuid,omitempty"`
+	Language   envs.Language    `json:"language,omitempty"`
+	Field      string           `json:"field,omitempty"`
+	Evaluated  bool             `json:"evaluated,omitempty"`
+	Old        json.RawMessage  `json:"old,omitempty"`
+	New        json.RawMessage  `json:"new,omitempty"`
+}
+
+// Description returns a human readable description of this change
+func (c *Change) Description() string {
+	var subject string
+	switch c.Type {
+	case ChangeFlowChanged:
+		subject = "flow"
+	case ChangeNodeAdded, ChangeNodeRemoved, ChangeNodeMoved:
+		subject = fmt.Sprintf("node %s", c.NodeUUID)
+	case ChangeActionAdded, ChangeActionRemoved, ChangeActionMoved, ChangeActionChanged:
+		subject = fmt.Sprintf("action %s", c.ActionUUID)
+	case ChangeRouterAdded, ChangeRouterRemoved, ChangeRouterChanged:
+		subject = fmt.Sprintf("router on node %s", c.NodeUUID)
+	case ChangeCategoryAdded, ChangeCategoryRemoved, ChangeCategoryChanged:
+		subject = fmt.Sprintf("category %s", c.ItemUUID)
+	case ChangeExitAdded, ChangeExitRemoved, ChangeExitChanged:
+		subject = fmt.Sprintf("exit %s", c.ItemUUID)
+	case ChangeTranslationAdded, ChangeTranslationRemoved, ChangeTranslationChanged:
+		subject = fmt.Sprintf("%s translation of %s on %s", c.Language, c.Field, c.ItemUUID)
+	}
+
+	switch c.Type {
+	case ChangeNodeAdded, ChangeActionAdded, ChangeRouterAdded, ChangeCategoryAdded, ChangeExitAdded, ChangeTranslationAdded:
+		return fmt.Sprintf("%s added", subject)
+	case ChangeNodeRemoved, ChangeActionRemoved, ChangeRouterRemoved, ChangeCategoryRemoved, ChangeExitRemoved, ChangeTranslationRemoved:
+		return fmt.Sprintf("%s removed", subject)
+	case ChangeNodeMoved, ChangeActionMoved:
+		return fmt.Sprintf("%s moved: %s %s → %s", subject, c.Field, c.Old, c.New)
+	case ChangeTranslationChanged:
+		return fmt.Sprintf("%s changed: %s → %s", subject, c.Old, c.New)
+	}
+
+	field := c.Field
+	if c.Type == ChangeRouterChanged && c.ItemUUID != "" {
+		field = fmt.Sprintf("%s %s", c.Field, c.ItemUUID)
+	}
+
+	description := fmt.Sprintf("%s changed: %s %s → %s", subject, field, formatValue(c.Old), formatValue(c.New))
+	if c.Evaluated {
+		description += " (evaluated)"
+	}
+	return description
+}
+
+func formatValue(v json.RawMessage) string {
+	if len(v) == 0 {
+		return "none"
+	}
+	return string(v)
+}
+
+// Diff compares two versions of a flow, matching nodes, actions, categories and exits by UUID, and returns the changes
+// needed to get from the old version to the new version
+func Diff(old, new flows.Flow) []*Change {
+	d := &differ{changes: make([]*Change, 0)}
+
+	d.diffFlow(old, new)
+	d.diffNodes(old, new)
+	d.diffLocalization(old.Localization(), new.Localization())
+
+	return d.changes
+}
+
+type differ struct {
+	changes []*Change
+}
+
+func (d *differ) add(c *Change) {
+	d.changes = append(d.changes, c)
+}
+
+func (d *differ) diffFlow(old, new flows.Flow) {
+	d.diffValue(&Change{Type: ChangeFlowChanged, Field: "name"}, old.Name(), new.Name())
+	d.diffValue(&Change{Type: ChangeFlowChanged, Field: "language"}, old.Language(), new.Language())
+	d.diffValue(&Change{Type: ChangeFlowChanged, Field: "type"}, old.Type(), new.Type())
+	d.diffValue(&Change{Type: ChangeFlowChanged, Field: "expire_after_minutes"}, old.ExpireAfterMinutes(), new.ExpireAfterMinutes())
+}
+
+func (d *differ) diffNodes(old, new flows.Flow) {
+	oldIndexes := make(map[flows.NodeUUID]int, len(old.Nodes()))
+	for i, n := range old.Nodes() {
+		oldIndexes[n.UUID()] = i
+	}
+
+	// actions can be moved between nodes so match them across the whole flow
+	oldActions := make(map[flows.ActionUUID]flows.Action)
+	oldActionNodes := make(map[flows.ActionUUID]flows.NodeUUID)
+	for _, n := range old.Nodes() {
+		for _, a := range n.Actions() {
+			oldActions[a.UUID()] = a
+			oldActionNodes[a.UUID()] = n.UUID()
+		}
+	}
+	newActions := make(map[flows.ActionUUID]bool)
+
+	for newIndex, newNode := range new.Nodes() {
+		var oldNode flows.Node
+		if oldIndex, exists := oldIndexes[newNode.UUID()]; exists {
+			oldNode = old.Nodes()[oldIndex]
+
+			d.diffValue(&Change{Type: ChangeNodeMoved, NodeUUID: newNode.UUID(), Field: "index"}, oldIndex, newIndex)
+			d.diffValue(&Change{Type: ChangeNodeMoved, NodeUUID: newNode.UUID(), Field: "position"}, nodePosition(old, oldNode.UUID()), nodePosition(new, newNode.UUID()))
+		} else {
+			d.add(&Change{Type: ChangeNodeAdded, NodeUUID: newNode.UUID()})
+		}
+
+		for _, newAction := range newNode.Actions() {
+			newActions[newAction.UUID()] = true
+
+			oldAction := oldActions[newAction.UUID()]
+			if oldAction == nil {
+				// actions, routers and exits on added nodes are implied by the node being added
+				if oldNode != nil {
+					d.add(&Change{Type: ChangeActionAdded, NodeUUID: newNode.UUID(), ActionUUID: newAction.UUID()})
+				}
+				continue
+			}
+
+			d.diffValue(&Change{Type: ChangeActionMoved, NodeUUID: newNode.UUID(), ActionUUID: newAction.UUID(), Field: "node_uuid"}, oldActionNodes[newAction.UUID()], newNode.UUID())
+			d.diffAction(newNode.UUID(), oldAction, newAction)
+		}
+
+		if oldNode != nil {
+			d.diffActionOrder(oldNode, newNode)
+			d.diffRouter(newNode.UUID(), oldNode.Router(), newNode.Router())
+			d.diffExits(newNode.UUID(), oldNode.Exits(), newNode.Exits())
+		}
+	}
+
+	newNodes := make(map[flows.NodeUUID]bool, len(new.Nodes()))
+	for _, n := range new.Nodes() {
+		newNodes[n.UUID()] = true
+	}
+
+	for _, oldNode := range old.Nodes() {
+		if !newNodes[oldNode.UUID()] {
+			d.add(&Change{Type: ChangeNodeRemoved, NodeUUID: oldNode.UUID()})
+			continue
+		}
+		for _, a := range oldNode.Actions() {
+			if !newActions[a.UUID()] {
+				d.add(&Change{Type: ChangeActionRemoved, NodeUUID: oldNode.UUID(), ActionUUID: a.UUID()})
+			}
+		}
+	}
+}
+
+// compares the order of the actions which are in both versions of a node, ignoring actions added or removed
+func (d *differ) diffActionOrder(old, new flows.Node) {
+	inOld, inNew := make(map[flows.ActionUUID]bool), make(map[flows.ActionUUID]bool)
+	for _, a := range old.Actions() {
+		inOld[a.UUID()] = true
+	}
+	for _, a := range new.Actions() {
+		inNew[a.UUID()] = true
+	}
+
+	oldOrder := make(map[flows.ActionUUID]int)
+	for _, a := range old.Actions() {
+		if inNew[a.UUID()] {
+			oldOrder[a.UUID()] = len(oldOrder)
+		}
+	}
+
+	newIndex := 0
+	for _, a := range new.Actions() {
+		if inOld[a.UUID()] {
+			d.diffValue(&Change{Type: ChangeActionMoved, NodeUUID: new.UUID(), ActionUUID: a.UUID(), Field: "index"}, oldOrder[a.UUID()], newIndex)
+			newIndex++
+		}
+	}
+}
+
+// compares the engine fields of two versions of an action
+func (d *differ) diffAction(nodeUUID flows.NodeUUID, old, new flows.Action) {
+	if old.Type() != new.Type() {
+		d.diffValue(&Change{Type: ChangeActionChanged, NodeUUID: nodeUUID, ActionUUID: new.UUID(), Field: "type"}, old.Type(), new.Type())
+		return
+	}
+
+	oldValues := make(map[string]reflect.Value)
+	inspect.Fields(old, func(ef *inspect.EngineField, v reflect.Value) { oldValues[ef.JSONName] = v })
+
+	inspect.Fields(new, func(ef *inspect.EngineField, v reflect.Value) {
+		if ef.JSONName == "uuid" || ef.JSONName == "type" || ef.JSONName == "-" {
+			return
+		}
+
+		c := &Change{Type: ChangeActionChanged, NodeUUID: nodeUUID, ActionUUID: new.UUID(), Field: ef.JSONName, Evaluated: ef.Evaluated}
+		d.diffValue(c, oldValues[ef.JSONName].Interface(), v.Interface())
+	})
+}
+
+// compares two versions of a router by their JSON properties, with categories compared separately
+func (d *differ) diffRouter(nodeUUID flows.NodeUUID, old, new flows.Router) {
+	if old == nil && new == nil {
+		return
+	}
+	if old == nil {
+		d.add(&Change{Type: ChangeRouterAdded, NodeUUID: nodeUUID})
+		return
+	}
+	if new == nil {
+		d.add(&Change{Type: ChangeRouterRemoved, NodeUUID: nodeUUID})
+		return
+	}
+
+	oldProps, newProps := routerProperties(old), routerProperties(new)
+
+	keys := make([]string, 0, len(newProps))
+	for k := range oldProps {
+		keys = append(keys, k)
+	}
+	for k := range newProps {
+		if _, seen := oldProps[k]; !seen {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "categories" {
+			continue
+		}
+		if bytes.Equal(oldProps[key], newProps[key]) {
+			continue
+		}
+
+		// lists of things with UUIDs like cases are compared item by item
+		oldItems, oldOK := itemsByUUID(oldProps[key])
+		newItems, newOK := itemsByUUID(newProps[key])
+		if oldOK && newOK {
+			d.diffItems(nodeUUID, key, oldItems, newItems)
+		} else {
+			d.add(&Change{Type: ChangeRouterChanged, NodeUUID: nodeUUID, Field: key, Old: oldProps[key], New: newProps[key]})
+		}
+	}
+
+	d.diffCategories(nodeUUID, old.Categories(), new.Categories())
+}
+
+func (d *differ) diffItems(nodeUUID flows.NodeUUID, field string, old, new []*routerItem) {
+	oldByUUID := make(map[uuids.UUID]*routerItem, len(old))
+	for _, i := range old {
+		oldByUUID[i.uuid] = i
+	}
+	newByUUID := make(map[uuids.UUID]bool, len(new))
+
+	for _, newItem := range new {
+		newByUUID[newItem.uuid] = true
+
+		oldItem := oldByUUID[newItem.uuid]
+		if oldItem == nil {
+			d.add(&Change{Type: ChangeRouterChanged, NodeUUID: nodeUUID, ItemUUID: newItem.uuid, Field: field, New: newItem.data})
+		} else if !bytes.Equal(oldItem.data, newItem.data) {
+			d.add(&Change{Type: ChangeRouterChanged, NodeUUID: nodeUUID, ItemUUID: newItem.uuid, Field: field, Old: oldItem.data, New: newItem.data})
+		}
+	}
+
+	for _, oldItem := range old {
+		if !newByUUID[oldItem.uuid] {
+			d.add(&Change{Type: ChangeRouterChanged, NodeUUID: nodeUUID, ItemUUID: oldItem.uuid, Field: field, Old: oldItem.data})
+		}
+	}
+}
+
+func (d *differ) diffCategories(nodeUUID flows.NodeUUID, old, new []flows.Category) {
+	oldByUUID := make(map[flows.CategoryUUID]flows.Category, len(old))
+	for _, c := range old {
+		oldByUUID[c.UUID()] = c
+	}
+	newByUUID := make(map[flows.CategoryUUID]bool, len(new))
+
+	for _, newCat := range new {
+		newByUUID[newCat.UUID()] = true
+
+		oldCat := oldByUUID[newCat.UUID()]
+		if oldCat == nil {
+			d.add(&Change{Type: ChangeCategoryAdded, NodeUUID: nodeUUID, ItemUUID: uuids.UUID(newCat.UUID())})
+			continue
+		}
+
+		d.diffValue(&Change{Type: ChangeCategoryChanged, NodeUUID: nodeUUID, ItemUUID: uuids.UUID(newCat.UUID()), Field: "name"}, oldCat.Name(), newCat.Name())
+		d.diffValue(&Change{Type: ChangeCategoryChanged, NodeUUID: nodeUUID, ItemUUID: uuids.UUID(newCat.UUID()), Field: "exit_uuid"}, oldCat.ExitUUID(), newCat.ExitUUID())
+	}
+
+	for _, oldCat := range old {
+		if !newByUUID[oldCat.UUID()] {
+			d.add(&Change{Type: ChangeCategoryRemoved, NodeUUID: nodeUUID, ItemUUID: uuids.UUID(oldCat.UUID())})
+		}
+	}
+}
+
+func (d *differ) diffExits(nodeUUID flows.NodeUUID, old, new []flows.Exit) {
+	oldByUUID := make(map[flows.ExitUUID]flows.Exit, len(old))
+	for _, e := range old {
+		oldByUUID[e.UUID()] = e
+	}
+	newByUUID := make(map[flows.ExitUUID]bool, len(new))
+
+	for _, newExit := range new {
+		newByUUID[newExit.UUID()] = true
+
+		oldExit := oldByUUID[newExit.UUID()]
+		if oldExit == nil {
+			d.add(&Change{Type: ChangeExitAdded, NodeUUID: nodeUUID, ItemUUID: uuids.UUID(newExit.UUID())})
+			continue
+		}
+
+		d.diffValue(&Change{Type: ChangeExitChanged, NodeUUID: nodeUUID, ItemUUID: uuids.UUID(newExit.UUID()), Field: "destination_uuid"}, oldExit.DestinationUUID(), newExit.DestinationUUID())
+	}
+
+	for _, oldExit := range old {
+		if !newByUUID[oldExit.UUID()] {
+			d.add(&Change{Type: ChangeExitRemoved, NodeUUID: nodeUUID, ItemUUID: uuids.UUID(oldExit.UUID())})
+		}
+	}
+}
+
+// compares translations, ordered by language, item UUID and property
+func (d *differ) diffLocalization(old, new flows.Localization) {
+	type key struct {
+		lang     envs.Language
+		itemUUID uuids.UUID
+		property string
+	}
+
+	oldTexts, newTexts := make(map[key][]string), make(map[key][]string)
+	enumerate := func(l flows.Localization, texts map[key][]string) {
+		if typed, ok := l.(localization); ok {
+			for lang, translation := range typed {
+				translation.Enumerate(func(itemUUID uuids.UUID, property string, values []string) {
+					texts[key{lang, itemUUID, property}] = values
+				})
+			}
+		}
+	}
+	enumerate(old, oldTexts)
+	enumerate(new, newTexts)
+
+	keys := make([]key, 0, len(oldTexts)+len(newTexts))
+	for k := range oldTexts {
+		keys = append(keys, k)
+	}
+	for k := range newTexts {
+		if _, seen := oldTexts[k]; !seen {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].lang != keys[j].lang {
+			return keys[i].lang < keys[j].lang
+		}
+		if keys[i].itemUUID != keys[j].itemUUID {
+			return keys[i].itemUUID < keys[j].itemUUID
+		}
+		return keys[i].property < keys[j].property
+	})
+
+	for _, k := range keys {
+		oldValues, inOld := oldTexts[k]
+		newValues, inNew := newTexts[k]
+		c := &Change{ItemUUID: k.itemUUID, Language: k.lang, Field: k.property}
+
+		if !inOld {
+			c.Type, c.New = ChangeTranslationAdded, jsonx.MustMarshal(newValues)
+			d.add(c)
+		} else if !inNew {
+			c.Type, c.Old = ChangeTranslationRemoved, jsonx.MustMarshal(oldValues)
+			d.add(c)
+		} else {
+			c.Type = ChangeTranslationChanged
+			d.diffValue(c, oldValues, newValues)
+		}
+	}
+}
+
+// adds the given change if the JSON encodings of the old and new values differ
+func (d *differ) diffValue(c *Change, old, new interface{}) {
+	oldJSON, newJSON := jsonx.MustMarshal(old), jsonx.MustMarshal(new)
+	if !bytes.Equal(oldJSON, newJSON) {
+		c.Old, c.New = oldJSON, newJSON
+		d.add(c)
+	}
+}
+
+// gets the position of a node in the editor from the flow's UI JSON
+func nodePosition(flow flows.Flow, nodeUUID flows.NodeUUID) interface{} {
+	var position interface{}
+	if data, _, _, err := jsonparser.Get(flow.UI(), "nodes", string(nodeUUID), "position"); err == nil {
+		json.Unmarshal(data, &position)
+	}
+	return position
+}
+
+type routerItem struct {
+	uuid uuids.UUID
+	data json.RawMessage
+}
+
+// parses the given JSON as a list of objects which all have UUIDs
+func itemsByUUID(data json.RawMessage) ([]*routerItem, bool) {
+	var raw []json.RawMessage
+	if len(data) == 0 || json.Unmarshal(data, &raw) != nil {
+		return nil, false
+	}
+
+	items := make([]*routerItem, len(raw))
+	for i, r := range raw {
+		uuid, err := jsonparser.GetString(r, "uuid")
+		if err != nil || uuid == "" {
+			return nil, false
+		}
+		items[i] = &routerItem{uuid: uuids.UUID(uuid), data: r}
+	}
+	return items, true
+}
+
+// gets the top level JSON properties of a router
+func routerProperties(r flows.Router) map[string]json.RawMessage {
+	props := make(map[string]json.RawMessage)
+	jsonx.MustUnmarshal(jsonx.MustMarshal(r), &props)
+
+	for k, v := range props {
+		var generic interface{}
+		jsonx.MustUnmarshal(v, &generic)
+		props[k] = jsonx.MustMarshal(generic)
+	}
+	return props
+}
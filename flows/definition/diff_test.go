@@ -0,0 +1,44 @@
+package definition_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/flows/definition"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/jsonx"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	oldJSON, err := os.ReadFile("testdata/diff_old.json")
+	require.NoError(t, err)
+	newJSON, err := os.ReadFile("testdata/diff_new.json")
+	require.NoError(t, err)
+
+	oldFlow, err := definition.ReadFlow(oldJSON, nil)
+	require.NoError(t, err)
+	newFlow, err := definition.ReadFlow(newJSON, nil)
+	require.NoError(t, err)
+
+	// a flow compared with itself has no changes
+	assert.Equal(t, []*definition.Change{}, definition.Diff(oldFlow, oldFlow))
+
+	changes := definition.Diff(oldFlow, newFlow)
+
+	descriptions := make([]string, len(changes))
+	for i, c := range changes {
+		descriptions[i] = c.Description()
+	}
+	test.AssertSnapshot(t, "descriptions", strings.Join(descriptions, "\n"))
+	marshaled, err := jsonx.MarshalPretty(changes)
+	require.NoError(t, err)
+	test.AssertSnapshot(t, "changes", string(marshaled))
+
+	// and going backwards reverses everything
+	reversed := definition.Diff(newFlow, oldFlow)
+	assert.Equal(t, len(changes), len(reversed))
+}
@@ -108,8 +108,9 @@ func (f *flow) validate() error {
 	return nil
 }
 
-// Inspect enumerates dependencies, results etc
-func (f *flow) Inspect(sa flows.SessionAssets) *flows.Inspection {
+// Inspect enumerates dependencies, results etc. If languages are given, these are the languages which action text
+// should be translated into, otherwise that's the languages the flow already has translations for.
+func (f *flow) Inspect(sa flows.SessionAssets, languages ...envs.Language) *flows.Inspection {
 	templates, assetRefs, parentRefs := f.extract()
 
 	return &flows.Inspection{
@@ -117,7 +118,7 @@ func (f *flow) Inspect(sa flows.SessionAssets) *flows.Inspection {
 		Results:      flows.NewResultSpecs(f.extractResults()),
 		WaitingExits: f.extractExitsFromWaits(),
 		ParentRefs:   parentRefs,
-		Issues:       issues.Check(sa, f, templates, assetRefs),
+		Issues:       issues.Check(sa, f, templates, assetRefs, languages),
 	}
 }
 
@@ -690,7 +690,7 @@ func migrateRuleSet(lang envs.Language, r RuleSet, validDests map[uuids.UUID]boo
 
 		router = newSwitchRouter(wait, resultName, categories, operand, cases, defaultCategory)
 	case "random":
-		router = newRandomRouter(resultName, categories)
+		router = newRandomRouter(resultName, categories, migrateRandomWeights(lang, r, categories))
 		uiType = UINodeTypeSplitByRandom
 
 	case "airtime":
@@ -752,6 +752,50 @@ type categoryAndExit struct {
 	exit     migratedExit
 }
 
+// migrates the between buckets of a legacy random ruleset to category weights, or nil if they're even or unparseable
+func migrateRandomWeights(baseLanguage envs.Language, r RuleSet, categories []migratedCategory) []decimal.Decimal {
+	widthsByCategory := make(map[string]decimal.Decimal, len(categories))
+
+	for _, rule := range r.Rules {
+		if rule.Test.Type != "between" {
+			return nil
+		}
+
+		test := betweenTest{}
+		if err := jsonx.Unmarshal(rule.Test.Data, &test); err != nil {
+			return nil
+		}
+		min, err := decimal.NewFromString(test.Min)
+		if err != nil {
+			return nil
+		}
+		max, err := decimal.NewFromString(test.Max)
+		if err != nil {
+			return nil
+		}
+
+		baseName := rule.Category.Base(baseLanguage)
+		widthsByCategory[baseName] = widthsByCategory[baseName].Add(max.Sub(min))
+	}
+
+	weights := make([]decimal.Decimal, len(categories))
+	uneven := false
+
+	for i, category := range categories {
+		name, _ := category["name"].(string)
+		weights[i] = widthsByCategory[name]
+
+		if !weights[i].Equal(weights[0]) {
+			uneven = true
+		}
+	}
+
+	if !uneven {
+		return nil
+	}
+	return weights
+}
+
 // migrates a set of legacy rules to sets of categories, cases and exits
 func migrateRules(baseLanguage envs.Language, r RuleSet, validDests map[uuids.UUID]bool, localization migratedLocalization, uiConfig NodeUIConfig) ([]migratedCase, []migratedCategory, uuids.UUID, uuids.UUID, []migratedExit, error) {
 	cases := make([]migratedCase, 0, len(r.Rules))
@@ -0,0 +1,843 @@
+package legacy
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/actions"
+	"github.com/developc3ntro/omni-goflow/flows/definition/legacy/expressions"
+	"github.com/developc3ntro/omni-goflow/flows/routers"
+	"github.com/developc3ntro/omni-goflow/flows/routers/waits"
+	"github.com/developc3ntro/omni-goflow/flows/routers/waits/hints"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/uuids"
+
+	"github.com/buger/jsonparser"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// the legacy spec version we export to
+const exportVersion = "11.12"
+
+var exportFlowTypes = map[flows.FlowType]string{
+	flows.FlowTypeMessaging:        "M",
+	flows.FlowTypeVoice:            "V",
+	flows.FlowTypeMessagingOffline: "S",
+}
+
+// the reverse of testTypeMappings for tests which can be exported as is
+var exportTestTypes = map[string]string{
+	"has_all_words":      "contains",
+	"has_any_word":       "contains_any",
+	"has_beginning":      "starts",
+	"has_date":           "date",
+	"has_date_eq":        "date_equal",
+	"has_date_gt":        "date_after",
+	"has_date_lt":        "date_before",
+	"has_district":       "district",
+	"has_email":          "has_email",
+	"has_group":          "in_group",
+	"has_number":         "number",
+	"has_number_between": "between",
+	"has_number_eq":      "eq",
+	"has_number_gt":      "gt",
+	"has_number_gte":     "gte",
+	"has_number_lt":      "lt",
+	"has_number_lte":     "lte",
+	"has_only_phrase":    "contains_only_phrase",
+	"has_pattern":        "regex",
+	"has_phone":          "phone",
+	"has_phrase":         "contains_phrase",
+	"has_state":          "state",
+	"has_text":           "not_empty",
+	"has_ward":           "ward",
+}
+
+// legacy rule sets which split on a single contact field or flow result
+var contactFieldOperand = regexp.MustCompile(`^@contact\.\w+$`)
+var flowFieldOperand = regexp.MustCompile(`^@flow\.\w+$`)
+
+// ExportDefinition exports the given flow to the legacy format. Only the subset of actions and routers which have
+// legacy equivalents can be exported, and an error is returned for anything else.
+func ExportDefinition(flow flows.Flow) (json.RawMessage, error) {
+	flowType, supported := exportFlowTypes[flow.Type()]
+	if !supported {
+		return nil, errors.Errorf("flows of type %s can't be exported", flow.Type())
+	}
+
+	e := &exporter{flow: flow, nodeTypes: make(map[flows.NodeUUID]string, len(flow.Nodes()))}
+	for _, node := range flow.Nodes() {
+		if node.Router() != nil {
+			e.nodeTypes[node.UUID()] = "R"
+		} else {
+			e.nodeTypes[node.UUID()] = "A"
+		}
+	}
+
+	actionSets := make([]map[string]interface{}, 0)
+	ruleSets := make([]map[string]interface{}, 0)
+	var entry flows.NodeUUID
+
+	for i, node := range flow.Nodes() {
+		if i == 0 {
+			entry = node.UUID()
+		}
+
+		if node.Router() != nil {
+			ruleSet, err := e.exportRuleSet(node)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error exporting node[uuid=%s]", node.UUID())
+			}
+			ruleSets = append(ruleSets, ruleSet)
+		} else {
+			actionSet, err := e.exportActionSet(node)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error exporting node[uuid=%s]", node.UUID())
+			}
+			actionSets = append(actionSets, actionSet)
+		}
+	}
+
+	exported := map[string]interface{}{
+		"version":       exportVersion,
+		"base_language": flow.Language(),
+		"flow_type":     flowType,
+		"entry":         entry,
+		"action_sets":   actionSets,
+		"rule_sets":     ruleSets,
+		"metadata": map[string]interface{}{
+			"uuid":     flow.UUID(),
+			"name":     flow.Name(),
+			"revision": flow.Revision(),
+			"expires":  flow.ExpireAfterMinutes(),
+		},
+	}
+
+	return jsonx.Marshal(exported)
+}
+
+type exporter struct {
+	flow      flows.Flow
+	nodeTypes map[flows.NodeUUID]string
+}
+
+// exports a node without a router as an action set
+func (e *exporter) exportActionSet(node flows.Node) (map[string]interface{}, error) {
+	exportedActions := make([]map[string]interface{}, len(node.Actions()))
+	for i, action := range node.Actions() {
+		exported, err := e.exportAction(action)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error exporting action[uuid=%s, type=%s]", action.UUID(), action.Type())
+		}
+		exportedActions[i] = exported
+	}
+
+	exit := node.Exits()[0]
+	x, y := e.position(node)
+
+	return map[string]interface{}{
+		"uuid":        node.UUID(),
+		"x":           x,
+		"y":           y,
+		"destination": nullIfEmpty(string(exit.DestinationUUID())),
+		"exit_uuid":   exit.UUID(),
+		"actions":     exportedActions,
+	}, nil
+}
+
+// exports the given action to its legacy equivalent
+func (e *exporter) exportAction(action flows.Action) (map[string]interface{}, error) {
+	exported := map[string]interface{}{"uuid": action.UUID()}
+	var err error
+
+	switch a := action.(type) {
+	case *actions.SendMsgAction:
+		if a.Templating != nil {
+			return nil, errors.New("message templates can't be exported")
+		}
+		if a.Topic != flows.NilMsgTopic {
+			return nil, errors.New("message topics can't be exported")
+		}
+		exported["type"] = "reply"
+		exported["send_all"] = a.AllURNs
+		err = e.exportMsg(exported, action, a.Text, a.Attachments, a.QuickReplies)
+
+	case *actions.SendBroadcastAction:
+		if len(a.URNs) > 0 || a.ContactQuery != "" {
+			return nil, errors.New("broadcasts to URNs or contact queries can't be exported")
+		}
+		exported["type"] = "send"
+		if err = e.exportMsg(exported, action, a.Text, a.Attachments, a.QuickReplies); err != nil {
+			return nil, err
+		}
+		exported["contacts"] = exportContacts(a.Contacts)
+		if exported["groups"], err = exportGroups(a.Groups); err != nil {
+			return nil, err
+		}
+		exported["variables"], err = exportVariables(a.LegacyVars, false)
+
+	case *actions.AddContactGroupsAction:
+		exported["type"] = "add_group"
+		exported["groups"], err = exportGroups(a.Groups)
+
+	case *actions.RemoveContactGroupsAction:
+		exported["type"] = "del_group"
+		exported["groups"], err = exportGroups(a.Groups) // no groups means remove from all groups
+
+	case *actions.SetContactFieldAction:
+		exported["type"] = "save"
+		exported["field"] = a.Field.Key
+		exported["label"] = a.Field.Name
+		exported["value"], err = expressions.ExportTemplate(a.Value)
+
+	case *actions.SetContactNameAction:
+		exported["type"] = "save"
+		exported["field"] = "name"
+		exported["label"] = "Contact Name"
+		exported["value"], err = expressions.ExportTemplate(a.Name)
+
+	case *actions.AddContactURNAction:
+		exported["type"] = "save"
+		exported["field"] = a.Scheme
+		exported["label"] = a.Scheme
+		exported["value"], err = expressions.ExportTemplate(a.Path)
+
+	case *actions.SetContactLanguageAction:
+		if strings.Contains(a.Language, "@") {
+			return nil, errors.New("languages set from expressions can't be exported")
+		}
+		exported["type"] = "lang"
+		exported["lang"] = a.Language
+		exported["name"] = a.Language
+
+	case *actions.SetContactChannelAction:
+		if a.Channel == nil {
+			return nil, errors.New("clearing the contact channel can't be exported")
+		}
+		exported["type"] = "channel"
+		exported["channel"] = a.Channel.UUID
+		exported["name"] = a.Channel.Name
+
+	case *actions.AddInputLabelsAction:
+		exported["type"] = "add_label"
+		exported["labels"], err = exportLabels(a.Labels)
+
+	case *actions.SendEmailAction:
+		if len(a.Cc) > 0 || len(a.Bcc) > 0 || a.ReplyTo != "" || a.HTML != "" || len(a.Attachments) > 0 {
+			return nil, errors.New("emails with cc, bcc, reply to, HTML or attachments can't be exported")
+		}
+		exported["type"] = "email"
+		if exported["emails"], err = exportTemplates(a.Addresses); err != nil {
+			return nil, err
+		}
+		if exported["subject"], err = expressions.ExportTemplate(a.Subject); err != nil {
+			return nil, err
+		}
+		exported["msg"], err = expressions.ExportTemplate(a.Body)
+
+	case *actions.EnterFlowAction:
+		if !a.Terminal {
+			return nil, errors.New("non-terminal enter_flow actions can only be exported on nodes with subflow routers")
+		}
+		exported["type"] = "flow"
+		exported["flow"] = map[string]interface{}{"uuid": a.Flow.UUID, "name": a.Flow.Name}
+
+	case *actions.StartSessionAction:
+		if len(a.URNs) > 0 || a.ContactQuery != "" {
+			return nil, errors.New("starting sessions for URNs or contact queries can't be exported")
+		}
+		exported["type"] = "trigger-flow"
+		exported["flow"] = map[string]interface{}{"uuid": a.Flow.UUID, "name": a.Flow.Name}
+		exported["contacts"] = exportContacts(a.Contacts)
+		if exported["groups"], err = exportGroups(a.Groups); err != nil {
+			return nil, err
+		}
+		exported["variables"], err = exportVariables(a.LegacyVars, a.CreateContact)
+
+	case *actions.SayMsgAction:
+		exported["type"] = "say"
+		if exported["msg"], err = e.translations(uuids.UUID(action.UUID()), "text", a.Text, true); err != nil {
+			return nil, err
+		}
+		exported["recording"], err = e.translations(uuids.UUID(action.UUID()), "audio_url", a.AudioURL, false)
+
+	case *actions.PlayAudioAction:
+		exported["type"] = "play"
+		exported["url"], err = expressions.ExportTemplate(a.AudioURL)
+
+	default:
+		return nil, errors.Errorf("unable to export action type: %s", action.Type())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return exported, nil
+}
+
+// exports the text, attachments and quick replies of a message action
+func (e *exporter) exportMsg(exported map[string]interface{}, action flows.Action, text string, attachments []string, quickReplies []string) error {
+	actionUUID := uuids.UUID(action.UUID())
+	var err error
+
+	if exported["msg"], err = e.translations(actionUUID, "text", text, true); err != nil {
+		return err
+	}
+
+	if len(attachments) > 1 {
+		return errors.New("messages with more than one attachment can't be exported")
+	}
+	if len(attachments) == 1 {
+		if exported["media"], err = e.translations(actionUUID, "attachments", attachments[0], true); err != nil {
+			return err
+		}
+	}
+
+	if len(quickReplies) > 0 {
+		replies := make([]Translations, len(quickReplies))
+		for i, reply := range quickReplies {
+			if replies[i], err = e.translationsAt(actionUUID, "quick_replies", i, reply, true); err != nil {
+				return err
+			}
+		}
+		exported["quick_replies"] = replies
+	}
+	return nil
+}
+
+// exports a node with a router as a rule set
+func (e *exporter) exportRuleSet(node flows.Node) (map[string]interface{}, error) {
+	router := node.Router()
+	x, y := e.position(node)
+
+	ruleSet := map[string]interface{}{
+		"uuid":         node.UUID(),
+		"x":            x,
+		"y":            y,
+		"label":        router.ResultName(),
+		"operand":      "@step.value",
+		"config":       map[string]interface{}{},
+		"finished_key": nil,
+	}
+
+	if len(node.Actions()) > 1 {
+		return nil, errors.New("nodes with routers and more than one action can't be exported")
+	}
+
+	var action flows.Action
+	if len(node.Actions()) == 1 {
+		action = node.Actions()[0]
+	}
+
+	if random, isRandom := router.(*routers.RandomRouter); isRandom {
+		if router.Wait() != nil || action != nil {
+			return nil, errors.New("random routers with waits or actions can't be exported")
+		}
+		if random.Sticky() {
+			return nil, errors.New("sticky random routers can't be exported")
+		}
+
+		rules, err := e.exportRandomRules(node, random)
+		if err != nil {
+			return nil, err
+		}
+		ruleSet["ruleset_type"] = "random"
+		ruleSet["rules"] = rules
+		return ruleSet, nil
+	}
+
+	switch_, isSwitch := router.(*routers.SwitchRouter)
+	if !isSwitch {
+		return nil, errors.Errorf("unable to export router type: %s", router.Type())
+	}
+
+	var defaultTest map[string]interface{}
+
+	switch a := action.(type) {
+	case nil:
+		if err := e.exportSwitchType(ruleSet, switch_); err != nil {
+			return nil, err
+		}
+
+	case *actions.EnterFlowAction:
+		if a.Terminal || (switch_.Operand() != "@child.status" && switch_.Operand() != "@child.run.status") {
+			return nil, errors.New("enter_flow actions can only be exported on nodes which route on the child status")
+		}
+		ruleSet["ruleset_type"] = "subflow"
+		ruleSet["config"] = map[string]interface{}{"flow": map[string]interface{}{"uuid": a.Flow.UUID, "name": a.Flow.Name}}
+
+	case *actions.CallWebhookAction:
+		config, err := exportWebhookConfig(a)
+		if err != nil {
+			return nil, err
+		}
+		ruleSet["ruleset_type"] = "webhook"
+		ruleSet["label"] = a.ResultName
+		ruleSet["config"] = config
+		defaultTest = map[string]interface{}{"type": "webhook_status", "status": "failure"}
+
+	case *actions.CallResthookAction:
+		ruleSet["ruleset_type"] = "resthook"
+		ruleSet["label"] = a.ResultName
+		ruleSet["config"] = map[string]interface{}{"resthook": a.Resthook}
+		defaultTest = map[string]interface{}{"type": "webhook_status", "status": "failure"}
+
+	default:
+		return nil, errors.Errorf("nodes with routers and %s actions can't be exported", action.Type())
+	}
+
+	if hint, isDigits := e.digitsHint(router); isDigits && hint.TerminatedBy != "" {
+		ruleSet["finished_key"] = hint.TerminatedBy
+	}
+
+	rules, err := e.exportSwitchRules(node, switch_, ruleSet["ruleset_type"].(string), defaultTest)
+	if err != nil {
+		return nil, err
+	}
+	ruleSet["rules"] = rules
+	return ruleSet, nil
+}
+
+// works out the type and operand of a rule set for a switch router without an action
+func (e *exporter) exportSwitchType(ruleSet map[string]interface{}, router *routers.SwitchRouter) error {
+	if router.Wait() != nil {
+		wait, isMsg := router.Wait().(*waits.MsgWait)
+		if !isMsg {
+			return errors.Errorf("%s waits can't be exported", router.Wait().Type())
+		}
+		if router.Operand() != "@input" && router.Operand() != "@input.text" {
+			return errors.New("waits can only be exported on routers which route on the input")
+		}
+
+		switch h := wait.Hint().(type) {
+		case nil:
+			ruleSet["ruleset_type"] = "wait_message"
+		case *hints.AudioHint:
+			if e.flow.Type() == flows.FlowTypeVoice {
+				ruleSet["ruleset_type"] = "wait_recording"
+			} else {
+				ruleSet["ruleset_type"] = "wait_audio"
+			}
+		case *hints.ImageHint:
+			ruleSet["ruleset_type"] = "wait_photo"
+		case *hints.VideoHint:
+			ruleSet["ruleset_type"] = "wait_video"
+		case *hints.LocationHint:
+			ruleSet["ruleset_type"] = "wait_gps"
+		case *hints.DigitsHint:
+			if h.Count != nil && *h.Count == 1 {
+				ruleSet["ruleset_type"] = "wait_digit"
+			} else if h.TerminatedBy != "" {
+				ruleSet["ruleset_type"] = "wait_digits"
+			} else {
+				return errors.New("digits hints for more than one digit without a terminating key can't be exported")
+			}
+		default:
+			return errors.Errorf("%s hints can't be exported", wait.Hint().Type())
+		}
+		return nil
+	}
+
+	if router.Operand() == "@contact.groups" {
+		ruleSet["ruleset_type"] = "group"
+		return nil
+	}
+
+	operand, err := expressions.ExportTemplate(router.Operand())
+	if err != nil {
+		return err
+	}
+	ruleSet["operand"] = operand
+
+	switch {
+	case contactFieldOperand.MatchString(operand):
+		ruleSet["ruleset_type"] = "contact_field"
+	case flowFieldOperand.MatchString(operand):
+		ruleSet["ruleset_type"] = "flow_field"
+	default:
+		ruleSet["ruleset_type"] = "expression"
+	}
+	return nil
+}
+
+// exports the cases and categories of a switch router as legacy rules
+func (e *exporter) exportSwitchRules(node flows.Node, router *routers.SwitchRouter, ruleSetType string, defaultTest map[string]interface{}) ([]map[string]interface{}, error) {
+	rules := make([]map[string]interface{}, 0, len(router.Cases())+1)
+	usedCategories := make(map[flows.CategoryUUID]bool)
+	usedExits := make(map[flows.ExitUUID]bool)
+
+	for _, kase := range router.Cases() {
+		test, err := e.exportTest(ruleSetType, kase)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error exporting case[uuid=%s]", kase.UUID)
+		}
+
+		rule, err := e.exportRule(node, kase.CategoryUUID, kase.UUID, test, usedExits)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+		usedCategories[kase.CategoryUUID] = true
+	}
+
+	if router.Wait() != nil && router.Wait().Timeout() != nil {
+		timeout := router.Wait().Timeout()
+		if timeout.Seconds()%60 != 0 {
+			return nil, errors.Errorf("timeout of %d seconds can't be exported as legacy timeouts are in minutes", timeout.Seconds())
+		}
+
+		test := map[string]interface{}{"type": "timeout", "minutes": timeout.Seconds() / 60}
+		rule, err := e.exportRule(node, timeout.CategoryUUID(), uuids.New(), test, usedExits)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+		usedCategories[timeout.CategoryUUID()] = true
+	}
+
+	if router.DefaultCategoryUUID() != "" {
+		if defaultTest == nil {
+			defaultTest = map[string]interface{}{"type": "true"}
+		}
+
+		rule, err := e.exportRule(node, router.DefaultCategoryUUID(), uuids.New(), defaultTest, usedExits)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+		usedCategories[router.DefaultCategoryUUID()] = true
+	}
+
+	for _, category := range router.Categories() {
+		if !usedCategories[category.UUID()] {
+			return nil, errors.Errorf("category '%s' has no cases and isn't the default or timeout category", category.Name())
+		}
+	}
+
+	return rules, nil
+}
+
+// exports the categories of a random router as legacy rules with between tests
+func (e *exporter) exportRandomRules(node flows.Node, router *routers.RandomRouter) ([]map[string]interface{}, error) {
+	categories := router.Categories()
+	weights := router.Weights()
+	if len(weights) == 0 {
+		weights = make([]decimal.Decimal, len(categories))
+		for i := range weights {
+			weights[i] = decimal.NewFromInt(1)
+		}
+	}
+
+	total := decimal.Zero
+	for _, weight := range weights {
+		total = total.Add(weight)
+	}
+
+	rules := make([]map[string]interface{}, len(categories))
+	usedExits := make(map[flows.ExitUUID]bool)
+	cumulative := decimal.Zero
+
+	for i, category := range categories {
+		min := cumulative.Div(total).Round(4)
+		cumulative = cumulative.Add(weights[i])
+		max := cumulative.Div(total).Round(4)
+
+		test := map[string]interface{}{"type": "between", "min": min.String(), "max": max.String()}
+
+		rule, err := e.exportRule(node, category.UUID(), uuids.New(), test, usedExits)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = rule
+	}
+
+	return rules, nil
+}
+
+// exports a rule which takes the given category. Rules which take an exit for the first time use the exit UUID as
+// their UUID, as migrating a legacy flow does the reverse.
+func (e *exporter) exportRule(node flows.Node, categoryUUID flows.CategoryUUID, ruleUUID uuids.UUID, test map[string]interface{}, usedExits map[flows.ExitUUID]bool) (map[string]interface{}, error) {
+	var category flows.Category
+	for _, c := range node.Router().Categories() {
+		if c.UUID() == categoryUUID {
+			category = c
+		}
+	}
+	var exit flows.Exit
+	for _, x := range node.Exits() {
+		if category != nil && x.UUID() == category.ExitUUID() {
+			exit = x
+		}
+	}
+	if exit == nil {
+		return nil, errors.Errorf("no category or exit with UUID %s", categoryUUID)
+	}
+
+	if !usedExits[exit.UUID()] {
+		ruleUUID = uuids.UUID(exit.UUID())
+		usedExits[exit.UUID()] = true
+	}
+
+	name, err := e.translations(uuids.UUID(category.UUID()), "name", category.Name(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	var destinationType interface{}
+	if exit.DestinationUUID() != "" {
+		destinationType = e.nodeTypes[exit.DestinationUUID()]
+	}
+
+	return map[string]interface{}{
+		"uuid":             ruleUUID,
+		"category":         name,
+		"destination":      nullIfEmpty(string(exit.DestinationUUID())),
+		"destination_type": destinationType,
+		"test":             test,
+	}, nil
+}
+
+// exports the given router case as a legacy test
+func (e *exporter) exportTest(ruleSetType string, kase *routers.Case) (map[string]interface{}, error) {
+	args := kase.Arguments
+
+	switch ruleSetType {
+	case "subflow":
+		if kase.Type != "has_only_text" || len(args) != 1 || (args[0] != "completed" && args[0] != "expired") {
+			return nil, errors.New("subflow routers can only be exported with cases for completed and expired")
+		}
+		return map[string]interface{}{"type": "subflow", "exit_type": args[0]}, nil
+
+	case "webhook", "resthook":
+		if kase.Type != "has_only_text" || len(args) != 1 || args[0] != "Success" {
+			return nil, errors.New("webhook routers can only be exported with a case for success")
+		}
+		return map[string]interface{}{"type": "webhook_status", "status": "success"}, nil
+	}
+
+	legacyType, supported := exportTestTypes[kase.Type]
+	if !supported {
+		return nil, errors.Errorf("unable to export '%s' tests", kase.Type)
+	}
+
+	test := map[string]interface{}{"type": legacyType}
+	var err error
+
+	switch legacyType {
+	case "date", "has_email", "not_empty", "number", "phone", "state":
+		// tests that take no arguments
+
+	case "eq", "gt", "gte", "lt", "lte", "date_equal", "date_after", "date_before", "district":
+		if len(args) != 1 {
+			return nil, errors.Errorf("expected 1 argument for %s test", kase.Type)
+		}
+		test["test"], err = expressions.ExportTemplate(args[0])
+
+	case "between":
+		if len(args) != 2 {
+			return nil, errors.Errorf("expected 2 arguments for %s test", kase.Type)
+		}
+		if test["min"], err = expressions.ExportTemplate(args[0]); err != nil {
+			return nil, err
+		}
+		test["max"], err = expressions.ExportTemplate(args[1])
+
+	case "ward":
+		if len(args) != 2 {
+			return nil, errors.Errorf("expected 2 arguments for %s test", kase.Type)
+		}
+		if test["district"], err = expressions.ExportTemplate(args[0]); err != nil {
+			return nil, err
+		}
+		test["state"], err = expressions.ExportTemplate(args[1])
+
+	case "contains", "contains_any", "contains_phrase", "contains_only_phrase", "regex", "starts":
+		if len(args) != 1 {
+			return nil, errors.Errorf("expected 1 argument for %s test", kase.Type)
+		}
+		test["test"], err = e.translationsAt(kase.UUID, "arguments", 0, args[0], legacyType != "regex")
+
+	case "in_group":
+		if len(args) < 1 {
+			return nil, errors.Errorf("expected group UUID argument for %s test", kase.Type)
+		}
+		group := map[string]interface{}{"uuid": args[0], "name": ""}
+		if len(args) > 1 {
+			group["name"] = args[1]
+		}
+		test["test"] = group
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return test, nil
+}
+
+// gets the digits hint on the wait of the given router, if there is one
+func (e *exporter) digitsHint(router flows.Router) (*hints.DigitsHint, bool) {
+	if wait, isMsg := router.Wait().(*waits.MsgWait); isMsg {
+		hint, isDigits := wait.Hint().(*hints.DigitsHint)
+		return hint, isDigits
+	}
+	return nil, false
+}
+
+// gets the position of the given node in the editor
+func (e *exporter) position(node flows.Node) (int, int) {
+	x, _ := jsonparser.GetInt(e.flow.UI(), "nodes", string(node.UUID()), "position", "left")
+	y, _ := jsonparser.GetInt(e.flow.UI(), "nodes", string(node.UUID()), "position", "top")
+	return int(x), int(y)
+}
+
+// gets the base and translated values of a localized property as legacy translations
+func (e *exporter) translations(itemUUID uuids.UUID, property string, base string, isTemplate bool) (Translations, error) {
+	return e.translationsAt(itemUUID, property, 0, base, isTemplate)
+}
+
+// gets the base and translated values of the item at the given index of a localized property as legacy translations
+func (e *exporter) translationsAt(itemUUID uuids.UUID, property string, index int, base string, isTemplate bool) (Translations, error) {
+	values := map[envs.Language]string{e.flow.Language(): base}
+
+	if e.flow.Localization() != nil {
+		for _, lang := range e.flow.Localization().Languages() {
+			translated := e.flow.Localization().GetItemTranslation(lang, itemUUID, property)
+			if index < len(translated) && translated[index] != "" {
+				values[lang] = translated[index]
+			}
+		}
+	}
+
+	translations := make(Translations, len(values))
+	for lang, value := range values {
+		if isTemplate {
+			exported, err := expressions.ExportTemplate(value)
+			if err != nil {
+				return nil, err
+			}
+			value = exported
+		}
+		translations[lang] = value
+	}
+	return translations, nil
+}
+
+func exportWebhookConfig(a *actions.CallWebhookAction) (map[string]interface{}, error) {
+	if a.Body != "" && a.Body != legacyWebhookPayload {
+		return nil, errors.New("webhooks with custom bodies can't be exported")
+	}
+
+	url, err := expressions.ExportURLTemplate(a.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]map[string]interface{}, 0, len(a.Headers))
+	for _, name := range sortedKeys(a.Headers) {
+		// legacy POST webhooks always send JSON
+		if a.Method == "POST" && strings.EqualFold(name, "Content-Type") && a.Headers[name] == "application/json" {
+			continue
+		}
+
+		value, err := expressions.ExportTemplate(a.Headers[name])
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, map[string]interface{}{"name": name, "value": value})
+	}
+
+	return map[string]interface{}{
+		"webhook":         url,
+		"webhook_action":  a.Method,
+		"webhook_headers": headers,
+	}, nil
+}
+
+func exportContacts(contacts []*flows.ContactReference) []map[string]interface{} {
+	exported := make([]map[string]interface{}, len(contacts))
+	for i, contact := range contacts {
+		exported[i] = map[string]interface{}{"uuid": contact.UUID, "name": contact.Name}
+	}
+	return exported
+}
+
+func exportGroups(groups []*assets.GroupReference) ([]interface{}, error) {
+	exported := make([]interface{}, len(groups))
+	for i, group := range groups {
+		if group.Variable() {
+			// legacy flows used name expressions as references
+			nameMatch, err := expressions.ExportTemplate(group.NameMatch)
+			if err != nil {
+				return nil, err
+			}
+			exported[i] = nameMatch
+		} else {
+			exported[i] = map[string]interface{}{"uuid": group.UUID, "name": group.Name}
+		}
+	}
+	return exported, nil
+}
+
+func exportLabels(labels []*assets.LabelReference) ([]interface{}, error) {
+	exported := make([]interface{}, len(labels))
+	for i, label := range labels {
+		if label.Variable() {
+			nameMatch, err := expressions.ExportTemplate(label.NameMatch)
+			if err != nil {
+				return nil, err
+			}
+			exported[i] = nameMatch
+		} else {
+			exported[i] = map[string]interface{}{"uuid": label.UUID, "name": label.Name}
+		}
+	}
+	return exported, nil
+}
+
+func exportVariables(vars []string, createContact bool) ([]map[string]interface{}, error) {
+	exported := make([]map[string]interface{}, 0, len(vars)+1)
+	for _, v := range vars {
+		id, err := expressions.ExportTemplate(v)
+		if err != nil {
+			return nil, err
+		}
+		exported = append(exported, map[string]interface{}{"id": id})
+	}
+	if createContact {
+		exported = append(exported, map[string]interface{}{"id": "@new_contact"})
+	}
+	return exported, nil
+}
+
+func exportTemplates(templates []string) ([]string, error) {
+	exported := make([]string, len(templates))
+	for i, t := range templates {
+		var err error
+		if exported[i], err = expressions.ExportTemplate(t); err != nil {
+			return nil, err
+		}
+	}
+	return exported, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
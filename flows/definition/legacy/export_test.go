@@ -0,0 +1,167 @@
+package legacy_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/flows/definition"
+	"github.com/developc3ntro/omni-goflow/flows/definition/legacy"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/uuids"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportDefinition(t *testing.T) {
+	uuids.SetGenerator(uuids.NewSeededGenerator(12345))
+	defer uuids.SetGenerator(uuids.DefaultGenerator)
+
+	flowJSON, err := os.ReadFile("testdata/export.json")
+	require.NoError(t, err)
+
+	flow, err := definition.ReadFlow(flowJSON, nil)
+	require.NoError(t, err)
+
+	exported, err := legacy.ExportDefinition(flow)
+	require.NoError(t, err)
+
+	assert.True(t, legacy.IsPossibleDefinition(exported))
+
+	pretty, err := test.NormalizeJSON(exported)
+	require.NoError(t, err)
+	test.AssertSnapshot(t, "exported", string(pretty))
+
+	// check that the exported flow can be migrated back into a valid flow
+	migrated, err := legacy.MigrateDefinition(exported, "http://temba.io/")
+	require.NoError(t, err)
+
+	reread, err := definition.ReadFlow(migrated, nil)
+	require.NoError(t, err)
+	assert.Equal(t, flow.UUID(), reread.UUID())
+	assert.Equal(t, len(flow.Nodes()), len(reread.Nodes()))
+}
+
+func TestExportDefinitionErrors(t *testing.T) {
+	tcs := []struct {
+		node string
+		err  string
+	}{
+		{
+			node: `{
+				"uuid": "a58be63b-907d-4a1a-856b-0bb5579d7507",
+				"actions": [
+					{"uuid": "f01d693b-2af2-49fb-9e38-146eb00937e9", "type": "send_msg", "text": "Hi @globals.org_name"}
+				],
+				"exits": [{"uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"}]
+			}`,
+			err: "error exporting node[uuid=a58be63b-907d-4a1a-856b-0bb5579d7507]: error exporting action[uuid=f01d693b-2af2-49fb-9e38-146eb00937e9, type=send_msg]: error evaluating @globals.org_name: globals.org_name has no legacy equivalent",
+		},
+		{
+			node: `{
+				"uuid": "a58be63b-907d-4a1a-856b-0bb5579d7507",
+				"actions": [
+					{"uuid": "f01d693b-2af2-49fb-9e38-146eb00937e9", "type": "send_msg", "text": "Hi", "attachments": ["image:http://a.jpg", "image:http://b.jpg"]}
+				],
+				"exits": [{"uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"}]
+			}`,
+			err: "error exporting node[uuid=a58be63b-907d-4a1a-856b-0bb5579d7507]: error exporting action[uuid=f01d693b-2af2-49fb-9e38-146eb00937e9, type=send_msg]: messages with more than one attachment can't be exported",
+		},
+		{
+			node: `{
+				"uuid": "a58be63b-907d-4a1a-856b-0bb5579d7507",
+				"actions": [
+					{"uuid": "f01d693b-2af2-49fb-9e38-146eb00937e9", "type": "set_run_result", "name": "Color", "value": "red"}
+				],
+				"exits": [{"uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"}]
+			}`,
+			err: "error exporting node[uuid=a58be63b-907d-4a1a-856b-0bb5579d7507]: error exporting action[uuid=f01d693b-2af2-49fb-9e38-146eb00937e9, type=set_run_result]: unable to export action type: set_run_result",
+		},
+		{
+			node: `{
+				"uuid": "a58be63b-907d-4a1a-856b-0bb5579d7507",
+				"actions": [
+					{"uuid": "f01d693b-2af2-49fb-9e38-146eb00937e9", "type": "enter_flow", "flow": {"uuid": "b7cf0d83-f1c9-411c-96fd-c511a4cfa86d", "name": "Other"}}
+				],
+				"exits": [{"uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"}]
+			}`,
+			err: "error exporting node[uuid=a58be63b-907d-4a1a-856b-0bb5579d7507]: error exporting action[uuid=f01d693b-2af2-49fb-9e38-146eb00937e9, type=enter_flow]: non-terminal enter_flow actions can only be exported on nodes with subflow routers",
+		},
+		{
+			node: `{
+				"uuid": "a58be63b-907d-4a1a-856b-0bb5579d7507",
+				"router": {
+					"type": "switch",
+					"operand": "@input.text",
+					"cases": [
+						{"uuid": "8ab03f8c-9d0a-4a2c-9a7c-6c6b2d3e6e57", "type": "has_time", "arguments": [], "category_uuid": "c8b7a8ff-5f6c-4e2b-8f5c-4a9a5d8f4a2b"}
+					],
+					"categories": [{"uuid": "c8b7a8ff-5f6c-4e2b-8f5c-4a9a5d8f4a2b", "name": "Match", "exit_uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"}]
+				},
+				"exits": [{"uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"}]
+			}`,
+			err: "error exporting node[uuid=a58be63b-907d-4a1a-856b-0bb5579d7507]: error exporting case[uuid=8ab03f8c-9d0a-4a2c-9a7c-6c6b2d3e6e57]: unable to export 'has_time' tests",
+		},
+		{
+			node: `{
+				"uuid": "a58be63b-907d-4a1a-856b-0bb5579d7507",
+				"router": {
+					"type": "switch",
+					"wait": {"type": "msg", "timeout": {"seconds": 90, "category_uuid": "c8b7a8ff-5f6c-4e2b-8f5c-4a9a5d8f4a2b"}},
+					"operand": "@input",
+					"default_category_uuid": "2e7c2a8f-6b2e-4f5a-9d1c-7c3f2f9a1b2c",
+					"categories": [
+						{"uuid": "2e7c2a8f-6b2e-4f5a-9d1c-7c3f2f9a1b2c", "name": "Other", "exit_uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"},
+						{"uuid": "c8b7a8ff-5f6c-4e2b-8f5c-4a9a5d8f4a2b", "name": "No Response", "exit_uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"}
+					]
+				},
+				"exits": [{"uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"}]
+			}`,
+			err: "error exporting node[uuid=a58be63b-907d-4a1a-856b-0bb5579d7507]: timeout of 90 seconds can't be exported as legacy timeouts are in minutes",
+		},
+		{
+			node: `{
+				"uuid": "a58be63b-907d-4a1a-856b-0bb5579d7507",
+				"router": {
+					"type": "random",
+					"sticky": true,
+					"categories": [
+						{"uuid": "2e7c2a8f-6b2e-4f5a-9d1c-7c3f2f9a1b2c", "name": "A", "exit_uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"},
+						{"uuid": "c8b7a8ff-5f6c-4e2b-8f5c-4a9a5d8f4a2b", "name": "B", "exit_uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"}
+					]
+				},
+				"exits": [{"uuid": "3ecaf8b1-c10b-4d62-b9dd-b3a24e6e8a45"}]
+			}`,
+			err: "error exporting node[uuid=a58be63b-907d-4a1a-856b-0bb5579d7507]: sticky random routers can't be exported",
+		},
+	}
+
+	for _, tc := range tcs {
+		flow, err := definition.ReadFlow([]byte(`{
+			"uuid": "76f0a02f-3b75-4b86-9064-e9195e1b3a02",
+			"name": "Test",
+			"spec_version": "13.1.0",
+			"language": "eng",
+			"type": "messaging",
+			"nodes": [`+tc.node+`]
+		}`), nil)
+		require.NoError(t, err)
+
+		_, err = legacy.ExportDefinition(flow)
+		assert.EqualError(t, err, tc.err)
+	}
+
+	// background flows have no legacy equivalent
+	flow, err := definition.ReadFlow([]byte(`{
+		"uuid": "76f0a02f-3b75-4b86-9064-e9195e1b3a02",
+		"name": "Test",
+		"spec_version": "13.1.0",
+		"language": "eng",
+		"type": "messaging_background",
+		"nodes": []
+	}`), nil)
+	require.NoError(t, err)
+
+	_, err = legacy.ExportDefinition(flow)
+	assert.EqualError(t, err, "flows of type messaging_background can't be exported")
+}
@@ -0,0 +1,153 @@
+package expressions
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/flows"
+
+	"github.com/pkg/errors"
+)
+
+// the reverse of the mappings used to migrate legacy context references
+var exportMappings []mapping
+
+// whole expressions which were produced by migrating legacy context references
+var exportExpressionMappings []mapping
+
+func init() {
+	var re = regexp.MustCompile
+
+	exportMappings = []mapping{
+		{re(`^((?:parent|child)\.)?contact$`), `${1}contact`, false},
+		{re(`^((?:parent|child)\.)?contact\.(uuid|id|name|first_name|created_on|language|groups)$`), `${1}contact.$2`, false},
+		{re(`^((?:parent|child)\.)?(?:contact\.)?fields\.(\w+)$`), `${1}contact.$2`, false},
+		{re(`^((?:parent|child)\.)?urns\.(\w+)$`), `${1}contact.$2.urn`, false},
+
+		{re(`^results$`), `flow`, false},
+		{re(`^results\.(\w+)(?:\.value)?$`), `flow.$1`, false},
+		{re(`^results\.(\w+)\.(?:category|category_localized)$`), `flow.$1.category`, false},
+		{re(`^results\.(\w+)\.input$`), `flow.$1.text`, false},
+		{re(`^results\.(\w+)\.created_on$`), `flow.$1.time`, false},
+
+		{re(`^(parent|child)\.results$`), `$1`, false},
+		{re(`^(parent|child)\.results\.(\w+)(?:\.value)?$`), `$1.$2`, false},
+		{re(`^(parent|child)\.results\.(\w+)\.(?:category|category_localized)$`), `$1.$2.category`, false},
+		{re(`^(parent|child)\.results\.(\w+)\.input$`), `$1.$2.text`, false},
+		{re(`^(parent|child)\.results\.(\w+)\.created_on$`), `$1.$2.time`, false},
+
+		{re(`^input$`), `step.value`, false},
+		{re(`^input\.text$`), `step.text`, false},
+		{re(`^input\.created_on$`), `step.time`, false},
+
+		{re(`^contact\.channel\.address$`), `channel`, false},
+		{re(`^contact\.channel\.name$`), `channel.name`, false},
+
+		{re(`^legacy_extra$`), `extra`, false},
+		{re(`^legacy_extra\.([\w\.]+)$`), `extra.$1`, false},
+	}
+
+	exportExpressionMappings = []mapping{
+		{re(`^now\(\)$`), `@date.now`, false},
+		{re(`^today\(\)$`), `@date.today`, false},
+		{re(`^format_date\(today\(\)\)$`), `@date.today`, false},
+		{re(`^(?:format_date\()?datetime_add\(now\(\), 1, "D"\)\)?$`), `@date.tomorrow`, false},
+		{re(`^(?:format_date\()?datetime_add\(now\(\), -1, "D"\)\)?$`), `@date.yesterday`, false},
+		{re(`^datetime_add\(today\(\), (-?\d+), "D"\)$`), `@(date.today + $1)`, false},
+		{re(`^format_urn\(((?:parent|child)\.)?urns\.(\w+)\)$`), `@${1}contact.$2.display`, false},
+		{re(`^default\(urn_parts\(((?:parent|child)\.)?urns\.(\w+)\)\.path, ""\)$`), `@${1}contact.$2`, false},
+		{re(`^urn_parts\(((?:parent|child)\.)?urns\.(\w+)\)\.path$`), `@${1}contact.$2.path`, false},
+		{re(`^join\(((?:parent|child)\.)?contact\.groups, ","\)$`), `@${1}contact.groups`, false},
+	}
+}
+
+// ExportTemplate will take a template in the current syntax and translate it to the legacy syntax. Only context
+// references which have legacy equivalents can be exported, and an error is returned for anything else.
+func ExportTemplate(template string) (string, error) {
+	return exportTemplate(template, false)
+}
+
+// ExportURLTemplate is like ExportTemplate but for URL templates where legacy expressions were implicitly URL encoded,
+// so url_encode calls can be removed
+func ExportURLTemplate(template string) (string, error) {
+	return exportTemplate(template, true)
+}
+
+var urlEncodeRegex = regexp.MustCompile(`^url_encode\((.+)\)$`)
+
+func exportTemplate(template string, urlEncoded bool) (string, error) {
+	var buf bytes.Buffer
+	scanner := excellent.NewXScanner(strings.NewReader(template), flows.RunContextTopLevels)
+	scanner.SetUnescapeBody(false)
+	templateErrors := excellent.NewTemplateErrors()
+
+	for tokenType, token := scanner.Scan(); tokenType != excellent.EOF; tokenType, token = scanner.Scan() {
+		switch tokenType {
+		case excellent.BODY:
+			buf.WriteString(token)
+		case excellent.IDENTIFIER:
+			exported, err := ExportContextReference(token)
+			if err != nil {
+				templateErrors.Add("@"+token, err.Error())
+				buf.WriteString("@" + token)
+			} else {
+				buf.WriteString("@" + exported)
+			}
+		case excellent.EXPRESSION:
+			if urlEncoded {
+				token = urlEncodeRegex.ReplaceAllString(strings.TrimSpace(token), "$1")
+			}
+
+			exported, err := exportExpression(token)
+			if err != nil {
+				templateErrors.Add(fmt.Sprintf("@(%s)", token), err.Error())
+				buf.WriteString("@(" + token + ")")
+			} else {
+				buf.WriteString(exported)
+			}
+		}
+	}
+
+	if templateErrors.HasErrors() {
+		return buf.String(), templateErrors
+	}
+	return buf.String(), nil
+}
+
+// ExportContextReference translates a context reference in the current syntax to a legacy context reference
+func ExportContextReference(path string) (string, error) {
+	path = strings.ToLower(path)
+
+	for _, mapping := range exportMappings {
+		if mapping.pattern.MatchString(path) {
+			return mapping.pattern.ReplaceAllString(path, mapping.replace), nil
+		}
+	}
+
+	return "", errors.Errorf("%s has no legacy equivalent", path)
+}
+
+// exports a complete expression, which is only possible for simple context references and those expressions which are
+// created by migrating legacy context references
+func exportExpression(expression string) (string, error) {
+	expression = strings.TrimSpace(expression)
+
+	if identifierRegex.MatchString(expression) {
+		exported, err := ExportContextReference(expression)
+		if err != nil {
+			return "", err
+		}
+		return "@" + exported, nil
+	}
+
+	for _, mapping := range exportExpressionMappings {
+		if mapping.pattern.MatchString(expression) {
+			return mapping.pattern.ReplaceAllString(expression, mapping.replace), nil
+		}
+	}
+
+	return "", errors.New("expression has no legacy equivalent")
+}
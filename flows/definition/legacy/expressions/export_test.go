@@ -0,0 +1,74 @@
+package expressions_test
+
+import (
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/flows/definition/legacy/expressions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		exported string
+		err      string
+	}{
+		{template: `Hi there`, exported: `Hi there`},
+		{template: `@contact`, exported: `@contact`},
+		{template: `Hi @contact.name`, exported: `Hi @contact.name`},
+		{template: `@CONTACT.First_Name`, exported: `@contact.first_name`},
+		{template: `@fields.gender`, exported: `@contact.gender`},
+		{template: `@contact.fields.gender`, exported: `@contact.gender`},
+		{template: `@parent.fields.gender`, exported: `@parent.contact.gender`},
+		{template: `@urns.tel`, exported: `@contact.tel.urn`},
+		{template: `@(format_urn(urns.tel))`, exported: `@contact.tel.display`},
+		{template: `@(default(urn_parts(urns.tel).path, ""))`, exported: `@contact.tel`},
+		{template: `@(urn_parts(urns.twitter).path)`, exported: `@contact.twitter.path`},
+		{template: `@(join(contact.groups, ","))`, exported: `@contact.groups`},
+		{template: `@results.color`, exported: `@flow.color`},
+		{template: `@results.color.value`, exported: `@flow.color`},
+		{template: `@results.color.category_localized`, exported: `@flow.color.category`},
+		{template: `@results.color.input`, exported: `@flow.color.text`},
+		{template: `@results.color.created_on`, exported: `@flow.color.time`},
+		{template: `@child.results.age`, exported: `@child.age`},
+		{template: `@parent.results.age.category`, exported: `@parent.age.category`},
+		{template: `You said @input`, exported: `You said @step.value`},
+		{template: `@input.text`, exported: `@step.text`},
+		{template: `@contact.channel.address`, exported: `@channel`},
+		{template: `@legacy_extra.address.state`, exported: `@extra.address.state`},
+		{template: `@(results.color)`, exported: `@flow.color`},
+		{template: `@(now())`, exported: `@date.now`},
+		{template: `@(format_date(today()))`, exported: `@date.today`},
+		{template: `@(datetime_add(today(), -3, "D"))`, exported: `@(date.today + -3)`},
+		{template: `email@@example.com`, exported: `email@@example.com`},
+		{
+			template: `Hi @contact.name, @globals.org_name says @(upper(fields.name))`,
+			exported: `Hi @contact.name, @globals.org_name says @(upper(fields.name))`,
+			err:      "error evaluating @globals.org_name: globals.org_name has no legacy equivalent, error evaluating @(upper(fields.name)): expression has no legacy equivalent",
+		},
+		{template: `@webhook.json`, exported: `@webhook.json`, err: "error evaluating @webhook.json: webhook.json has no legacy equivalent"},
+		{template: `http://example.com?q=@(url_encode(results.color))`, exported: `http://example.com?q=@(url_encode(results.color))`, err: "error evaluating @(url_encode(results.color)): expression has no legacy equivalent"},
+	}
+
+	for _, tc := range tests {
+		exported, err := expressions.ExportTemplate(tc.template)
+
+		assert.Equal(t, tc.exported, exported, "export mismatch for '%s'", tc.template)
+		if tc.err != "" {
+			assert.EqualError(t, err, tc.err, "error mismatch for '%s'", tc.template)
+		} else {
+			assert.NoError(t, err, "unexpected error for '%s'", tc.template)
+
+			// check that migrating the exported template gets us back to something equivalent
+			_, err := expressions.MigrateTemplate(exported, nil)
+			assert.NoError(t, err, "unexpected error re-migrating '%s'", exported)
+		}
+	}
+}
+
+func TestExportURLTemplate(t *testing.T) {
+	exported, err := expressions.ExportURLTemplate(`http://example.com?q=@(url_encode(results.color))&c=@(url_encode(format_urn(urns.tel)))&i=@input.text`)
+	assert.NoError(t, err)
+	assert.Equal(t, `http://example.com?q=@flow.color&c=@contact.tel.display&i=@step.text`, exported)
+}
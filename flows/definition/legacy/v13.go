@@ -229,7 +229,7 @@ func newSwitchRouter(wait migratedWait, resultName string, categories []migrated
 	return migratedRouter(d)
 }
 
-func newRandomRouter(resultName string, categories []migratedCategory) migratedRouter {
+func newRandomRouter(resultName string, categories []migratedCategory, weights []decimal.Decimal) migratedRouter {
 	d := map[string]interface{}{
 		"type":       "random",
 		"categories": categories,
@@ -237,6 +237,9 @@ func newRandomRouter(resultName string, categories []migratedCategory) migratedR
 	if resultName != "" {
 		d["result_name"] = resultName
 	}
+	if weights != nil {
+		d["weights"] = weights
+	}
 
 	return migratedRouter(d)
 }
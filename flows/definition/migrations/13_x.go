@@ -1,6 +1,8 @@
 package migrations
 
 import (
+	"fmt"
+
 	"github.com/nyaruka/gocommon/uuids"
 
 	"github.com/Masterminds/semver"
@@ -8,6 +10,8 @@ import (
 
 func init() {
 	registerMigration(semver.MustParse("13.1.0"), Migrate13_1)
+
+	registerDowngrade(semver.MustParse("13.1.0"), Downgrade13_1)
 }
 
 // Migrate13_1 adds UUID to send_msg templating
@@ -24,3 +28,41 @@ func Migrate13_1(f Flow) (Flow, error) {
 	}
 	return f, nil
 }
+
+// Downgrade13_1 removes UUID from send_msg templating, and with it any translations of the template variables
+func Downgrade13_1(f Flow) (Flow, []*Incompatibility, error) {
+	incompatibilities := make([]*Incompatibility, 0)
+	localization := f.Localization()
+
+	for _, node := range f.Nodes() {
+		for _, action := range node.Actions() {
+			if action.Type() == "send_msg" {
+				templating, _ := action["templating"].(map[string]interface{})
+				if templating == nil {
+					continue
+				}
+
+				templatingUUID, _ := templating["uuid"].(string)
+				delete(templating, "uuid")
+
+				if templatingUUID == "" {
+					continue
+				}
+
+				for _, lang := range localization.Languages() {
+					translations := localization.Translations(lang)
+					if _, found := translations[templatingUUID]; found {
+						delete(translations, templatingUUID)
+
+						actionUUID, _ := action["uuid"].(string)
+						incompatibilities = append(incompatibilities, &Incompatibility{
+							UUID:        uuids.UUID(actionUUID),
+							Description: fmt.Sprintf("translation of template variables to '%s' isn't supported", lang),
+						})
+					}
+				}
+			}
+		}
+	}
+	return f, incompatibilities, nil
+}
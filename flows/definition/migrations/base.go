@@ -116,15 +116,26 @@ func migrate(data []byte, from *semver.Version, to *semver.Version) ([]byte, err
 // Clone clones the given flow definition by replacing all UUIDs using the provided mapping and
 // generating new random UUIDs if they aren't in the mapping
 func Clone(data []byte, depMapping map[uuids.UUID]uuids.UUID) ([]byte, error) {
+	clone, _, err := CloneWithMapping(data, depMapping)
+	return clone, err
+}
+
+// CloneWithMapping is like Clone but also returns the mapping of every UUID in the original definition to its UUID in
+// the clone, including those from the provided mapping
+func CloneWithMapping(data []byte, depMapping map[uuids.UUID]uuids.UUID) ([]byte, map[uuids.UUID]uuids.UUID, error) {
 	clone, err := readFlow(data)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	remapUUIDs(clone, depMapping)
+	mapping := remapUUIDs(clone, depMapping)
 
 	// finally marshal back to JSON
-	return jsonx.Marshal(clone)
+	marshaled, err := jsonx.Marshal(clone)
+	if err != nil {
+		return nil, nil, err
+	}
+	return marshaled, mapping, nil
 }
 
 // reads a flow definition as a flow primitive
@@ -143,7 +154,7 @@ func readFlow(data []byte) (Flow, error) {
 }
 
 // remap all UUIDs in the flow
-func remapUUIDs(data map[string]interface{}, depMapping map[uuids.UUID]uuids.UUID) {
+func remapUUIDs(data map[string]interface{}, depMapping map[uuids.UUID]uuids.UUID) map[uuids.UUID]uuids.UUID {
 	// copy in the dependency mappings into a master mapping of all UUIDs
 	mapping := make(map[uuids.UUID]uuids.UUID)
 	for k, v := range depMapping {
@@ -191,6 +202,8 @@ func remapUUIDs(data map[string]interface{}, depMapping map[uuids.UUID]uuids.UUI
 	}
 
 	walk(data, objectCallback, arrayCallback)
+
+	return mapping
 }
 
 // extract the property names from a generic JSON object, sorted A-Z
@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"sort"
+
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/uuids"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+)
+
+// the earliest version that flows can be downgraded to
+var earliestVersion = semver.MustParse("13.0.0")
+
+// Incompatibility is a feature of a flow definition which can't be represented in an older spec version and so was
+// removed when the flow was downgraded
+type Incompatibility struct {
+	Version     string     `json:"version"`
+	UUID        uuids.UUID `json:"uuid,omitempty"`
+	Description string     `json:"description"`
+}
+
+// DowngradeFunc is a function that can downgrade a flow definition from one version to the previous version, returning
+// the features which couldn't be represented in the previous version
+type DowngradeFunc func(Flow) (Flow, []*Incompatibility, error)
+
+var registeredDowngrades = map[*semver.Version]DowngradeFunc{}
+
+// registers a downgrade from the given version
+func registerDowngrade(version *semver.Version, fn DowngradeFunc) {
+	registeredDowngrades[version] = fn
+}
+
+// RegisteredDowngrades gets all registered downgrades
+func RegisteredDowngrades() map[*semver.Version]DowngradeFunc {
+	return registeredDowngrades
+}
+
+// DowngradeToVersion downgrades the given flow definition to the given older version. Features which can't be
+// represented in that version are removed and returned as incompatibilities rather than silently dropped.
+func DowngradeToVersion(data []byte, to *semver.Version) ([]byte, []*Incompatibility, error) {
+	header := &Header13{}
+	if err := utils.UnmarshalAndValidate(data, header); err != nil {
+		return nil, nil, errors.Wrap(err, "unable to read flow header")
+	}
+
+	if to.LessThan(earliestVersion) {
+		return nil, nil, errors.Errorf("can't downgrade to versions earlier than %s", earliestVersion)
+	}
+	if to.GreaterThan(header.SpecVersion) {
+		return nil, nil, errors.Errorf("can't downgrade from %s to newer version %s", header.SpecVersion, to)
+	}
+
+	// get all versions which need to be undone
+	versions := make([]*semver.Version, 0)
+	for v := range registeredDowngrades {
+		if v.GreaterThan(to) && v.Compare(header.SpecVersion) <= 0 {
+			versions = append(versions, v)
+		}
+	}
+
+	// sorted by latest first
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i].GreaterThan(versions[j]) })
+
+	downgraded, err := readFlow(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	incompatibilities := make([]*Incompatibility, 0)
+
+	for _, version := range versions {
+		var found []*Incompatibility
+		downgraded, found, err = registeredDowngrades[version](downgraded)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "unable to downgrade from version %s", version.String())
+		}
+
+		for _, inc := range found {
+			inc.Version = version.String()
+		}
+		incompatibilities = append(incompatibilities, found...)
+	}
+
+	downgraded["spec_version"] = to.String()
+
+	// finally marshal back to JSON
+	marshaled, err := jsonx.Marshal(downgraded)
+	if err != nil {
+		return nil, nil, err
+	}
+	return marshaled, incompatibilities, nil
+}
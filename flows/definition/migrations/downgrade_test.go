@@ -0,0 +1,84 @@
+package migrations_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/flows/definition"
+	"github.com/developc3ntro/omni-goflow/flows/definition/migrations"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/jsonx"
+
+	"github.com/Masterminds/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDowngradeToVersion(t *testing.T) {
+	// get all versions in order, starting with the earliest version we can downgrade to
+	versions := []*semver.Version{semver.MustParse("13.0.0")}
+	for v := range migrations.RegisteredDowngrades() {
+		versions = append(versions, v)
+	}
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i].LessThan(versions[j]) })
+
+	for i, version := range versions[1:] {
+		previous := versions[i]
+
+		testsJSON, err := os.ReadFile(fmt.Sprintf("testdata/downgrades/%s.json", version.String()))
+		require.NoError(t, err)
+
+		tests := []struct {
+			Description       string          `json:"description"`
+			Original          json.RawMessage `json:"original"`
+			Downgraded        json.RawMessage `json:"downgraded"`
+			Incompatibilities json.RawMessage `json:"incompatibilities"`
+		}{}
+
+		err = jsonx.Unmarshal(testsJSON, &tests)
+		require.NoError(t, err, "unable to read tests for version %s", version)
+
+		for _, tc := range tests {
+			testName := fmt.Sprintf("version %s with '%s'", version, tc.Description)
+
+			actual, incompatibilities, err := migrations.DowngradeToVersion(tc.Original, previous)
+			assert.NoError(t, err, "unexpected error in %s", testName)
+
+			test.AssertEqualJSON(t, tc.Downgraded, actual, "downgrade mismatch in %s", testName)
+			test.AssertEqualJSON(t, tc.Incompatibilities, jsonx.MustMarshal(incompatibilities), "incompatibilities mismatch in %s", testName)
+
+			// check downgraded flow can be migrated back to a valid flow
+			_, err = definition.ReadFlow(actual, nil)
+			assert.NoError(t, err, "downgraded flow validation error in %s", testName)
+		}
+	}
+}
+
+func TestDowngradeToVersionErrors(t *testing.T) {
+	flow := []byte(`{
+		"uuid": "76f0a02f-3b75-4b86-9064-e9195e1b3a02",
+		"name": "Empty Flow",
+		"spec_version": "13.0.0",
+		"language": "eng",
+		"type": "messaging",
+		"nodes": []
+	}`)
+
+	_, _, err := migrations.DowngradeToVersion([]byte(`{}`), semver.MustParse("13.0.0"))
+	assert.EqualError(t, err, "unable to read flow header: field 'uuid' is required, field 'spec_version' is required")
+
+	_, _, err = migrations.DowngradeToVersion(flow, semver.MustParse("13.1.0"))
+	assert.EqualError(t, err, "can't downgrade from 13.0.0 to newer version 13.1.0")
+
+	_, _, err = migrations.DowngradeToVersion(flow, semver.MustParse("12.0.0"))
+	assert.EqualError(t, err, "can't downgrade to versions earlier than 13.0.0")
+
+	// downgrading to the same version is a noop
+	downgraded, incompatibilities, err := migrations.DowngradeToVersion(flow, semver.MustParse("13.0.0"))
+	assert.NoError(t, err)
+	assert.Len(t, incompatibilities, 0)
+	test.AssertEqualJSON(t, flow, downgraded, "downgrade mismatch")
+}
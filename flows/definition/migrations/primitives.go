@@ -52,3 +52,26 @@ func (r Router) Type() string {
 	d, _ := r["type"].(string)
 	return d
 }
+
+// Localization returns the localization of this flow
+func (f Flow) Localization() Localization {
+	d, _ := f["localization"].(map[string]interface{})
+	if d == nil {
+		return nil
+	}
+	return Localization(d)
+}
+
+// Localization holds the localization definition of a flow
+type Localization map[string]interface{}
+
+// Languages returns the languages in this localization, sorted A-Z
+func (l Localization) Languages() []string {
+	return objectProperties(l)
+}
+
+// Translations returns the item translations for the given language
+func (l Localization) Translations(lang string) map[string]interface{} {
+	d, _ := l[lang].(map[string]interface{})
+	return d
+}
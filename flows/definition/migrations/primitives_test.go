@@ -44,3 +44,18 @@ func TestMigrationPrimitives(t *testing.T) {
 	a = migrations.Action(map[string]interface{}{"type": "foo"}) // type set
 	assert.Equal(t, "foo", a.Type())
 }
+
+func TestLocalizationPrimitives(t *testing.T) {
+	f := migrations.Flow(map[string]interface{}{}) // localization not set
+	assert.Nil(t, f.Localization())
+
+	f = migrations.Flow(map[string]interface{}{"localization": map[string]interface{}{
+		"spa": map[string]interface{}{"3ba7b0a0-b2a6-4f89-b51e-9b9d8fca7e23": map[string]interface{}{}},
+		"fra": map[string]interface{}{},
+	}})
+	l := f.Localization()
+	assert.Equal(t, []string{"fra", "spa"}, l.Languages())
+	assert.Equal(t, map[string]interface{}{}, l.Translations("fra"))
+	assert.Len(t, l.Translations("spa"), 1)
+	assert.Nil(t, l.Translations("kin"))
+}
@@ -16,6 +16,7 @@ type sessionAssets struct {
 	classifiers *flows.ClassifierAssets
 	fields      *flows.FieldAssets
 	flows       flows.FlowAssets
+	functions   *flows.FunctionAssets
 	globals     *flows.GlobalAssets
 	groups      *flows.GroupAssets
 	labels      *flows.LabelAssets
@@ -43,6 +44,10 @@ func NewSessionAssets(env envs.Environment, source assets.Source, migrationConfi
 	if err != nil {
 		return nil, err
 	}
+	functions, err := source.Functions()
+	if err != nil {
+		return nil, err
+	}
 	globals, err := source.Globals()
 	if err != nil {
 		return nil, err
@@ -89,6 +94,7 @@ func NewSessionAssets(env envs.Environment, source assets.Source, migrationConfi
 		classifiers: flows.NewClassifierAssets(classifiers),
 		fields:      fieldAssets,
 		flows:       definition.NewFlowAssets(source, migrationConfig),
+		functions:   flows.NewFunctionAssets(functions),
 		globals:     flows.NewGlobalAssets(globals),
 		groups:      groupAssets,
 		labels:      flows.NewLabelAssets(labels),
@@ -106,6 +112,7 @@ func (s *sessionAssets) Channels() *flows.ChannelAssets       { return s.channel
 func (s *sessionAssets) Classifiers() *flows.ClassifierAssets { return s.classifiers }
 func (s *sessionAssets) Fields() *flows.FieldAssets           { return s.fields }
 func (s *sessionAssets) Flows() flows.FlowAssets              { return s.flows }
+func (s *sessionAssets) Functions() *flows.FunctionAssets     { return s.functions }
 func (s *sessionAssets) Globals() *flows.GlobalAssets         { return s.globals }
 func (s *sessionAssets) Groups() *flows.GroupAssets           { return s.groups }
 func (s *sessionAssets) Labels() *flows.LabelAssets           { return s.labels }
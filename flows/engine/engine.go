@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/excellent"
 	"github.com/developc3ntro/omni-goflow/flows"
 	"github.com/nyaruka/gocommon/uuids"
 )
@@ -14,6 +15,10 @@ type engine struct {
 	maxStepsPerSprint    int
 	maxResumesPerSession int
 	maxTemplateChars     int
+	maxFunctionDepth     int
+	maxFunctionCalls     int
+	expressionCache      *excellent.Cache
+	observers            []Observer
 }
 
 // NewSession creates a new session
@@ -42,6 +47,10 @@ func (e *engine) Services() flows.Services  { return e.services }
 func (e *engine) MaxStepsPerSprint() int    { return e.maxStepsPerSprint }
 func (e *engine) MaxResumesPerSession() int { return e.maxResumesPerSession }
 func (e *engine) MaxTemplateChars() int     { return e.maxTemplateChars }
+func (e *engine) MaxFunctionDepth() int     { return e.maxFunctionDepth }
+func (e *engine) MaxFunctionCalls() int     { return e.maxFunctionCalls }
+
+func (e *engine) ExpressionCache() *excellent.Cache { return e.expressionCache }
 
 var _ flows.Engine = (*engine)(nil)
 
@@ -62,6 +71,8 @@ func NewBuilder() *Builder {
 			maxStepsPerSprint:    100,
 			maxResumesPerSession: 500,
 			maxTemplateChars:     10000,
+			maxFunctionDepth:     10,
+			maxFunctionCalls:     1000,
 		},
 	}
 }
@@ -114,5 +125,34 @@ func (b *Builder) WithMaxTemplateChars(max int) *Builder {
 	return b
 }
 
+// WithMaxFunctionDepth sets the maximum depth of nested calls to functions defined as assets, which also limits recursion
+func (b *Builder) WithMaxFunctionDepth(max int) *Builder {
+	b.eng.maxFunctionDepth = max
+	return b
+}
+
+// WithMaxFunctionCalls sets the maximum number of calls to functions defined as assets when evaluating a template
+func (b *Builder) WithMaxFunctionCalls(max int) *Builder {
+	b.eng.maxFunctionCalls = max
+	return b
+}
+
+// WithExpressionCacheSize sets the maximum number of parsed expressions and scanned templates which are cached for
+// reuse across sessions. A size of zero, the default, disables caching.
+func (b *Builder) WithExpressionCacheSize(size int) *Builder {
+	if size > 0 {
+		b.eng.expressionCache = excellent.NewCache(size)
+	} else {
+		b.eng.expressionCache = nil
+	}
+	return b
+}
+
+// WithObserver adds an observer which will be notified of progress as sessions are started and resumed
+func (b *Builder) WithObserver(o Observer) *Builder {
+	b.eng.observers = append(b.eng.observers, o)
+	return b
+}
+
 // Build returns the final engine
 func (b *Builder) Build() flows.Engine { return b.eng }
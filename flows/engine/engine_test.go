@@ -17,6 +17,7 @@ func TestBuilder(t *testing.T) {
 
 	assert.Equal(t, 123, eng.MaxStepsPerSprint())
 	assert.Equal(t, 567, eng.MaxResumesPerSession())
+	assert.Nil(t, eng.ExpressionCache())
 
 	_, err := eng.Services().Email(nil)
 	assert.EqualError(t, err, "no email service factory configured")
@@ -39,4 +40,11 @@ func TestBuilder(t *testing.T) {
 	svc, err := eng.Services().Webhook(nil)
 	assert.NoError(t, err)
 	assert.Equal(t, webhookSvc, svc)
+
+	// enable caching of parsed expressions
+	eng = engine.NewBuilder().WithExpressionCacheSize(100).Build()
+	assert.NotNil(t, eng.ExpressionCache())
+
+	eng = engine.NewBuilder().WithExpressionCacheSize(100).WithExpressionCacheSize(0).Build()
+	assert.Nil(t, eng.ExpressionCache())
 }
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/nyaruka/gocommon/dates"
+)
+
+// Observer is notified in real time as the engine starts and resumes sessions, which allows callers to stream
+// progress, record metrics or trace slow actions without waiting for the final sprint. Callbacks are made
+// synchronously from the execution loop so implementations should return quickly.
+type Observer interface {
+	// NodeVisited is called after a node has been visited with the time taken to execute its actions and router
+	NodeVisited(session flows.Session, run flows.Run, step flows.Step, node flows.Node, elapsed time.Duration)
+
+	// ActionExecuted is called after an action has executed with the time taken and any error it returned
+	ActionExecuted(session flows.Session, run flows.Run, step flows.Step, action flows.Action, elapsed time.Duration, err error)
+
+	// EventLogged is called as each event is logged with the time elapsed since the start of the sprint
+	EventLogged(session flows.Session, event flows.Event, elapsed time.Duration)
+
+	// SprintEnded is called when a sprint has ended with its total duration
+	SprintEnded(session flows.Session, sprint flows.Sprint, elapsed time.Duration)
+}
+
+// the observers of a single sprint
+type sprintObservers struct {
+	session   flows.Session
+	observers []Observer
+	start     time.Time
+}
+
+func newSprintObservers(session flows.Session, observers []Observer) *sprintObservers {
+	o := &sprintObservers{session: session, observers: observers}
+
+	// only read the time if someone is observing, as time sources used in tests are affected by each read
+	if len(observers) > 0 {
+		o.start = dates.Now()
+	}
+	return o
+}
+
+// times the given function, returning a zero duration if there are no observers
+func (o *sprintObservers) time(f func()) time.Duration {
+	if len(o.observers) == 0 {
+		f()
+		return 0
+	}
+
+	start := dates.Now()
+	f()
+	return dates.Now().Sub(start)
+}
+
+func (o *sprintObservers) nodeVisited(run flows.Run, step flows.Step, node flows.Node, elapsed time.Duration) {
+	for _, obs := range o.observers {
+		obs.NodeVisited(o.session, run, step, node, elapsed)
+	}
+}
+
+func (o *sprintObservers) actionExecuted(run flows.Run, step flows.Step, action flows.Action, elapsed time.Duration, err error) {
+	for _, obs := range o.observers {
+		obs.ActionExecuted(o.session, run, step, action, elapsed, err)
+	}
+}
+
+func (o *sprintObservers) eventLogged(event flows.Event) {
+	if len(o.observers) > 0 {
+		elapsed := dates.Now().Sub(o.start)
+
+		for _, obs := range o.observers {
+			obs.EventLogged(o.session, event, elapsed)
+		}
+	}
+}
+
+func (o *sprintObservers) sprintEnded(sprint flows.Sprint) {
+	if len(o.observers) > 0 {
+		elapsed := dates.Now().Sub(o.start)
+
+		for _, obs := range o.observers {
+			obs.SprintEnded(o.session, sprint, elapsed)
+		}
+	}
+}
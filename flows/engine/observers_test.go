@@ -0,0 +1,92 @@
+package engine_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/engine"
+	"github.com/developc3ntro/omni-goflow/flows/resumes"
+	"github.com/developc3ntro/omni-goflow/flows/triggers"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/dates"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testObserver struct {
+	log []string
+}
+
+func (o *testObserver) NodeVisited(session flows.Session, run flows.Run, step flows.Step, node flows.Node, elapsed time.Duration) {
+	o.log = append(o.log, fmt.Sprintf("node %s (%s)", node.UUID(), elapsed))
+}
+
+func (o *testObserver) ActionExecuted(session flows.Session, run flows.Run, step flows.Step, action flows.Action, elapsed time.Duration, err error) {
+	o.log = append(o.log, fmt.Sprintf("action %s (%s) err=%v", action.Type(), elapsed, err))
+}
+
+func (o *testObserver) EventLogged(session flows.Session, event flows.Event, elapsed time.Duration) {
+	o.log = append(o.log, fmt.Sprintf("event %s (%s)", event.Type(), elapsed))
+}
+
+func (o *testObserver) SprintEnded(session flows.Session, sprint flows.Sprint, elapsed time.Duration) {
+	o.log = append(o.log, fmt.Sprintf("sprint ended with %d events (%s)", len(sprint.Events()), elapsed))
+}
+
+func TestObservers(t *testing.T) {
+	defer dates.SetNowSource(dates.DefaultNowSource)
+
+	// every read of the time advances it by one second
+	dates.SetNowSource(dates.NewSequentialNowSource(time.Date(2018, 4, 11, 13, 24, 30, 123456000, time.UTC)))
+
+	assetsJSON, err := os.ReadFile("testdata/timeout_test.json")
+	require.NoError(t, err)
+
+	sa, err := test.CreateSessionAssets(assetsJSON, "")
+	require.NoError(t, err)
+
+	contact := flows.NewEmptyContact(sa, "Bob", envs.Language("eng"), nil)
+	env := envs.NewBuilder().Build()
+	trigger := triggers.NewBuilder(env, assets.NewFlowReference("76f0a02f-3b75-4b86-9064-e9195e1b3a02", ""), contact).Manual().Build()
+
+	obs1, obs2 := &testObserver{}, &testObserver{}
+	eng := engine.NewBuilder().WithObserver(obs1).WithObserver(obs2).Build()
+
+	session, sprint, err := eng.NewSession(sa, trigger)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(sprint.Events()))
+
+	assert.Equal(t, []string{
+		"event msg_created (7s)",
+		"action send_msg (4s) err=<nil>",
+		"event msg_wait (11s)",
+		"node 46d51f50-58de-49da-8d13-dadbf322685d (11s)",
+		"sprint ended with 2 events (14s)",
+	}, obs1.log)
+	assert.Equal(t, obs1.log, obs2.log)
+
+	obs1.log = nil
+
+	_, err = session.Resume(resumes.NewWaitTimeout(nil, nil))
+	require.NoError(t, err)
+
+	// resuming doesn't revisit the waiting node but does visit the node after it
+	assert.Equal(t, []string{
+		"event wait_timed_out (3s)",
+		"event run_result_changed (9s)",
+		"event msg_created (16s)",
+		"action send_msg (4s) err=<nil>",
+		"node 091decfb-c9b0-4dcf-954e-04927f119fc8 (7s)",
+		"sprint ended with 3 events (20s)",
+	}, obs1.log)
+
+	// observers aren't required
+	_, _, err = engine.NewBuilder().Build().NewSession(sa, trigger)
+	assert.NoError(t, err)
+}
@@ -47,7 +47,7 @@ type session struct {
 	pushedFlow *pushedFlow
 	parentRun  flows.RunSummary
 
-	engine flows.Engine
+	engine *engine
 }
 
 func (s *session) Assets() flows.SessionAssets { return s.assets }
@@ -168,7 +168,8 @@ func (s *session) Engine() flows.Engine { return s.engine }
 
 // Start initializes this session with the given trigger and runs the flow to the first wait
 func (s *session) start(trigger flows.Trigger) (flows.Sprint, error) {
-	sprint := newEmptySprint()
+	sprint := s.newSprint()
+	defer sprint.observers.sprintEnded(sprint)
 
 	if err := s.prepareForSprint(); err != nil {
 		return sprint, err
@@ -191,7 +192,8 @@ func (s *session) start(trigger flows.Trigger) (flows.Sprint, error) {
 
 // Resume tries to resume a waiting session
 func (s *session) Resume(resume flows.Resume) (flows.Sprint, error) {
-	sprint := newEmptySprint()
+	sprint := s.newSprint()
+	defer sprint.observers.sprintEnded(sprint)
 
 	if err := s.prepareForSprint(); err != nil {
 		return sprint, err
@@ -213,6 +215,11 @@ func (s *session) Resume(resume flows.Resume) (flows.Sprint, error) {
 	return sprint, nil
 }
 
+// creates a new sprint which reports to the engine's observers
+func (s *session) newSprint() *sprint {
+	return newObservedSprint(s, s.engine.observers)
+}
+
 // prepares the session for starting/resuming
 func (s *session) prepareForSprint() error {
 	if s.parentRun == nil {
@@ -423,7 +430,11 @@ func (s *session) continueUntilWait(sprint *sprint, currentRun flows.Run, node f
 					return errors.Errorf("unable to find destination node %s in flow %s", destination, currentRun.Flow().UUID())
 				}
 
-				step, exit, operand, err = s.visitNode(sprint, currentRun, node, trigger)
+				elapsed := sprint.observers.time(func() {
+					step, exit, operand, err = s.visitNode(sprint, currentRun, node, trigger)
+				})
+				sprint.observers.nodeVisited(currentRun, step, node, elapsed)
+
 				if err != nil {
 					return err
 				}
@@ -458,7 +469,13 @@ func (s *session) visitNode(sprint *sprint, run flows.Run, node flows.Node, trig
 	// execute our node's actions
 	if node.Actions() != nil {
 		for _, action := range node.Actions() {
-			if err := action.Execute(run, step, sprint.logModifier, logEvent); err != nil {
+			var err error
+			elapsed := sprint.observers.time(func() {
+				err = action.Execute(run, step, sprint.logModifier, logEvent)
+			})
+			sprint.observers.actionExecuted(run, step, action, elapsed, err)
+
+			if err != nil {
 				return step, nil, "", errors.Wrapf(err, "error executing action[type=%s,uuid=%s]", action.Type(), action.UUID())
 			}
 
@@ -586,7 +603,7 @@ type sessionEnvelope struct {
 }
 
 // ReadSession decodes a session from the passed in JSON
-func readSession(eng flows.Engine, sessionAssets flows.SessionAssets, data json.RawMessage, missing assets.MissingCallback) (flows.Session, error) {
+func readSession(eng *engine, sessionAssets flows.SessionAssets, data json.RawMessage, missing assets.MissingCallback) (flows.Session, error) {
 	e := &sessionEnvelope{}
 	var err error
 
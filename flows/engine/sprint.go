@@ -51,20 +51,28 @@ type sprint struct {
 	modifiers []flows.Modifier
 	events    []flows.Event
 	segments  []flows.Segment
+
+	observers *sprintObservers
 }
 
 // creates a new empty sprint
 func newEmptySprint() *sprint {
+	return newObservedSprint(nil, nil)
+}
+
+// creates a new empty sprint whose progress is reported to the given observers
+func newObservedSprint(session flows.Session, observers []Observer) *sprint {
 	return &sprint{
 		modifiers: make([]flows.Modifier, 0, 10),
 		events:    make([]flows.Event, 0, 10),
 		segments:  make([]flows.Segment, 0, 10),
+		observers: newSprintObservers(session, observers),
 	}
 }
 
 // NewSprint creates a new sprint - engine doesn't use this but we do it when handling surveyor responses
 func NewSprint(modifiers []flows.Modifier, events []flows.Event, segments []flows.Segment) flows.Sprint {
-	return &sprint{modifiers: modifiers, events: events, segments: segments}
+	return &sprint{modifiers: modifiers, events: events, segments: segments, observers: newSprintObservers(nil, nil)}
 }
 
 func (s *sprint) Modifiers() []flows.Modifier { return s.modifiers }
@@ -77,6 +85,7 @@ func (s *sprint) logModifier(m flows.Modifier) {
 
 func (s *sprint) logEvent(e flows.Event) {
 	s.events = append(s.events, e)
+	s.observers.eventLogged(e)
 }
 
 func (s *sprint) logSegment(flow flows.Flow, node flows.Node, exit flows.Exit, operand string, dest flows.Node) {
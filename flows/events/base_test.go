@@ -17,6 +17,7 @@ import (
 	"github.com/developc3ntro/omni-goflow/flows/routers/waits/hints"
 	"github.com/developc3ntro/omni-goflow/services/webhooks"
 	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/developc3ntro/omni-goflow/utils"
 	"github.com/nyaruka/gocommon/dates"
 	"github.com/nyaruka/gocommon/httpx"
 	"github.com/nyaruka/gocommon/jsonx"
@@ -179,6 +180,40 @@ func TestEventMarshaling(t *testing.T) {
 				]
 			}`,
 		},
+		{
+			events.NewEmailServiceCalled(
+				[]*flows.HTTPLog{
+					{
+						HTTPTrace: &flows.HTTPTrace{
+							URL:        "https://api.sendgrid.com/v3/mail/send",
+							StatusCode: 202,
+							Status:     flows.CallStatusSuccess,
+							Request:    "POST /v3/mail/send HTTP/1.1\r\nHost: api.sendgrid.com\r\nUser-Agent: Go-http-client/1.1\r\nAccept-Encoding: gzip\r\n\r\n",
+							Response:   "HTTP/1.0 202 Accepted\r\nContent-Length: 0\r\n\r\n",
+							ElapsedMS:  12,
+						},
+						CreatedOn: dates.Now(),
+					},
+				},
+			),
+			`{
+				"type": "service_called",
+				"created_on": "2018-10-18T14:20:30.000123456Z",
+				"service": "email",
+				"http_logs": [
+					{
+						"url": "https://api.sendgrid.com/v3/mail/send",
+						"status_code": 202,
+						"status": "success",
+						"request": "POST /v3/mail/send HTTP/1.1\r\nHost: api.sendgrid.com\r\nUser-Agent: Go-http-client/1.1\r\nAccept-Encoding: gzip\r\n\r\n",
+						"response": "HTTP/1.0 202 Accepted\r\nContent-Length: 0\r\n\r\n",
+						"elapsed_ms": 12,
+						"retries": 0,
+						"created_on": "2018-10-18T14:20:30.000123456Z"
+					}
+				]
+			}`,
+		},
 		{
 			events.NewContactFieldChanged(
 				gender,
@@ -302,6 +337,7 @@ func TestEventMarshaling(t *testing.T) {
 								"name": "Support Tickets",
 								"uuid": "19dc6346-9623-4fe4-be80-538d493ecdf5"
 							},
+							"status": "open",
 							"topic": null,
 							"uuid": "e5f5a9b0-1c08-4e56-8f5c-92e00bc3cf52"
 						},
@@ -311,6 +347,7 @@ func TestEventMarshaling(t *testing.T) {
 								"name": "Bob"
 							},
 							"body": "What day is it?",
+							"status": "open",
 							"ticketer": {
 								"name": "Support Tickets",
 								"uuid": "19dc6346-9623-4fe4-be80-538d493ecdf5"
@@ -365,7 +402,7 @@ func TestEventMarshaling(t *testing.T) {
 			}`,
 		},
 		{
-			events.NewEmailSent([]string{"bob@nyaruka.com", "jim@nyaruka.com"}, "Update", "Flows are great!"),
+			events.NewEmailSent(&flows.Email{To: []string{"bob@nyaruka.com", "jim@nyaruka.com"}, Subject: "Update", Body: "Flows are great!"}),
 			`{
 				"created_on": "2018-10-18T14:20:30.000123456Z",
 				"type": "email_sent",
@@ -374,6 +411,30 @@ func TestEventMarshaling(t *testing.T) {
 				"body": "Flows are great!"
 			}`,
 		},
+		{
+			events.NewEmailSent(&flows.Email{
+				To:          []string{"bob@nyaruka.com"},
+				Cc:          []string{"jim@nyaruka.com"},
+				Bcc:         []string{"audit@nyaruka.com"},
+				ReplyTo:     "support@nyaruka.com",
+				Subject:     "Update",
+				Body:        "Flows are great!",
+				HTML:        "<p>Flows are <b>great</b>!</p>",
+				Attachments: []utils.Attachment{"application/pdf:https://example.com/flows.pdf"},
+			}),
+			`{
+				"created_on": "2018-10-18T14:20:30.000123456Z",
+				"type": "email_sent",
+				"to": ["bob@nyaruka.com"],
+				"cc": ["jim@nyaruka.com"],
+				"bcc": ["audit@nyaruka.com"],
+				"reply_to": "support@nyaruka.com",
+				"subject": "Update",
+				"body": "Flows are great!",
+				"html": "<p>Flows are <b>great</b>!</p>",
+				"attachments": ["application/pdf:https://example.com/flows.pdf"]
+			}`,
+		},
 		{
 			events.NewEnvironmentRefreshed(session.Environment()),
 			`{
@@ -554,10 +615,112 @@ func TestEventMarshaling(t *testing.T) {
 					"assignee": {
 						"email": "bob@nyaruka.com",
 						"name": "Bob"
-					}
+					},
+					"status": "open"
+				}
+			}`,
+		},
+		{
+			events.NewTicketClosed(ticket),
+			`{
+				"type": "ticket_closed",
+				"created_on": "2018-10-18T14:20:30.000123456Z",
+				"ticket": {
+					"uuid": "7481888c-07dd-47dc-bf22-ef7448696ffe",
+					"ticketer": {
+						"uuid": "19dc6346-9623-4fe4-be80-538d493ecdf5",
+						"name": "Support Tickets"
+					},
+					"topic": {
+						"uuid": "472a7a73-96cb-4736-b567-056d987cc5b4",
+						"name": "Weather"
+					},
+					"body": "Where are my cookies?",
+					"external_id": "1243252",
+					"assignee": {
+						"email": "bob@nyaruka.com",
+						"name": "Bob"
+					},
+					"status": "open"
+				}
+			}`,
+		},
+		{
+			events.NewTicketReopened(ticket),
+			`{
+				"type": "ticket_reopened",
+				"created_on": "2018-10-18T14:20:30.000123456Z",
+				"ticket": {
+					"uuid": "7481888c-07dd-47dc-bf22-ef7448696ffe",
+					"ticketer": {
+						"uuid": "19dc6346-9623-4fe4-be80-538d493ecdf5",
+						"name": "Support Tickets"
+					},
+					"topic": {
+						"uuid": "472a7a73-96cb-4736-b567-056d987cc5b4",
+						"name": "Weather"
+					},
+					"body": "Where are my cookies?",
+					"external_id": "1243252",
+					"assignee": {
+						"email": "bob@nyaruka.com",
+						"name": "Bob"
+					},
+					"status": "open"
 				}
 			}`,
 		},
+		{
+			events.NewTicketAssigned(ticket),
+			`{
+				"type": "ticket_assigned",
+				"created_on": "2018-10-18T14:20:30.000123456Z",
+				"ticket": {
+					"uuid": "7481888c-07dd-47dc-bf22-ef7448696ffe",
+					"ticketer": {
+						"uuid": "19dc6346-9623-4fe4-be80-538d493ecdf5",
+						"name": "Support Tickets"
+					},
+					"topic": {
+						"uuid": "472a7a73-96cb-4736-b567-056d987cc5b4",
+						"name": "Weather"
+					},
+					"body": "Where are my cookies?",
+					"external_id": "1243252",
+					"assignee": {
+						"email": "bob@nyaruka.com",
+						"name": "Bob"
+					},
+					"status": "open"
+				}
+			}`,
+		},
+		{
+			events.NewTicketNoteAdded(ticket, "Customer was very polite"),
+			`{
+				"type": "ticket_note_added",
+				"created_on": "2018-10-18T14:20:30.000123456Z",
+				"ticket": {
+					"uuid": "7481888c-07dd-47dc-bf22-ef7448696ffe",
+					"ticketer": {
+						"uuid": "19dc6346-9623-4fe4-be80-538d493ecdf5",
+						"name": "Support Tickets"
+					},
+					"topic": {
+						"uuid": "472a7a73-96cb-4736-b567-056d987cc5b4",
+						"name": "Weather"
+					},
+					"body": "Where are my cookies?",
+					"external_id": "1243252",
+					"assignee": {
+						"email": "bob@nyaruka.com",
+						"name": "Bob"
+					},
+					"status": "open"
+				},
+				"note": "Customer was very polite"
+			}`,
+		},
 		{
 			events.NewTicketerCalled(
 				assets.NewTicketerReference(assets.TicketerUUID("4b937f49-7fb7-43a5-8e57-14e2f028a471"), "Support"),
@@ -2,6 +2,7 @@ package events
 
 import (
 	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/utils"
 )
 
 func init() {
@@ -11,31 +12,45 @@ func init() {
 // TypeEmailSent is our type for the email event
 const TypeEmailSent string = "email_sent"
 
-// EmailSentEvent events are created when an action has sent an email.
+// EmailSentEvent events are created when an action has sent an email. The CC and BCC recipients,
+// reply-to address, HTML body and attachments are only included if the email had them.
 //
 //	{
 //	  "type": "email_sent",
 //	  "created_on": "2006-01-02T15:04:05Z",
 //	  "to": ["foo@bar.com"],
+//	  "cc": ["boss@bar.com"],
 //	  "subject": "Your activation token",
-//	  "body": "Your activation token is AAFFKKEE"
+//	  "body": "Your activation token is AAFFKKEE",
+//	  "html": "<p>Your activation token is <b>AAFFKKEE</b></p>",
+//	  "attachments": ["application/pdf:https://example.com/terms.pdf"]
 //	}
 //
 // @event email_sent
 type EmailSentEvent struct {
 	BaseEvent
 
-	To      []string `json:"to" validate:"required,min=1"`
-	Subject string   `json:"subject" validate:"required"`
-	Body    string   `json:"body"`
+	To          []string           `json:"to" validate:"required,min=1"`
+	Cc          []string           `json:"cc,omitempty"`
+	Bcc         []string           `json:"bcc,omitempty"`
+	ReplyTo     string             `json:"reply_to,omitempty"`
+	Subject     string             `json:"subject" validate:"required"`
+	Body        string             `json:"body"`
+	HTML        string             `json:"html,omitempty"`
+	Attachments []utils.Attachment `json:"attachments,omitempty"`
 }
 
-// NewEmailSent returns a new email event with the passed in subject, body and emails
-func NewEmailSent(to []string, subject string, body string) *EmailSentEvent {
+// NewEmailSent returns a new email event for the given email
+func NewEmailSent(email *flows.Email) *EmailSentEvent {
 	return &EmailSentEvent{
-		BaseEvent: NewBaseEvent(TypeEmailSent),
-		To:        to,
-		Subject:   subject,
-		Body:      body,
+		BaseEvent:   NewBaseEvent(TypeEmailSent),
+		To:          email.To,
+		Cc:          email.Cc,
+		Bcc:         email.Bcc,
+		ReplyTo:     email.ReplyTo,
+		Subject:     email.Subject,
+		Body:        email.Body,
+		HTML:        email.HTML,
+		Attachments: email.Attachments,
 	}
 }
@@ -51,6 +51,15 @@ func NewClassifierCalled(classifier *assets.ClassifierReference, httpLogs []*flo
 	}
 }
 
+// NewEmailServiceCalled returns a service called event for an email service
+func NewEmailServiceCalled(httpLogs []*flows.HTTPLog) *ServiceCalledEvent {
+	return &ServiceCalledEvent{
+		BaseEvent: NewBaseEvent(TypeServiceCalled),
+		Service:   "email",
+		HTTPLogs:  httpLogs,
+	}
+}
+
 // NewTicketerCalled returns a service called event for a ticketer
 func NewTicketerCalled(ticketer *assets.TicketerReference, httpLogs []*flows.HTTPLog) *ServiceCalledEvent {
 	return &ServiceCalledEvent{
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"github.com/developc3ntro/omni-goflow/flows"
+)
+
+func init() {
+	registerType(TypeTicketAssigned, func() flows.Event { return &TicketAssignedEvent{} })
+}
+
+// TypeTicketAssigned is the type for our ticket assigned events
+const TypeTicketAssigned string = "ticket_assigned"
+
+// TicketAssignedEvent events are created when a ticket is assigned to a different user.
+//
+//	{
+//	  "type": "ticket_assigned",
+//	  "created_on": "2006-01-02T15:04:05Z",
+//	  "ticket": {
+//	    "uuid": "2e677ae6-9b57-423c-b022-7950503eef35",
+//	    "ticketer": {
+//	      "uuid": "d605bb96-258d-4097-ad0a-080937db2212",
+//	      "name": "Support Tickets"
+//	    },
+//	    "topic": {
+//	      "uuid": "add17edf-0b6e-4311-bcd7-a64b2a459157",
+//	      "name": "Weather"
+//	    },
+//	    "body": "Where are my cookies?",
+//	    "external_id": "32526523",
+//	    "assignee": {"email": "jim@nyaruka.com", "name": "Jim"},
+//	    "status": "open"
+//	  }
+//	}
+//
+// @event ticket_assigned
+type TicketAssignedEvent struct {
+	BaseEvent
+
+	Ticket *Ticket `json:"ticket"`
+}
+
+// NewTicketAssigned returns a new ticket assigned event
+func NewTicketAssigned(ticket *flows.Ticket) *TicketAssignedEvent {
+	return &TicketAssignedEvent{
+		BaseEvent: NewBaseEvent(TypeTicketAssigned),
+		Ticket:    newTicket(ticket),
+	}
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"github.com/developc3ntro/omni-goflow/flows"
+)
+
+func init() {
+	registerType(TypeTicketClosed, func() flows.Event { return &TicketClosedEvent{} })
+}
+
+// TypeTicketClosed is the type for our ticket closed events
+const TypeTicketClosed string = "ticket_closed"
+
+// TicketClosedEvent events are created when a ticket is closed.
+//
+//	{
+//	  "type": "ticket_closed",
+//	  "created_on": "2006-01-02T15:04:05Z",
+//	  "ticket": {
+//	    "uuid": "2e677ae6-9b57-423c-b022-7950503eef35",
+//	    "ticketer": {
+//	      "uuid": "d605bb96-258d-4097-ad0a-080937db2212",
+//	      "name": "Support Tickets"
+//	    },
+//	    "topic": {
+//	      "uuid": "add17edf-0b6e-4311-bcd7-a64b2a459157",
+//	      "name": "Weather"
+//	    },
+//	    "body": "Where are my cookies?",
+//	    "external_id": "32526523",
+//	    "assignee": {"email": "bob@nyaruka.com", "name": "Bob"},
+//	    "status": "closed"
+//	  }
+//	}
+//
+// @event ticket_closed
+type TicketClosedEvent struct {
+	BaseEvent
+
+	Ticket *Ticket `json:"ticket"`
+}
+
+// NewTicketClosed returns a new ticket closed event
+func NewTicketClosed(ticket *flows.Ticket) *TicketClosedEvent {
+	return &TicketClosedEvent{
+		BaseEvent: NewBaseEvent(TypeTicketClosed),
+		Ticket:    newTicket(ticket),
+	}
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"github.com/developc3ntro/omni-goflow/flows"
+)
+
+func init() {
+	registerType(TypeTicketNoteAdded, func() flows.Event { return &TicketNoteAddedEvent{} })
+}
+
+// TypeTicketNoteAdded is the type for our ticket note added events
+const TypeTicketNoteAdded string = "ticket_note_added"
+
+// TicketNoteAddedEvent events are created when an internal note is added to a ticket.
+//
+//	{
+//	  "type": "ticket_note_added",
+//	  "created_on": "2006-01-02T15:04:05Z",
+//	  "ticket": {
+//	    "uuid": "2e677ae6-9b57-423c-b022-7950503eef35",
+//	    "ticketer": {
+//	      "uuid": "d605bb96-258d-4097-ad0a-080937db2212",
+//	      "name": "Support Tickets"
+//	    },
+//	    "topic": {
+//	      "uuid": "add17edf-0b6e-4311-bcd7-a64b2a459157",
+//	      "name": "Weather"
+//	    },
+//	    "body": "Where are my cookies?",
+//	    "external_id": "32526523",
+//	    "assignee": {"email": "bob@nyaruka.com", "name": "Bob"},
+//	    "status": "open"
+//	  },
+//	  "note": "Customer was very polite"
+//	}
+//
+// @event ticket_note_added
+type TicketNoteAddedEvent struct {
+	BaseEvent
+
+	Ticket *Ticket `json:"ticket"`
+	Note   string  `json:"note"`
+}
+
+// NewTicketNoteAdded returns a new ticket note added event
+func NewTicketNoteAdded(ticket *flows.Ticket, note string) *TicketNoteAddedEvent {
+	return &TicketNoteAddedEvent{
+		BaseEvent: NewBaseEvent(TypeTicketNoteAdded),
+		Ticket:    newTicket(ticket),
+		Note:      note,
+	}
+}
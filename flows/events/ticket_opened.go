@@ -12,6 +12,7 @@ func init() {
 // TypeTicketOpened is the type for our ticket opened events
 const TypeTicketOpened string = "ticket_opened"
 
+// Ticket is a ticket as recorded in ticket events
 type Ticket struct {
 	UUID       flows.TicketUUID          `json:"uuid"                   validate:"required,uuid4"`
 	Ticketer   *assets.TicketerReference `json:"ticketer"               validate:"required,dive"`
@@ -19,6 +20,7 @@ type Ticket struct {
 	Body       string                    `json:"body"`
 	ExternalID string                    `json:"external_id,omitempty"`
 	Assignee   *assets.UserReference     `json:"assignee,omitempty"     validate:"omitempty,dive"`
+	Status     flows.TicketStatus        `json:"status,omitempty"       validate:"omitempty,ticket_status"`
 }
 
 // TicketOpenedEvent events are created when a new ticket is opened.
@@ -38,7 +40,8 @@ type Ticket struct {
 //	    },
 //	    "body": "Where are my cookies?",
 //	    "external_id": "32526523",
-//	    "assignee": {"email": "bob@nyaruka.com", "name": "Bob"}
+//	    "assignee": {"email": "bob@nyaruka.com", "name": "Bob"},
+//	    "status": "open"
 //	  }
 //	}
 //
@@ -53,13 +56,18 @@ type TicketOpenedEvent struct {
 func NewTicketOpened(ticket *flows.Ticket) *TicketOpenedEvent {
 	return &TicketOpenedEvent{
 		BaseEvent: NewBaseEvent(TypeTicketOpened),
-		Ticket: &Ticket{
-			UUID:       ticket.UUID(),
-			Ticketer:   ticket.Ticketer().Reference(),
-			Topic:      ticket.Topic().Reference(),
-			Body:       ticket.Body(),
-			ExternalID: ticket.ExternalID(),
-			Assignee:   ticket.Assignee().Reference(),
-		},
+		Ticket:    newTicket(ticket),
+	}
+}
+
+func newTicket(ticket *flows.Ticket) *Ticket {
+	return &Ticket{
+		UUID:       ticket.UUID(),
+		Ticketer:   ticket.Ticketer().Reference(),
+		Topic:      ticket.Topic().Reference(),
+		Body:       ticket.Body(),
+		ExternalID: ticket.ExternalID(),
+		Assignee:   ticket.Assignee().Reference(),
+		Status:     ticket.Status(),
 	}
 }
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"github.com/developc3ntro/omni-goflow/flows"
+)
+
+func init() {
+	registerType(TypeTicketReopened, func() flows.Event { return &TicketReopenedEvent{} })
+}
+
+// TypeTicketReopened is the type for our ticket reopened events
+const TypeTicketReopened string = "ticket_reopened"
+
+// TicketReopenedEvent events are created when a closed ticket is reopened.
+//
+//	{
+//	  "type": "ticket_reopened",
+//	  "created_on": "2006-01-02T15:04:05Z",
+//	  "ticket": {
+//	    "uuid": "2e677ae6-9b57-423c-b022-7950503eef35",
+//	    "ticketer": {
+//	      "uuid": "d605bb96-258d-4097-ad0a-080937db2212",
+//	      "name": "Support Tickets"
+//	    },
+//	    "topic": {
+//	      "uuid": "add17edf-0b6e-4311-bcd7-a64b2a459157",
+//	      "name": "Weather"
+//	    },
+//	    "body": "Where are my cookies?",
+//	    "external_id": "32526523",
+//	    "assignee": {"email": "bob@nyaruka.com", "name": "Bob"},
+//	    "status": "open"
+//	  }
+//	}
+//
+// @event ticket_reopened
+type TicketReopenedEvent struct {
+	BaseEvent
+
+	Ticket *Ticket `json:"ticket"`
+}
+
+// NewTicketReopened returns a new ticket reopened event
+func NewTicketReopened(ticket *flows.Ticket) *TicketReopenedEvent {
+	return &TicketReopenedEvent{
+		BaseEvent: NewBaseEvent(TypeTicketReopened),
+		Ticket:    newTicket(ticket),
+	}
+}
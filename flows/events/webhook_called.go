@@ -22,7 +22,8 @@ const (
 
 // WebhookCalledEvent events are created when a webhook is called. The event contains
 // the URL and the status of the response, as well as a full dump of the
-// request and response.
+// request and response. If the response was served from a cache, `cached` will be true, and if
+// the request was skipped because the host is failing, `circuit_open` will be true.
 //
 //	{
 //	  "type": "webhook_called",
@@ -43,8 +44,10 @@ type WebhookCalledEvent struct {
 
 	*flows.HTTPTrace
 
-	Resthook   string     `json:"resthook,omitempty"`
-	Extraction Extraction `json:"extraction"`
+	Resthook    string     `json:"resthook,omitempty"`
+	Extraction  Extraction `json:"extraction"`
+	Cached      bool       `json:"cached,omitempty"`
+	CircuitOpen bool       `json:"circuit_open,omitempty"`
 }
 
 // NewWebhookCalled returns a new webhook called event
@@ -63,9 +66,11 @@ func NewWebhookCalled(call *flows.WebhookCall, status flows.CallStatus, resthook
 	}
 
 	return &WebhookCalledEvent{
-		BaseEvent:  NewBaseEvent(TypeWebhookCalled),
-		HTTPTrace:  flows.NewHTTPTrace(call.Trace, status),
-		Resthook:   resthook,
-		Extraction: extraction,
+		BaseEvent:   NewBaseEvent(TypeWebhookCalled),
+		HTTPTrace:   flows.NewRedactedHTTPTrace(call.Trace, status, call.Redactor),
+		Resthook:    resthook,
+		Extraction:  extraction,
+		Cached:      call.Cached,
+		CircuitOpen: call.CircuitOpen,
 	}
 }
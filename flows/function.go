@@ -0,0 +1,67 @@
+package flows
+
+import (
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/excellent"
+)
+
+// Function represents a function defined as an asset which can be called from expressions.
+type Function struct {
+	assets.Function
+}
+
+// NewFunction returns a new function object from the given function asset
+func NewFunction(asset assets.Function) *Function {
+	return &Function{Function: asset}
+}
+
+// Asset returns the underlying asset
+func (f *Function) Asset() assets.Function { return f.Function }
+
+// Reference returns a reference to this function
+func (f *Function) Reference() *assets.FunctionReference {
+	return assets.NewFunctionReference(f.Name())
+}
+
+// FunctionAssets provides access to all function assets
+type FunctionAssets struct {
+	all       []*Function
+	byName    map[string]*Function
+	functions *excellent.Functions
+}
+
+// NewFunctionAssets creates a new set of function assets
+func NewFunctionAssets(functions []assets.Function) *FunctionAssets {
+	s := &FunctionAssets{
+		all:    make([]*Function, len(functions)),
+		byName: make(map[string]*Function, len(functions)),
+	}
+	defs := make([]excellent.FunctionDefinition, len(functions))
+
+	for i, asset := range functions {
+		function := NewFunction(asset)
+		s.all[i] = function
+		s.byName[strings.ToLower(function.Name())] = function
+		defs[i] = asset
+	}
+
+	s.functions = excellent.NewFunctions(defs)
+	return s
+}
+
+// Get returns the function with the given name (case-insensitive)
+func (s *FunctionAssets) Get(name string) *Function {
+	return s.byName[strings.ToLower(name)]
+}
+
+// All returns all the functions in this set
+func (s *FunctionAssets) All() []*Function {
+	return s.all
+}
+
+// Scope returns the scope in which these functions can be called from expressions
+func (s *FunctionAssets) Scope(maxDepth, maxCalls int) *excellent.Scope {
+	return s.functions.Scope(maxDepth, maxCalls)
+}
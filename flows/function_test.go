@@ -0,0 +1,37 @@
+package flows_test
+
+import (
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/assets/static"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/excellent/types"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctions(t *testing.T) {
+	fa1 := static.NewFunction("format_id", []string{"id"}, `upper(text_slice(id, 0, 3)) & "-" & text_slice(id, 3)`)
+	fa2 := static.NewFunction("Format_Phone", []string{"phone"}, `"+1 " & phone`)
+
+	fa := flows.NewFunctionAssets([]assets.Function{fa1, fa2})
+
+	f1 := fa.Get("FORMAT_ID")
+
+	assert.Equal(t, "format_id", f1.Name())
+	assert.Equal(t, fa1, f1.Asset())
+	assert.Equal(t, assets.NewFunctionReference("format_id"), f1.Reference())
+	assert.Equal(t, fa.Get("format_phone").Asset(), fa2)
+	assert.Nil(t, fa.Get("xxx"))
+	assert.Len(t, fa.All(), 2)
+
+	env := envs.NewBuilder().Build()
+	ctx := types.NewXObject(map[string]types.XValue{"id": types.NewXText("abc123")})
+
+	// check use in expressions
+	test.AssertXEqual(t, types.NewXText("ABC-123"), excellent.NewCache(0).EvaluateExpression(env, ctx, fa.Scope(10, 100), `format_id(id)`))
+}
@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// fill colors of nodes by heat, from never visited to most visited
+var dotColors = []string{"#f4cccc", "#d9ead3", "#b6d7a8", "#93c47d"}
+
+// WriteDOT writes the given graph in the Graphviz DOT language
+func WriteDOT(w io.Writer, g *Graph) error {
+	lines := []string{
+		fmt.Sprintf("digraph %s {", dotQuote(g.Name)),
+		`  node [shape=box, fontname="Helvetica", fontsize=10];`,
+		`  edge [fontname="Helvetica", fontsize=9];`,
+	}
+
+	if len(g.Nodes) > 0 {
+		lines = append(lines, "  start [shape=point];")
+		lines = append(lines, fmt.Sprintf("  start -> %s;", g.Nodes[0].ID))
+	}
+
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(nodeLabel(n, "\n"))}
+		if n.Router {
+			attrs = append(attrs, `style="rounded,filled"`)
+		} else {
+			attrs = append(attrs, `style="filled"`)
+		}
+		if n.Visits != nil {
+			attrs = append(attrs, "fillcolor="+dotQuote(dotColors[g.Heat(n)]))
+		} else {
+			attrs = append(attrs, `fillcolor="white"`)
+		}
+
+		lines = append(lines, fmt.Sprintf("  %s [%s];", n.ID, strings.Join(attrs, ", ")))
+	}
+
+	for _, e := range g.Edges {
+		if e.Label != "" {
+			lines = append(lines, fmt.Sprintf("  %s -> %s [label=%s];", e.From, e.To, dotQuote(e.Label)))
+		} else {
+			lines = append(lines, fmt.Sprintf("  %s -> %s;", e.From, e.To))
+		}
+	}
+
+	lines = append(lines, "}")
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// gets the label of a node as its action and router descriptions, and its visits if known
+func nodeLabel(n *Node, newline string) string {
+	label := strings.Join(n.Lines, newline)
+	if n.Visits != nil {
+		label += newline + fmt.Sprintf("(%d visits)", *n.Visits)
+	}
+	return label
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
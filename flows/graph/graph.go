@@ -0,0 +1,210 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/actions"
+	"github.com/developc3ntro/omni-goflow/flows/routers"
+	"github.com/developc3ntro/omni-goflow/utils"
+)
+
+// the maximum length of text included in action summaries
+const maxSummaryText = 40
+
+// Node is a node in a flow graph
+type Node struct {
+	ID     string
+	UUID   flows.NodeUUID
+	Lines  []string
+	Router bool
+	Visits *int
+}
+
+// Edge is a connection between two nodes in a flow graph, labeled with the categories which lead to it
+type Edge struct {
+	From  string
+	To    string
+	Label string
+}
+
+// Graph is a simplified representation of a flow as nodes and edges
+type Graph struct {
+	Name      string
+	Nodes     []*Node
+	Edges     []*Edge
+	MaxVisits int
+}
+
+// New creates a new graph of the given flow. If visits isn't nil then nodes include their number of visits.
+func New(flow flows.Flow, visits map[flows.NodeUUID]int) *Graph {
+	g := &Graph{Name: flow.Name(), Nodes: make([]*Node, len(flow.Nodes())), Edges: make([]*Edge, 0)}
+	ids := make(map[flows.NodeUUID]string, len(flow.Nodes()))
+
+	for i, node := range flow.Nodes() {
+		ids[node.UUID()] = fmt.Sprintf("n%d", i)
+	}
+
+	for i, node := range flow.Nodes() {
+		lines := make([]string, 0, len(node.Actions())+1)
+		for _, action := range node.Actions() {
+			lines = append(lines, describeAction(action))
+		}
+		if node.Router() != nil {
+			lines = append(lines, describeRouter(node.Router()))
+		}
+
+		n := &Node{ID: ids[node.UUID()], UUID: node.UUID(), Lines: lines, Router: node.Router() != nil}
+		if visits != nil {
+			count := visits[node.UUID()]
+			n.Visits = &count
+			if count > g.MaxVisits {
+				g.MaxVisits = count
+			}
+		}
+		g.Nodes[i] = n
+
+		for _, exit := range node.Exits() {
+			if exit.DestinationUUID() == "" || ids[exit.DestinationUUID()] == "" {
+				continue
+			}
+			g.Edges = append(g.Edges, &Edge{From: n.ID, To: ids[exit.DestinationUUID()], Label: describeExit(node, exit)})
+		}
+	}
+
+	return g
+}
+
+// HasVisits returns whether the nodes in this graph include their number of visits
+func (g *Graph) HasVisits() bool {
+	return len(g.Nodes) > 0 && g.Nodes[0].Visits != nil
+}
+
+// Heat returns how visited the given node is, from 0 (never visited) to 3 (among the most visited)
+func (g *Graph) Heat(n *Node) int {
+	if n.Visits == nil || *n.Visits == 0 || g.MaxVisits == 0 {
+		return 0
+	}
+	return 1 + (2 * *n.Visits / g.MaxVisits)
+}
+
+// describes an action by its type and its most important property
+func describeAction(action flows.Action) string {
+	var detail string
+
+	switch a := action.(type) {
+	case *actions.SendMsgAction:
+		detail = a.Text
+	case *actions.SendBroadcastAction:
+		detail = a.Text
+	case *actions.SayMsgAction:
+		detail = a.Text
+	case *actions.PlayAudioAction:
+		detail = a.AudioURL
+	case *actions.SendEmailAction:
+		detail = a.Subject
+	case *actions.SetContactFieldAction:
+		detail = fmt.Sprintf("%s = %s", a.Field.Key, a.Value)
+	case *actions.SetContactNameAction:
+		detail = a.Name
+	case *actions.SetContactLanguageAction:
+		detail = a.Language
+	case *actions.SetRunResultAction:
+		detail = fmt.Sprintf("%s = %s", a.Name, a.Value)
+	case *actions.AddContactGroupsAction:
+		detail = groupNames(a.Groups)
+	case *actions.RemoveContactGroupsAction:
+		if a.AllGroups {
+			detail = "all groups"
+		} else {
+			detail = groupNames(a.Groups)
+		}
+	case *actions.EnterFlowAction:
+		detail = a.Flow.Name
+	case *actions.StartSessionAction:
+		detail = a.Flow.Name
+	case *actions.CallWebhookAction:
+		detail = fmt.Sprintf("%s %s", a.Method, a.URL)
+	case *actions.CallResthookAction:
+		detail = a.Resthook
+	}
+
+	if detail == "" {
+		return action.Type()
+	}
+	return fmt.Sprintf("%s: %s", action.Type(), truncate(detail, maxSummaryText))
+}
+
+// describes a router by its type, operand and result name
+func describeRouter(router flows.Router) string {
+	desc := router.Type()
+	if switch_, ok := router.(*routers.SwitchRouter); ok {
+		desc = fmt.Sprintf("switch on %s", switch_.Operand())
+	}
+	if router.Wait() != nil {
+		desc = fmt.Sprintf("wait for %s, %s", router.Wait().Type(), desc)
+	}
+	if router.ResultName() != "" {
+		desc += fmt.Sprintf(" as %s", router.ResultName())
+	}
+	return desc
+}
+
+// describes an exit by the categories which lead to it and the cases which lead to them
+func describeExit(node flows.Node, exit flows.Exit) string {
+	if node.Router() == nil {
+		return ""
+	}
+
+	var cases []*routers.Case
+	if switch_, ok := node.Router().(*routers.SwitchRouter); ok {
+		cases = switch_.Cases()
+	}
+
+	labels := make([]string, 0)
+	for _, category := range node.Router().Categories() {
+		if category.ExitUUID() != exit.UUID() {
+			continue
+		}
+
+		tests := make([]string, 0)
+		for _, c := range cases {
+			if c.CategoryUUID == category.UUID() {
+				tests = append(tests, describeCase(c))
+			}
+		}
+
+		if len(tests) > 0 {
+			labels = append(labels, fmt.Sprintf("%s: %s", category.Name(), strings.Join(tests, " | ")))
+		} else {
+			labels = append(labels, category.Name())
+		}
+	}
+	return strings.Join(labels, ", ")
+}
+
+func describeCase(c *routers.Case) string {
+	if len(c.Arguments) == 0 {
+		return c.Type
+	}
+	return fmt.Sprintf("%s %s", c.Type, truncate(strings.Join(c.Arguments, " "), maxSummaryText))
+}
+
+// collapses whitespace so that text fits on a single line and truncates it
+func truncate(s string, length int) string {
+	return utils.TruncateEllipsis(strings.Join(strings.Fields(s), " "), length)
+}
+
+func groupNames(groups []*assets.GroupReference) string {
+	names := make([]string, len(groups))
+	for i, group := range groups {
+		if group.Variable() {
+			names[i] = group.NameMatch
+		} else {
+			names[i] = group.Name
+		}
+	}
+	return strings.Join(names, ", ")
+}
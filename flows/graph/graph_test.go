@@ -0,0 +1,61 @@
+package graph_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/definition"
+	"github.com/developc3ntro/omni-goflow/flows/graph"
+	"github.com/developc3ntro/omni-goflow/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph(t *testing.T) {
+	flowJSON, err := os.ReadFile("testdata/favorites.json")
+	require.NoError(t, err)
+
+	flow, err := definition.ReadFlow(flowJSON, nil)
+	require.NoError(t, err)
+
+	g := graph.New(flow, nil)
+	assert.Equal(t, "Favorites", g.Name)
+	assert.Len(t, g.Nodes, 6)
+	assert.False(t, g.HasVisits())
+	assert.Equal(t, []string{
+		"send_msg: Hi @contact.name! What is your favori...",
+		"add_contact_groups: Survey Takers",
+		"set_contact_field: last_survey = @(format_date(today()))",
+	}, g.Nodes[0].Lines)
+	assert.Equal(t, []string{"wait for msg, switch on @input as Color"}, g.Nodes[1].Lines)
+	assert.Equal(t, "Blue: has_any_word blue | has_any_word navy", g.Edges[2].Label)
+
+	dot := &strings.Builder{}
+	require.NoError(t, graph.WriteDOT(dot, g))
+	test.AssertSnapshot(t, "dot", dot.String())
+
+	mermaid := &strings.Builder{}
+	require.NoError(t, graph.WriteMermaid(mermaid, g))
+	test.AssertSnapshot(t, "mermaid", mermaid.String())
+
+	// with visit counts nodes are colored by how often they were visited
+	g = graph.New(flow, map[flows.NodeUUID]int{
+		"10f3d1ef-575f-4d56-aace-0941727ba15f": 10,
+		"e25efaa5-aa5f-46f6-922a-896c45aeb1bc": 12,
+		"d7a38fd5-f612-40b7-8786-6f4e5a9f49a1": 4,
+	})
+	assert.True(t, g.HasVisits())
+	assert.Equal(t, 12, g.MaxVisits)
+	assert.Equal(t, []int{2, 3, 1, 0}, []int{g.Heat(g.Nodes[0]), g.Heat(g.Nodes[1]), g.Heat(g.Nodes[2]), g.Heat(g.Nodes[3])})
+
+	dot = &strings.Builder{}
+	require.NoError(t, graph.WriteDOT(dot, g))
+	test.AssertSnapshot(t, "dot_visits", dot.String())
+
+	mermaid = &strings.Builder{}
+	require.NoError(t, graph.WriteMermaid(mermaid, g))
+	test.AssertSnapshot(t, "mermaid_visits", mermaid.String())
+}
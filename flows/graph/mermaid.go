@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// classes of nodes by heat, from never visited to most visited
+var mermaidClasses = []string{
+	"classDef heat0 fill:#f4cccc,stroke:#999",
+	"classDef heat1 fill:#d9ead3,stroke:#999",
+	"classDef heat2 fill:#b6d7a8,stroke:#999",
+	"classDef heat3 fill:#93c47d,stroke:#999",
+}
+
+// WriteMermaid writes the given graph as a Mermaid flowchart
+func WriteMermaid(w io.Writer, g *Graph) error {
+	lines := []string{"flowchart TD"}
+
+	if len(g.Nodes) > 0 {
+		lines = append(lines, "  start(( ))")
+		lines = append(lines, fmt.Sprintf("  start --> %s", g.Nodes[0].ID))
+	}
+
+	for _, n := range g.Nodes {
+		label := mermaidQuote(nodeLabel(n, "\n"))
+		if n.Router {
+			lines = append(lines, fmt.Sprintf("  %s([%s])", n.ID, label))
+		} else {
+			lines = append(lines, fmt.Sprintf("  %s[%s]", n.ID, label))
+		}
+	}
+
+	for _, e := range g.Edges {
+		if e.Label != "" {
+			lines = append(lines, fmt.Sprintf("  %s -->|%s| %s", e.From, mermaidQuote(e.Label), e.To))
+		} else {
+			lines = append(lines, fmt.Sprintf("  %s --> %s", e.From, e.To))
+		}
+	}
+
+	// if we have visits, color each node by its heat
+	if g.HasVisits() {
+		for _, c := range mermaidClasses {
+			lines = append(lines, "  "+c)
+		}
+		for _, n := range g.Nodes {
+			lines = append(lines, fmt.Sprintf("  class %s heat%d", n.ID, g.Heat(n)))
+		}
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "\n", "<br/>", "|", "#124;", "<", "#lt;", ">", "#gt;")
+
+func mermaidQuote(s string) string {
+	return `"` + mermaidEscaper.Replace(s) + `"`
+}
@@ -0,0 +1,191 @@
+package inspect
+
+import (
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/excellent/functions"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/nyaruka/gocommon/urns"
+)
+
+var (
+	textType     = excellent.NewType(functions.TypeText)
+	numberType   = excellent.NewType(functions.TypeNumber)
+	datetimeType = excellent.NewType(functions.TypeDateTime)
+	textsType    = excellent.NewArrayType(textType)
+)
+
+// the shapes of the objects in the context which don't depend on assets or the flow
+var (
+	channelType = object(map[string]*excellent.Type{"uuid": textType, "name": textType, "address": textType})
+	groupType   = object(map[string]*excellent.Type{"uuid": textType, "name": textType})
+	topicType   = object(map[string]*excellent.Type{"uuid": textType, "name": textType})
+	userType    = object(map[string]*excellent.Type{"email": textType, "name": textType, "first_name": textType})
+	flowType    = object(map[string]*excellent.Type{"uuid": textType, "name": textType, "revision": numberType})
+
+	ticketType = object(map[string]*excellent.Type{
+		"uuid":     textType,
+		"topic":    topicType,
+		"body":     textType,
+		"assignee": userType,
+		"status":   textType,
+	})
+
+	resultType = object(map[string]*excellent.Type{
+		"name":                 textType,
+		"value":                textType,
+		"values":               textsType,
+		"category":             textType,
+		"categories":           textsType,
+		"category_localized":   textType,
+		"categories_localized": textsType,
+		"input":                textType,
+		"extra":                excellent.AnyType,
+		"node_uuid":            textType,
+		"created_on":           datetimeType,
+	})
+
+	stepType = object(map[string]*excellent.Type{
+		"uuid":       textType,
+		"node_uuid":  textType,
+		"arrived_on": datetimeType,
+		"exit_uuid":  textType,
+	})
+
+	inputType = object(map[string]*excellent.Type{
+		"type":        textType,
+		"uuid":        textType,
+		"created_on":  datetimeType,
+		"channel":     channelType,
+		"urn":         textType,
+		"text":        textType,
+		"attachments": textsType,
+		"external_id": textType,
+	})
+
+	nodeType = object(map[string]*excellent.Type{"uuid": textType, "visit_count": numberType})
+)
+
+// ContextType returns the static type of the root context of runs of the given flow, for type checking expressions.
+// Fields are typed if assets are provided and results are typed by the results the flow can set, but references to
+// fields, globals and results which don't exist are left to be reported as missing dependencies or unset results.
+func ContextType(sa flows.SessionAssets, flow flows.Flow) *excellent.Type {
+	urnsType := urnsType()
+	fieldsType := fieldsType(sa)
+
+	results := make(map[string]*excellent.Type)
+	for _, node := range flow.Nodes() {
+		node.EnumerateResults(func(a flows.Action, r flows.Router, i *flows.ResultInfo) {
+			results[strings.ToLower(i.Key)] = resultType
+		})
+	}
+	resultsType := excellent.NewObjectType(results, resultType)
+
+	contactType := object(map[string]*excellent.Type{
+		"uuid":         textType,
+		"id":           textType,
+		"name":         textType,
+		"first_name":   textType,
+		"language":     textType,
+		"timezone":     textType,
+		"created_on":   datetimeType,
+		"last_seen_on": datetimeType,
+		"urns":         textsType,
+		"urn":          textType,
+		"groups":       excellent.NewArrayType(groupType),
+		"fields":       fieldsType,
+		"channel":      channelType,
+		"tickets":      excellent.NewArrayType(ticketType),
+	})
+
+	runType := object(map[string]*excellent.Type{
+		"uuid":       textType,
+		"contact":    contactType,
+		"flow":       flowType,
+		"status":     textType,
+		"results":    resultsType,
+		"path":       excellent.NewArrayType(stepType),
+		"created_on": datetimeType,
+		"exited_on":  datetimeType,
+	})
+
+	// related runs are of other flows so we don't know what results they have or what fields they set
+	relatedRunType := object(map[string]*excellent.Type{
+		"uuid":    textType,
+		"contact": contactType,
+		"flow":    flowType,
+		"urns":    urnsType,
+		"fields":  fieldsType,
+		"results": excellent.NewObjectType(nil, resultType),
+		"status":  textType,
+		"run":     object(map[string]*excellent.Type{"status": textType}),
+	})
+
+	root := object(map[string]*excellent.Type{
+		"run":          runType,
+		"child":        relatedRunType,
+		"parent":       relatedRunType,
+		"contact":      contactType,
+		"results":      resultsType,
+		"urns":         urnsType,
+		"fields":       fieldsType,
+		"ticket":       ticketType,
+		"trigger":      excellent.AnyType,
+		"resume":       excellent.AnyType,
+		"input":        inputType,
+		"globals":      excellent.NewObjectType(nil, textType),
+		"webhook":      excellent.AnyType,
+		"node":         nodeType,
+		"legacy_extra": excellent.AnyType,
+	})
+
+	// functions defined as assets are resolved after the context and builtin functions
+	if sa != nil {
+		for _, f := range sa.Functions().All() {
+			name := strings.ToLower(f.Name())
+			if _, exists := root.Properties[name]; !exists && functions.Lookup(name) == nil {
+				params := make([]string, len(f.Parameters()))
+				for i := range params {
+					params[i] = functions.TypeAny
+				}
+				root.Properties[name] = excellent.NewFunctionType(functions.FixedSignature(functions.TypeAny, params...))
+			}
+		}
+	}
+
+	return root
+}
+
+// creates an object type which can only have the given properties
+func object(properties map[string]*excellent.Type) *excellent.Type {
+	return excellent.NewObjectType(properties, nil)
+}
+
+func urnsType() *excellent.Type {
+	properties := make(map[string]*excellent.Type, len(urns.ValidSchemes))
+	for scheme := range urns.ValidSchemes {
+		properties[scheme] = textType
+	}
+	return object(properties)
+}
+
+func fieldsType(sa flows.SessionAssets) *excellent.Type {
+	properties := make(map[string]*excellent.Type)
+
+	if sa != nil {
+		for _, f := range sa.Fields().All() {
+			switch f.Type() {
+			case assets.FieldTypeNumber:
+				properties[f.Key()] = numberType
+			case assets.FieldTypeDatetime:
+				properties[f.Key()] = datetimeType
+			default:
+				properties[f.Key()] = textType
+			}
+		}
+	}
+
+	return excellent.NewObjectType(properties, excellent.AnyType)
+}
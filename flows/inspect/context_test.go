@@ -0,0 +1,62 @@
+package inspect_test
+
+import (
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/actions"
+	"github.com/developc3ntro/omni-goflow/flows/definition"
+	"github.com/developc3ntro/omni-goflow/flows/inspect"
+	"github.com/developc3ntro/omni-goflow/test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextType(t *testing.T) {
+	sa, err := test.LoadSessionAssets(envs.NewBuilder().Build(), "../../test/testdata/runner/subflow.json")
+	require.NoError(t, err)
+
+	flow, err := definition.NewFlow(
+		assets.FlowUUID("502c3ee4-3249-4dee-8e71-c62070667d52"),
+		"Test",
+		envs.Language("eng"),
+		flows.FlowTypeMessaging,
+		123,
+		30,
+		definition.NewLocalization(),
+		[]flows.Node{
+			definition.NewNode(flows.NodeUUID("866b06e2-ff54-443e-9d79-2f60074514b5"), []flows.Action{
+				actions.NewSetRunResult(flows.ActionUUID("94790ebc-4f24-4664-a15d-ac758781c720"), "Age", "32", "HasAge"),
+			}, nil, []flows.Exit{}),
+		},
+		nil,
+		nil,
+	)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		expression string
+		problems   []string
+	}{
+		{`contact.name & results.age.category & run.results.age.value & child.results.foo.value`, nil},
+		{`fields.anything & globals.anything & trigger.params.foo & webhook.json.bar`, nil},
+		{`urns.tel & contact.groups[0].name & input.channel.address & node.visit_count`, nil},
+		{`contact.nmae`, []string{`contact has no property 'nmae'`}},
+		{`urns.foo`, []string{`urns has no property 'foo'`}},
+		{`results.age.vlaue`, []string{`results.age has no property 'vlaue'`}},
+		{`abs(results.age.values)`, []string{`argument 1 of abs should be number, got array`}},
+	}
+
+	root := inspect.ContextType(sa, flow)
+
+	for _, tc := range tcs {
+		var problems []string
+		excellent.TypeCheck(tc.expression, root, func(p *excellent.Problem) { problems = append(problems, p.Description) })
+
+		assert.Equal(t, tc.problems, problems, "problems mismatch for expression %s", tc.expression)
+	}
+}
@@ -77,6 +77,8 @@ func CheckReference(sa flows.SessionAssets, ref assets.Reference) bool {
 	case *assets.FlowReference:
 		_, err := sa.Flows().Get(typed.UUID)
 		return err == nil
+	case *assets.FunctionReference:
+		return sa.Functions().Get(typed.Name) != nil
 	case *assets.GlobalReference:
 		return sa.Globals().Get(typed.Key) != nil
 	case *assets.GroupReference:
@@ -40,6 +40,8 @@ func TestDependencies(t *testing.T) {
 		flows.NewExtractedReference(node1, action1, nil, envs.NilLanguage, flows.NewContactReference("0b099519-0889-4c74-b744-9122272f346a", "Bob")),
 		flows.NewExtractedReference(node1, action1, nil, envs.NilLanguage, assets.NewFieldReference("gender", "Gender")),
 		flows.NewExtractedReference(node1, action1, nil, envs.NilLanguage, assets.NewFlowReference("4f932672-7995-47f0-96e6-faf5abd2d81d", "Registration")),
+		flows.NewExtractedReference(node1, action1, nil, envs.NilLanguage, assets.NewFunctionReference("format_phone")),
+		flows.NewExtractedReference(node1, action1, nil, envs.NilLanguage, assets.NewFunctionReference("format_id")),
 		flows.NewExtractedReference(node1, action1, nil, envs.NilLanguage, assets.NewGlobalReference("org_name", "Org Name")),
 		flows.NewExtractedReference(node1, action1, nil, envs.NilLanguage, assets.NewGroupReference("46057a92-6580-4e93-af36-2bb9c9d61e51", "Testers")),
 		flows.NewExtractedReference(node1, action1, nil, envs.NilLanguage, assets.NewGroupReference("377c3101-a7fc-47b1-9136-980348e362c0", "Customers")),
@@ -51,8 +53,15 @@ func TestDependencies(t *testing.T) {
 		flows.NewExtractedReference(node2, nil, router2, envs.NilLanguage, assets.NewGlobalReference("org_name", "Org Name")),
 	}
 
-	// if our assets only includes a single group, the other assets should be reported as missing
+	// if our assets only includes a single function and group, the other assets should be reported as missing
 	source, err := static.NewSource([]byte(`{
+			"functions": [
+				{
+					"name": "FORMAT_ID",
+					"parameters": ["id"],
+					"body": "upper(id)"
+				}
+			],
 			"groups": [
 				{
 					"uuid": "377c3101-a7fc-47b1-9136-980348e362c0",
@@ -97,6 +106,15 @@ func TestDependencies(t *testing.T) {
 			"type": "flow",
 			"uuid": "4f932672-7995-47f0-96e6-faf5abd2d81d"
 		},
+		{
+			"missing": true,
+			"name": "format_phone",
+			"type": "function"
+		},
+		{
+			"name": "format_id",
+			"type": "function"
+		},
 		{
 			"key": "org_name",
 			"missing": true,
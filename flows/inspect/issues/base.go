@@ -7,7 +7,7 @@ import (
 	"github.com/developc3ntro/omni-goflow/flows"
 )
 
-type reportFunc func(flows.SessionAssets, flows.Flow, []flows.ExtractedTemplate, []flows.ExtractedReference, func(flows.Issue))
+type reportFunc func(flows.SessionAssets, flows.Flow, []flows.ExtractedTemplate, []flows.ExtractedReference, []envs.Language, func(flows.Issue))
 
 var RegisteredTypes = map[string]reportFunc{}
 
@@ -51,15 +51,30 @@ func (p *baseIssue) Language() envs.Language { return p.Language_ }
 // Description returns the description of the issue
 func (p *baseIssue) Description() string { return p.Description_ }
 
-// Check returns all issues in the given flow
-func Check(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference) []flows.Issue {
+// returns the languages of the given localization in a consistent order
+func sortedLanguages(localization flows.Localization) []envs.Language {
+	languages := localization.Languages()
+	sort.Slice(languages, func(i, j int) bool { return languages[i] < languages[j] })
+	return languages
+}
+
+// Check returns all issues in the given flow. Languages are those which the flow should be translated into and may
+// be empty.
+func Check(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language) []flows.Issue {
 	issues := make([]flows.Issue, 0)
 	report := func(i flows.Issue) {
 		issues = append(issues, i)
 	}
 
-	for _, fn := range RegisteredTypes {
-		fn(sa, flow, tpls, refs, report)
+	// run checks in a consistent order so that issues on the same node are always reported in the same order
+	typeNames := make([]string, 0, len(RegisteredTypes))
+	for typeName := range RegisteredTypes {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		RegisteredTypes[typeName](sa, flow, tpls, refs, languages, report)
 	}
 
 	// sort issues by node order
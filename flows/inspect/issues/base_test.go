@@ -35,6 +35,7 @@ func testIssueType(t *testing.T, sa flows.SessionAssets, typeName string) {
 	tests := []struct {
 		Description string          `json:"description"`
 		NoAssets    bool            `json:"no_assets,omitempty"`
+		Languages   []envs.Language `json:"languages,omitempty"`
 		Flow        json.RawMessage `json:"flow"`
 
 		Issues json.RawMessage `json:"issues"`
@@ -55,7 +56,7 @@ func testIssueType(t *testing.T, sa flows.SessionAssets, typeName string) {
 			sessionAssets = sa
 		}
 
-		info := flow.Inspect(sessionAssets)
+		info := flow.Inspect(sessionAssets, tc.Languages...)
 		issuesJSON := jsonx.MustMarshal(info.Issues)
 
 		// clone test case and populate with actual values
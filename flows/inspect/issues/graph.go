@@ -0,0 +1,70 @@
+package issues
+
+import (
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/actions"
+)
+
+// returns the UUIDs of the nodes that the given node can exit to
+func destinations(node flows.Node) []flows.NodeUUID {
+	dests := make([]flows.NodeUUID, 0, len(node.Exits()))
+	for _, exit := range node.Exits() {
+		if exit.DestinationUUID() != "" {
+			dests = append(dests, exit.DestinationUUID())
+		}
+	}
+	return dests
+}
+
+// returns the set of nodes which can be reached by following exits from the given node, not including that node
+// itself unless it is part of a loop
+func reachableFrom(flow flows.Flow, start flows.Node) map[flows.NodeUUID]bool {
+	reached := make(map[flows.NodeUUID]bool)
+	queue := destinations(start)
+
+	for len(queue) > 0 {
+		uuid := queue[0]
+		queue = queue[1:]
+
+		if reached[uuid] {
+			continue
+		}
+		reached[uuid] = true
+
+		if node := flow.GetNode(uuid); node != nil {
+			queue = append(queue, destinations(node)...)
+		}
+	}
+	return reached
+}
+
+// returns a map of each node to the nodes which can reach it by following exits
+func ancestors(flow flows.Flow) map[flows.NodeUUID]map[flows.NodeUUID]bool {
+	ancestors := make(map[flows.NodeUUID]map[flows.NodeUUID]bool, len(flow.Nodes()))
+	for _, node := range flow.Nodes() {
+		ancestors[node.UUID()] = make(map[flows.NodeUUID]bool)
+	}
+
+	for _, node := range flow.Nodes() {
+		for uuid := range reachableFrom(flow, node) {
+			if ancestors[uuid] != nil {
+				ancestors[uuid][node.UUID()] = true
+			}
+		}
+	}
+	return ancestors
+}
+
+// checks whether execution can pause at the given node, either by waiting for input or by entering a subflow
+// which might wait for input
+func canPause(node flows.Node) bool {
+	if node.Router() != nil && node.Router().Wait() != nil {
+		return true
+	}
+	for _, action := range node.Actions() {
+		if action.Type() == actions.TypeEnterFlow {
+			return true
+		}
+	}
+	return false
+}
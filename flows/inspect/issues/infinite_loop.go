@@ -0,0 +1,84 @@
+package issues
+
+import (
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+)
+
+func init() {
+	registerType(TypeInfiniteLoop, InfiniteLoopCheck)
+}
+
+// TypeInfiniteLoop is our type for a loop which never waits
+const TypeInfiniteLoop string = "infinite_loop"
+
+// InfiniteLoop is a set of nodes which can loop back on themselves without ever waiting for input, and which
+// will therefore hit the engine's step limit if a contact keeps going around the loop
+type InfiniteLoop struct {
+	baseIssue
+
+	NodeUUIDs []flows.NodeUUID `json:"node_uuids"`
+}
+
+func newInfiniteLoop(nodeUUIDs []flows.NodeUUID) *InfiniteLoop {
+	return &InfiniteLoop{
+		baseIssue: newBaseIssue(
+			TypeInfiniteLoop,
+			nodeUUIDs[0],
+			"",
+			"",
+			"nodes can loop without waiting for input",
+		),
+		NodeUUIDs: nodeUUIDs,
+	}
+}
+
+// InfiniteLoopCheck checks for loops with no waits
+func InfiniteLoopCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language, report func(flows.Issue)) {
+	// only consider nodes where execution can't pause, as any loop through a wait is fine
+	reachable := make(map[flows.NodeUUID]map[flows.NodeUUID]bool)
+	for _, node := range flow.Nodes() {
+		if !canPause(node) {
+			reachable[node.UUID()] = reachableWithoutPausing(flow, node)
+		}
+	}
+
+	// group nodes which can reach each other into loops
+	seen := make(map[flows.NodeUUID]bool)
+
+	for _, node := range flow.Nodes() {
+		if seen[node.UUID()] || !reachable[node.UUID()][node.UUID()] {
+			continue
+		}
+
+		loop := make([]flows.NodeUUID, 0)
+		for _, other := range flow.Nodes() {
+			if reachable[node.UUID()][other.UUID()] && reachable[other.UUID()][node.UUID()] {
+				loop = append(loop, other.UUID())
+				seen[other.UUID()] = true
+			}
+		}
+
+		report(newInfiniteLoop(loop))
+	}
+}
+
+// returns the set of nodes that can be reached from the given node without passing through a node that can pause
+func reachableWithoutPausing(flow flows.Flow, start flows.Node) map[flows.NodeUUID]bool {
+	reached := make(map[flows.NodeUUID]bool)
+	queue := destinations(start)
+
+	for len(queue) > 0 {
+		uuid := queue[0]
+		queue = queue[1:]
+
+		node := flow.GetNode(uuid)
+		if reached[uuid] || node == nil || canPause(node) {
+			continue
+		}
+		reached[uuid] = true
+
+		queue = append(queue, destinations(node)...)
+	}
+	return reached
+}
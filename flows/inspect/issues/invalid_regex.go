@@ -39,7 +39,7 @@ func newInvalidRegex(nodeUUID flows.NodeUUID, actionUUID flows.ActionUUID, langu
 }
 
 // InvalidRegexCheck checks for invalid regexes
-func InvalidRegexCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, report func(flows.Issue)) {
+func InvalidRegexCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language, report func(flows.Issue)) {
 	checkTemplate := func(n flows.Node, a flows.Action, l envs.Language, t string) {
 		// only check if template doesn't contain expressions
 		if !excellent.HasExpressions(t, flows.RunContextTopLevels) {
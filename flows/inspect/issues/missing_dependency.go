@@ -37,7 +37,7 @@ func newMissingDependency(nodeUUID flows.NodeUUID, actionUUID flows.ActionUUID,
 }
 
 // MissingDependencyCheck checks for missing dependencies
-func MissingDependencyCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, report func(flows.Issue)) {
+func MissingDependencyCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language, report func(flows.Issue)) {
 	// skip check if we don't have assets
 	if sa == nil {
 		return
@@ -0,0 +1,85 @@
+package issues
+
+import (
+	"fmt"
+
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/inspect"
+	"github.com/nyaruka/gocommon/uuids"
+)
+
+func init() {
+	registerType(TypeMissingTranslation, MissingTranslationCheck)
+}
+
+// TypeMissingTranslation is our type for a missing translation
+const TypeMissingTranslation string = "missing_translation"
+
+// MissingTranslation is localizable text in an action which hasn't been translated into one of the flow's languages
+type MissingTranslation struct {
+	baseIssue
+
+	Property string `json:"property"`
+}
+
+func newMissingTranslation(nodeUUID flows.NodeUUID, actionUUID flows.ActionUUID, language envs.Language, property string) *MissingTranslation {
+	return &MissingTranslation{
+		baseIssue: newBaseIssue(
+			TypeMissingTranslation,
+			nodeUUID,
+			actionUUID,
+			language,
+			fmt.Sprintf("missing %s translation for %s", language, property),
+		),
+		Property: property,
+	}
+}
+
+// MissingTranslationCheck checks for action text which is missing a translation in one of the given languages, which
+// will usually be the allowed languages of the environment. If no languages are given, then we check against the
+// languages that the flow has been translated into. Router text like category names is ignored as it's not seen by
+// contacts.
+func MissingTranslationCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language, report func(flows.Issue)) {
+	localization := flow.Localization()
+
+	if len(languages) == 0 && localization != nil {
+		languages = sortedLanguages(localization)
+	}
+
+	translated := make([]envs.Language, 0, len(languages))
+	for _, lang := range languages {
+		if lang != flow.Language() {
+			translated = append(translated, lang)
+		}
+	}
+	if len(translated) == 0 {
+		return
+	}
+
+	for _, node := range flow.Nodes() {
+		for _, action := range node.Actions() {
+			inspect.LocalizableText(action, func(uuid uuids.UUID, property string, texts []string, w func([]string)) {
+				if !hasNonEmpty(texts) {
+					return
+				}
+
+				for _, lang := range translated {
+					if localization == nil || !hasNonEmpty(localization.GetItemTranslation(lang, uuid, property)) {
+						report(newMissingTranslation(node.UUID(), action.UUID(), lang, property))
+					}
+				}
+			})
+		}
+	}
+}
+
+// checks whether any of the given texts is non-empty
+func hasNonEmpty(texts []string) bool {
+	for _, t := range texts {
+		if t != "" {
+			return true
+		}
+	}
+	return false
+}
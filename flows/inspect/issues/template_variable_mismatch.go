@@ -0,0 +1,89 @@
+package issues
+
+import (
+	"fmt"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/actions"
+	"github.com/nyaruka/gocommon/uuids"
+)
+
+func init() {
+	registerType(TypeTemplateVariableMismatch, TemplateVariableMismatchCheck)
+}
+
+// TypeTemplateVariableMismatch is our type for templating with the wrong number of variables
+const TypeTemplateVariableMismatch string = "template_variable_mismatch"
+
+// TemplateVariableMismatch is templating on a message which provides a different number of variables to what a
+// translation of the template expects
+type TemplateVariableMismatch struct {
+	baseIssue
+
+	Template       *assets.TemplateReference `json:"template"`
+	Locale         string                    `json:"locale"`
+	ExpectedCount  int                       `json:"expected_count"`
+	VariablesCount int                       `json:"variables_count"`
+}
+
+func newTemplateVariableMismatch(nodeUUID flows.NodeUUID, actionUUID flows.ActionUUID, language envs.Language, template *assets.TemplateReference, locale envs.Locale, expected, actual int) *TemplateVariableMismatch {
+	return &TemplateVariableMismatch{
+		baseIssue: newBaseIssue(
+			TypeTemplateVariableMismatch,
+			nodeUUID,
+			actionUUID,
+			language,
+			fmt.Sprintf("template '%s' in %s expects %d variables but %d are provided", template.Name, locale.ToBCP47(), expected, actual),
+		),
+		Template:       template,
+		Locale:         locale.ToBCP47(),
+		ExpectedCount:  expected,
+		VariablesCount: actual,
+	}
+}
+
+// TemplateVariableMismatchCheck checks that message templating provides the number of variables expected by each
+// translation of the template
+func TemplateVariableMismatchCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language, report func(flows.Issue)) {
+	// skip check if we don't have assets
+	if sa == nil {
+		return
+	}
+
+	for _, node := range flow.Nodes() {
+		for _, action := range node.Actions() {
+			sendMsg, isSendMsg := action.(*actions.SendMsgAction)
+			if !isSendMsg || sendMsg.Templating == nil {
+				continue
+			}
+
+			template := sa.Templates().Get(sendMsg.Templating.Template.UUID)
+			if template == nil {
+				continue // reported as a missing dependency
+			}
+
+			checkVariables := func(lang envs.Language, variables []string) {
+				for _, trans := range template.Translations() {
+					tt := flows.NewTemplateTranslation(trans)
+
+					if tt.VariableCount() != len(variables) {
+						report(newTemplateVariableMismatch(node.UUID(), action.UUID(), lang, template.Reference(), tt.Locale(), tt.VariableCount(), len(variables)))
+					}
+				}
+			}
+
+			checkVariables(envs.NilLanguage, sendMsg.Templating.Variables)
+
+			if flow.Localization() != nil {
+				for _, lang := range sortedLanguages(flow.Localization()) {
+					translated := flow.Localization().GetItemTranslation(lang, uuids.UUID(sendMsg.Templating.UUID), "variables")
+					if translated != nil {
+						checkVariables(lang, translated)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package issues
+
+import (
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/inspect"
+)
+
+// type checks the expressions in the given templates and calls the callback with each problem of the given kind,
+// reporting the same problem in the same node, action and language only once
+func typeCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, kind string, callback func(flows.NodeUUID, flows.ActionUUID, envs.Language, *excellent.Problem)) {
+	root := inspect.ContextType(sa, flow)
+
+	type reported struct {
+		node        flows.NodeUUID
+		action      flows.ActionUUID
+		language    envs.Language
+		description string
+	}
+	seen := make(map[reported]bool)
+
+	for _, tpl := range tpls {
+		var actionUUID flows.ActionUUID
+		if tpl.Action != nil {
+			actionUUID = tpl.Action.UUID()
+		}
+
+		excellent.TypeCheckTemplate(tpl.Template, flows.RunContextTopLevels, root, func(p *excellent.Problem) {
+			r := reported{tpl.Node.UUID(), actionUUID, tpl.Language, p.Description}
+			if p.Kind == kind && !seen[r] {
+				callback(tpl.Node.UUID(), actionUUID, tpl.Language, p)
+				seen[r] = true
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package issues
+
+import (
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/flows"
+)
+
+func init() {
+	registerType(TypeTypeMismatch, TypeMismatchCheck)
+}
+
+// TypeTypeMismatch is our type for a value in an expression which likely has the wrong type
+const TypeTypeMismatch string = "type_mismatch"
+
+// TypeMismatch is a function argument or operand in an expression which likely has the wrong type, e.g.
+// @(abs("ten")) or @(contact.name.first)
+type TypeMismatch struct {
+	baseIssue
+
+	Expression string `json:"expression"`
+}
+
+func newTypeMismatch(nodeUUID flows.NodeUUID, actionUUID flows.ActionUUID, language envs.Language, expression, description string) *TypeMismatch {
+	return &TypeMismatch{
+		baseIssue: newBaseIssue(
+			TypeTypeMismatch,
+			nodeUUID,
+			actionUUID,
+			language,
+			description,
+		),
+		Expression: expression,
+	}
+}
+
+// TypeMismatchCheck checks for function arguments and operands which likely have the wrong type
+func TypeMismatchCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language, report func(flows.Issue)) {
+	typeCheck(sa, flow, tpls, excellent.ProblemTypeMismatch, func(n flows.NodeUUID, a flows.ActionUUID, l envs.Language, p *excellent.Problem) {
+		report(newTypeMismatch(n, a, l, p.Expression, p.Description))
+	})
+}
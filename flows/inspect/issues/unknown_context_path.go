@@ -0,0 +1,42 @@
+package issues
+
+import (
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/flows"
+)
+
+func init() {
+	registerType(TypeUnknownContextPath, UnknownContextPathCheck)
+}
+
+// TypeUnknownContextPath is our type for a reference to a context path which doesn't exist
+const TypeUnknownContextPath string = "unknown_context_path"
+
+// UnknownContextPath is a reference in an expression to a property which doesn't exist in the context, e.g.
+// @contact.feilds.age
+type UnknownContextPath struct {
+	baseIssue
+
+	Expression string `json:"expression"`
+}
+
+func newUnknownContextPath(nodeUUID flows.NodeUUID, actionUUID flows.ActionUUID, language envs.Language, expression, description string) *UnknownContextPath {
+	return &UnknownContextPath{
+		baseIssue: newBaseIssue(
+			TypeUnknownContextPath,
+			nodeUUID,
+			actionUUID,
+			language,
+			description,
+		),
+		Expression: expression,
+	}
+}
+
+// UnknownContextPathCheck checks for expressions which reference properties that don't exist in the context
+func UnknownContextPathCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language, report func(flows.Issue)) {
+	typeCheck(sa, flow, tpls, excellent.ProblemUnknownPath, func(n flows.NodeUUID, a flows.ActionUUID, l envs.Language, p *excellent.Problem) {
+		report(newUnknownContextPath(n, a, l, p.Expression, p.Description))
+	})
+}
@@ -0,0 +1,46 @@
+package issues
+
+import (
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+)
+
+func init() {
+	registerType(TypeUnreachableNode, UnreachableNodeCheck)
+}
+
+// TypeUnreachableNode is our type for a node which can't be reached
+const TypeUnreachableNode string = "unreachable_node"
+
+// UnreachableNode is a node which can't be reached from the entry node of the flow
+type UnreachableNode struct {
+	baseIssue
+}
+
+func newUnreachableNode(nodeUUID flows.NodeUUID) *UnreachableNode {
+	return &UnreachableNode{
+		baseIssue: newBaseIssue(
+			TypeUnreachableNode,
+			nodeUUID,
+			"",
+			"",
+			"node can't be reached from the start of the flow",
+		),
+	}
+}
+
+// UnreachableNodeCheck checks for nodes that can't be reached from the entry node
+func UnreachableNodeCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language, report func(flows.Issue)) {
+	if len(flow.Nodes()) == 0 {
+		return
+	}
+
+	entry := flow.Nodes()[0]
+	reachable := reachableFrom(flow, entry)
+
+	for _, node := range flow.Nodes()[1:] {
+		if !reachable[node.UUID()] {
+			report(newUnreachableNode(node.UUID()))
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package issues
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent/tools"
+	"github.com/developc3ntro/omni-goflow/flows"
+)
+
+func init() {
+	registerType(TypeUnsetResult, UnsetResultCheck)
+}
+
+// TypeUnsetResult is our type for a reference to a result which hasn't been set
+const TypeUnsetResult string = "unset_result"
+
+// UnsetResult is a reference to a result which isn't set by any node that can come before the reference
+type UnsetResult struct {
+	baseIssue
+
+	Result string `json:"result"`
+}
+
+func newUnsetResult(nodeUUID flows.NodeUUID, actionUUID flows.ActionUUID, language envs.Language, result string) *UnsetResult {
+	return &UnsetResult{
+		baseIssue: newBaseIssue(
+			TypeUnsetResult,
+			nodeUUID,
+			actionUUID,
+			language,
+			fmt.Sprintf("result '%s' is not set before it is referenced", result),
+		),
+		Result: result,
+	}
+}
+
+// UnsetResultCheck checks for references to results which can't have been set
+func UnsetResultCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language, report func(flows.Issue)) {
+	ancestors := ancestors(flow)
+
+	// gather the results set by each node's actions and router
+	actionResults := make(map[flows.ActionUUID][]string)
+	nodeResults := make(map[flows.NodeUUID][]string)
+
+	for _, node := range flow.Nodes() {
+		node.EnumerateResults(func(a flows.Action, r flows.Router, i *flows.ResultInfo) {
+			if a != nil {
+				actionResults[a.UUID()] = append(actionResults[a.UUID()], i.Key)
+			}
+			nodeResults[node.UUID()] = append(nodeResults[node.UUID()], i.Key)
+		})
+	}
+
+	// determines the results which might be set when the given node and action (nil for the router) begin
+	availableResults := func(node flows.Node, action flows.Action) map[string]bool {
+		available := make(map[string]bool)
+		for uuid := range ancestors[node.UUID()] {
+			for _, key := range nodeResults[uuid] {
+				available[key] = true
+			}
+		}
+		for _, a := range node.Actions() {
+			if action != nil && a.UUID() == action.UUID() {
+				break
+			}
+			for _, key := range actionResults[a.UUID()] {
+				available[key] = true
+			}
+		}
+		return available
+	}
+
+	type reported struct {
+		node     flows.NodeUUID
+		action   flows.ActionUUID
+		language envs.Language
+		result   string
+	}
+	seen := make(map[reported]bool)
+
+	for _, tpl := range tpls {
+		var available map[string]bool
+
+		tools.FindContextRefsInTemplate(tpl.Template, flows.RunContextTopLevels, func(path []string) {
+			key := resultRefKey(path)
+			if key == "" {
+				return
+			}
+
+			if available == nil {
+				available = availableResults(tpl.Node, tpl.Action)
+			}
+
+			var actionUUID flows.ActionUUID
+			if tpl.Action != nil {
+				actionUUID = tpl.Action.UUID()
+			}
+
+			r := reported{tpl.Node.UUID(), actionUUID, tpl.Language, key}
+			if !available[key] && !seen[r] {
+				report(newUnsetResult(tpl.Node.UUID(), actionUUID, tpl.Language, key))
+				seen[r] = true
+			}
+		})
+	}
+}
+
+// gets the result key from a context path like results.foo or run.results.foo
+func resultRefKey(path []string) string {
+	if len(path) > 1 && strings.ToLower(path[0]) == "results" {
+		return strings.ToLower(path[1])
+	}
+	if len(path) > 2 && strings.ToLower(path[0]) == "run" && strings.ToLower(path[1]) == "results" {
+		return strings.ToLower(path[2])
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package issues
+
+import (
+	"fmt"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/actions"
+)
+
+func init() {
+	registerType(TypeWaitInBackground, WaitInBackgroundCheck)
+}
+
+// TypeWaitInBackground is our type for a wait which can happen in a background flow
+const TypeWaitInBackground string = "wait_in_background"
+
+// WaitInBackground is a background flow entering a subflow which can wait for input. Background flows can't
+// contain waits themselves, but nothing stops them entering a flow which does.
+type WaitInBackground struct {
+	baseIssue
+
+	Flow *assets.FlowReference `json:"flow"`
+}
+
+func newWaitInBackground(nodeUUID flows.NodeUUID, actionUUID flows.ActionUUID, flow *assets.FlowReference) *WaitInBackground {
+	return &WaitInBackground{
+		baseIssue: newBaseIssue(
+			TypeWaitInBackground,
+			nodeUUID,
+			actionUUID,
+			"",
+			fmt.Sprintf("background flow enters flow '%s' which can wait for input", flow.Name),
+		),
+		Flow: flow,
+	}
+}
+
+// WaitInBackgroundCheck checks for background flows which enter subflows that can wait for input
+func WaitInBackgroundCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language, report func(flows.Issue)) {
+	// skip check if we don't have assets or this isn't a background flow
+	if sa == nil || flow.Type() != flows.FlowTypeMessagingBackground {
+		return
+	}
+
+	for _, node := range flow.Nodes() {
+		for _, action := range node.Actions() {
+			enterFlow, isEnterFlow := action.(*actions.EnterFlowAction)
+			if !isEnterFlow {
+				continue
+			}
+
+			if flowCanWait(sa, enterFlow.Flow, map[assets.FlowUUID]bool{flow.UUID(): true}) {
+				report(newWaitInBackground(node.UUID(), action.UUID(), enterFlow.Flow))
+			}
+		}
+	}
+}
+
+// checks whether the referenced flow or any flow it enters has a wait
+func flowCanWait(sa flows.SessionAssets, ref *assets.FlowReference, checked map[assets.FlowUUID]bool) bool {
+	if checked[ref.UUID] {
+		return false
+	}
+	checked[ref.UUID] = true
+
+	flow, err := sa.Flows().Get(ref.UUID)
+	if err != nil {
+		return false // reported as a missing dependency
+	}
+
+	for _, node := range flow.Nodes() {
+		if node.Router() != nil && node.Router().Wait() != nil {
+			return true
+		}
+
+		for _, action := range node.Actions() {
+			if enterFlow, isEnterFlow := action.(*actions.EnterFlowAction); isEnterFlow && flowCanWait(sa, enterFlow.Flow, checked) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package issues
+
+import (
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/flows"
+)
+
+func init() {
+	registerType(TypeWrongArgCount, WrongArgCountCheck)
+}
+
+// TypeWrongArgCount is our type for a function call with the wrong number of arguments
+const TypeWrongArgCount string = "wrong_arg_count"
+
+// WrongArgCount is a function call in an expression with the wrong number of arguments, e.g. @(upper(5, 6))
+type WrongArgCount struct {
+	baseIssue
+
+	Expression string `json:"expression"`
+}
+
+func newWrongArgCount(nodeUUID flows.NodeUUID, actionUUID flows.ActionUUID, language envs.Language, expression, description string) *WrongArgCount {
+	return &WrongArgCount{
+		baseIssue: newBaseIssue(
+			TypeWrongArgCount,
+			nodeUUID,
+			actionUUID,
+			language,
+			description,
+		),
+		Expression: expression,
+	}
+}
+
+// WrongArgCountCheck checks for function calls with the wrong number of arguments
+func WrongArgCountCheck(sa flows.SessionAssets, flow flows.Flow, tpls []flows.ExtractedTemplate, refs []flows.ExtractedReference, languages []envs.Language, report func(flows.Issue)) {
+	typeCheck(sa, flow, tpls, excellent.ProblemArgCount, func(n flows.NodeUUID, a flows.ActionUUID, l envs.Language, p *excellent.Problem) {
+		report(newWrongArgCount(n, a, l, p.Expression, p.Description))
+	})
+}
@@ -17,6 +17,16 @@ type EngineField struct {
 	Getter    func(reflect.Value) reflect.Value
 }
 
+// Fields calls the given function with each engine field of the given struct and its value, e.g. to compare actions
+func Fields(s interface{}, include func(*EngineField, reflect.Value)) {
+	v := reflect.ValueOf(s)
+	rv := derefValue(v)
+
+	for _, ef := range extractEngineFields(v.Type(), rv.Type()) {
+		include(ef, ef.Getter(rv))
+	}
+}
+
 // extracts all engine fields from the given type
 func extractEngineFields(t reflect.Type, rt reflect.Type) []*EngineField {
 	fields := make([]*EngineField, 0)
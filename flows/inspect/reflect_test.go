@@ -162,3 +162,17 @@ func TestWalkTypes(t *testing.T) {
 
 	assert.Equal(t, []string{".foo", ".bar", ".sub", ".sub.zed", ".slice", ".slice[*].zed"}, paths)
 }
+
+func TestFields(t *testing.T) {
+	v := &containerStruct{embeddedType: embeddedType{Foo: "Hello"}, Bar: "World", Sub: subType{Zed: "Now"}}
+
+	names := make([]string, 0)
+	values := make([]interface{}, 0)
+	Fields(v, func(ef *EngineField, fv reflect.Value) {
+		names = append(names, ef.JSONName)
+		values = append(values, fv.Interface())
+	})
+
+	assert.Equal(t, []string{"foo", "bar", "sub", "slice"}, names)
+	assert.Equal(t, []interface{}{"Hello", "World", subType{Zed: "Now"}, []subType(nil)}, values)
+}
@@ -103,6 +103,11 @@ func ExtractFromTemplate(template string) ([]assets.Reference, []string) {
 			}
 		}
 	})
+
+	tools.FindFunctionCallsInTemplate(template, flows.RunContextTopLevels, func(name string) {
+		assetRefs = append(assetRefs, assets.NewFunctionReference(name))
+	})
+
 	return assetRefs, parentRefs
 }
 
@@ -78,6 +78,8 @@ func TestTemplatePaths(t *testing.T) {
 		"$.nodes[*].actions[@.type=\"add_contact_groups\"].groups[*].name_match",
 		"$.nodes[*].actions[@.type=\"add_contact_urn\"].path",
 		"$.nodes[*].actions[@.type=\"add_input_labels\"].labels[*].name_match",
+		"$.nodes[*].actions[@.type=\"add_ticket_note\"].note",
+		"$.nodes[*].actions[@.type=\"assign_ticket\"].assignee.email_match",
 		"$.nodes[*].actions[@.type=\"call_classifier\"].input",
 		"$.nodes[*].actions[@.type=\"call_webhook\"].body",
 		"$.nodes[*].actions[@.type=\"call_webhook\"].headers[*]",
@@ -94,7 +96,12 @@ func TestTemplatePaths(t *testing.T) {
 		"$.nodes[*].actions[@.type=\"send_broadcast\"].quick_replies[*]",
 		"$.nodes[*].actions[@.type=\"send_broadcast\"].text",
 		"$.nodes[*].actions[@.type=\"send_email\"].addresses[*]",
+		"$.nodes[*].actions[@.type=\"send_email\"].attachments[*]",
+		"$.nodes[*].actions[@.type=\"send_email\"].bcc[*]",
 		"$.nodes[*].actions[@.type=\"send_email\"].body",
+		"$.nodes[*].actions[@.type=\"send_email\"].cc[*]",
+		"$.nodes[*].actions[@.type=\"send_email\"].html",
+		"$.nodes[*].actions[@.type=\"send_email\"].reply_to",
 		"$.nodes[*].actions[@.type=\"send_email\"].subject",
 		"$.nodes[*].actions[@.type=\"send_msg\"].attachments[*]",
 		"$.nodes[*].actions[@.type=\"send_msg\"].quick_replies[*]",
@@ -170,6 +177,15 @@ func TestExtractFromTemplate(t *testing.T) {
 			},
 			[]string{"state"},
 		},
+		{
+			`Call @(format_phone(urns.tel)) or @(UPPER(format_phone(fields.work_phone)))`,
+			[]assets.Reference{
+				assets.NewFieldReference("work_phone", ""),
+				assets.NewFunctionReference("format_phone"),
+				assets.NewFunctionReference("format_phone"),
+			},
+			[]string{},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -105,6 +105,7 @@ type SessionAssets interface {
 	Classifiers() *ClassifierAssets
 	Fields() *FieldAssets
 	Flows() FlowAssets
+	Functions() *FunctionAssets
 	Globals() *GlobalAssets
 	Groups() *GroupAssets
 	Labels() *LabelAssets
@@ -140,7 +141,7 @@ type Flow interface {
 	Asset() assets.Flow
 	Reference() *assets.FlowReference
 
-	Inspect(sa SessionAssets) *Inspection
+	Inspect(sa SessionAssets, languages ...envs.Language) *Inspection
 	ExtractTemplates() []string
 	ExtractLocalizables() []string
 	ChangeLanguage(envs.Language) (Flow, error)
@@ -325,6 +326,9 @@ type Engine interface {
 	MaxStepsPerSprint() int
 	MaxResumesPerSession() int
 	MaxTemplateChars() int
+	MaxFunctionDepth() int
+	MaxFunctionCalls() int
+	ExpressionCache() *excellent.Cache
 }
 
 // Segment is a movement on the flow graph from an exit to another node
@@ -70,6 +70,54 @@ func init() {
 	for name, fn := range builtin {
 		RegisterXTest(name, fn)
 	}
+
+	fixed, optional, alternative, variadic := functions.FixedSignature, functions.OptionalSignature, functions.AlternativeSignature, functions.VariadicSignature
+	text, number, datetime, array, object := functions.TypeText, functions.TypeNumber, functions.TypeDateTime, functions.TypeArray, functions.TypeObject
+
+	signatures := map[string]*functions.Signature{
+		"has_error": fixed(object, functions.TypeAny),
+
+		"has_only_text":   fixed(object, text, text),
+		"has_phrase":      fixed(object, text, text),
+		"has_only_phrase": fixed(object, text, text),
+		"has_any_word":    fixed(object, text, text),
+		"has_all_words":   fixed(object, text, text),
+		"has_beginning":   fixed(object, text, text),
+		"has_text":        fixed(object, text),
+		"has_pattern":     fixed(object, text, text),
+
+		"has_number":         fixed(object, text),
+		"has_number_between": fixed(object, text, number, number),
+		"has_number_lt":      fixed(object, text, number),
+		"has_number_lte":     fixed(object, text, number),
+		"has_number_eq":      fixed(object, text, number),
+		"has_number_gte":     fixed(object, text, number),
+		"has_number_gt":      fixed(object, text, number),
+
+		"has_date":    fixed(object, text),
+		"has_date_lt": fixed(object, text, datetime),
+		"has_date_eq": fixed(object, text, datetime),
+		"has_date_gt": fixed(object, text, datetime),
+
+		"has_time":  fixed(object, text),
+		"has_phone": optional(1, object, text, text),
+		"has_email": fixed(object, text),
+		"has_group": optional(2, object, array, text, text),
+
+		"has_category":   variadic(2, object, object, text),
+		"has_intent":     fixed(object, object, text, number),
+		"has_top_intent": fixed(object, object, text, number),
+
+		"has_state":    fixed(object, text),
+		"has_district": optional(1, object, text, text),
+		"has_ward":     alternative([]int{1, 3}, object, text, text, text),
+
+		"has_value": fixed(object, text),
+	}
+
+	for name, sig := range signatures {
+		functions.RegisterSignature(name, sig)
+	}
 }
 
 // RegisterXTest registers a new router test (and Excellent function)
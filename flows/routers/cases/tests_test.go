@@ -8,6 +8,7 @@ import (
 	"github.com/developc3ntro/omni-goflow/assets"
 	"github.com/developc3ntro/omni-goflow/envs"
 	"github.com/developc3ntro/omni-goflow/excellent"
+	"github.com/developc3ntro/omni-goflow/excellent/functions"
 	"github.com/developc3ntro/omni-goflow/excellent/types"
 	"github.com/developc3ntro/omni-goflow/flows"
 	"github.com/developc3ntro/omni-goflow/flows/routers/cases"
@@ -492,6 +493,38 @@ func TestTests(t *testing.T) {
 	}
 }
 
+func TestSignatures(t *testing.T) {
+	env := envs.NewBuilder().Build()
+
+	callWithArgs := func(f *types.XFunction, num int) types.XValue {
+		args := make([]types.XValue, num)
+		for i := range args {
+			args[i] = types.NewXText("")
+		}
+		return f.Call(env, args)
+	}
+
+	for name, f := range cases.XTESTS {
+		sig := functions.LookupSignature(name)
+		if !assert.NotNil(t, sig, "missing signature for router test %s", name) {
+			continue
+		}
+
+		// check that the argument counts match what the test actually accepts
+		if sig.MinArgs > 0 {
+			assert.Regexp(t, `argument`, callWithArgs(f, sig.MinArgs-1), "expected error calling %s with %d args", name, sig.MinArgs-1)
+		}
+		if sig.MaxArgs >= 0 {
+			assert.Regexp(t, `argument`, callWithArgs(f, sig.MaxArgs+1), "expected error calling %s with %d args", name, sig.MaxArgs+1)
+		}
+		for n := sig.MinArgs; n < sig.MaxArgs; n++ {
+			if !sig.Accepts(n) {
+				assert.Regexp(t, `argument`, callWithArgs(f, n), "expected error calling %s with %d args", name, n)
+			}
+		}
+	}
+}
+
 func TestEvaluateTemplate(t *testing.T) {
 	ctx := types.NewXObject(map[string]types.XValue{
 		"int1":   types.NewXNumberFromInt(1),
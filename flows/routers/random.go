@@ -1,6 +1,8 @@
 package routers
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 
@@ -9,6 +11,7 @@ import (
 	"github.com/nyaruka/gocommon/jsonx"
 	"github.com/nyaruka/gocommon/random"
 
+	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 )
 
@@ -19,45 +22,135 @@ func init() {
 // TypeRandom is the type for a random router
 const TypeRandom string = "random"
 
-// RandomRouter is a router which will exit out a random exit
+// RandomRouter is a router which will exit out a random exit. By default each category is equally likely but
+// weights can be given to make some categories more likely than others. If the router is sticky, then the
+// random value is derived from the contact and node so that a contact always takes the same exit.
 type RandomRouter struct {
 	baseRouter
+
+	weights []decimal.Decimal
+	sticky  bool
 }
 
 // NewRandom creates a new random router
 func NewRandom(wait flows.Wait, resultName string, categories []flows.Category) *RandomRouter {
-	return &RandomRouter{newBaseRouter(TypeRandom, wait, resultName, categories)}
+	return &RandomRouter{baseRouter: newBaseRouter(TypeRandom, wait, resultName, categories)}
+}
+
+// NewWeightedRandom creates a new random router with the given category weights
+func NewWeightedRandom(wait flows.Wait, resultName string, categories []flows.Category, weights []decimal.Decimal, sticky bool) *RandomRouter {
+	return &RandomRouter{baseRouter: newBaseRouter(TypeRandom, wait, resultName, categories), weights: weights, sticky: sticky}
 }
 
+// Weights returns the weights of the categories on this router
+func (r *RandomRouter) Weights() []decimal.Decimal { return r.weights }
+
+// Sticky returns whether this router always routes a contact to the same category
+func (r *RandomRouter) Sticky() bool { return r.sticky }
+
 // Validate validates that the fields on this router are valid
 func (r *RandomRouter) Validate(flow flows.Flow, exits []flows.Exit) error {
+	if r.weights != nil {
+		if len(r.weights) != len(r.categories) {
+			return errors.Errorf("number of weights (%d) must match number of categories (%d)", len(r.weights), len(r.categories))
+		}
+
+		total := decimal.Zero
+		for _, w := range r.weights {
+			if w.IsNegative() {
+				return errors.Errorf("weight %s is negative", w)
+			}
+			total = total.Add(w)
+		}
+		if total.IsZero() {
+			return errors.New("weights must include at least one non-zero weight")
+		}
+	}
+
 	return r.validate(flow, exits)
 }
 
 // Route determines which exit to take from a node
 func (r *RandomRouter) Route(run flows.Run, step flows.Step, logEvent flows.EventCallback) (flows.ExitUUID, string, error) {
-	// pick a random category
-	rand := random.Decimal()
-	categoryNum := rand.Mul(decimal.New(int64(len(r.categories)), 0)).IntPart()
+	var rand decimal.Decimal
+	if r.sticky && run.Contact() != nil {
+		rand = stickyDecimal(string(run.Contact().UUID()), string(step.NodeUUID()))
+	} else {
+		rand = random.Decimal()
+	}
+
+	// pick a category
+	categoryNum := r.pickCategory(rand)
 	categoryUUID := r.categories[categoryNum].UUID()
 
 	exit, err := r.routeToCategory(run, step, categoryUUID, fmt.Sprintf("%d", categoryNum), rand.String(), nil, logEvent)
 	return exit, rand.String(), err
 }
 
+// picks the category which the given value in the range [0, 1) lands in
+func (r *RandomRouter) pickCategory(rand decimal.Decimal) int {
+	if r.weights == nil {
+		return int(rand.Mul(decimal.New(int64(len(r.categories)), 0)).IntPart())
+	}
+
+	total := decimal.Zero
+	for _, w := range r.weights {
+		total = total.Add(w)
+	}
+
+	target := rand.Mul(total)
+	cumulative := decimal.Zero
+	lastNonZero := 0
+
+	for i, w := range r.weights {
+		if w.IsZero() {
+			continue
+		}
+		cumulative = cumulative.Add(w)
+		lastNonZero = i
+
+		if target.LessThan(cumulative) {
+			return i
+		}
+	}
+	return lastNonZero
+}
+
+// generates a value in the range [0, 1) from a hash of the given keys
+func stickyDecimal(keys ...string) decimal.Decimal {
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+	}
+
+	// use the top 53 bits of the hash so the value is exactly representable as a float
+	bits := binary.BigEndian.Uint64(h.Sum(nil)[:8]) >> 11
+	return decimal.NewFromFloat(float64(bits) / float64(1<<53))
+}
+
 //------------------------------------------------------------------------------------------
 // JSON Encoding / Decoding
 //------------------------------------------------------------------------------------------
 
+type randomRouterEnvelope struct {
+	baseRouterEnvelope
+
+	Weights []decimal.Decimal `json:"weights,omitempty"`
+	Sticky  bool              `json:"sticky,omitempty"`
+}
+
 func readRandomRouter(data json.RawMessage) (flows.Router, error) {
-	e := &baseRouterEnvelope{}
+	e := &randomRouterEnvelope{}
 	if err := utils.UnmarshalAndValidate(data, e); err != nil {
 		return nil, err
 	}
 
-	r := &RandomRouter{}
+	r := &RandomRouter{
+		weights: e.Weights,
+		sticky:  e.Sticky,
+	}
 
-	if err := r.unmarshal(e); err != nil {
+	if err := r.unmarshal(&e.baseRouterEnvelope); err != nil {
 		return nil, err
 	}
 
@@ -66,9 +159,12 @@ func readRandomRouter(data json.RawMessage) (flows.Router, error) {
 
 // MarshalJSON marshals this resume into JSON
 func (r *RandomRouter) MarshalJSON() ([]byte, error) {
-	e := &baseRouterEnvelope{}
+	e := &randomRouterEnvelope{
+		Weights: r.weights,
+		Sticky:  r.sticky,
+	}
 
-	if err := r.marshal(e); err != nil {
+	if err := r.marshal(&e.baseRouterEnvelope); err != nil {
 		return nil, err
 	}
 
@@ -91,9 +91,15 @@ func NewSwitch(wait flows.Wait, resultName string, categories []flows.Category,
 	}
 }
 
+// Operand returns the operand for this switch router
+func (r *SwitchRouter) Operand() string { return r.operand }
+
 // Cases returns the cases for this switch router
 func (r *SwitchRouter) Cases() []*Case { return r.cases }
 
+// DefaultCategoryUUID returns the UUID of the category taken if no case matches
+func (r *SwitchRouter) DefaultCategoryUUID() flows.CategoryUUID { return r.defaultCategoryUUID }
+
 // Validate validates the arguments for this router
 func (r *SwitchRouter) Validate(flow flows.Flow, exits []flows.Exit) error {
 	// check the default category is valid
@@ -217,10 +217,8 @@ func (r *flowRun) RootContext(env envs.Environment) map[string]types.XValue {
 		urns = flows.ContextFunc(env, r.Contact().URNs().MapContext)
 		fields = flows.Context(env, r.Contact().Fields())
 
-		tickets := r.Contact().Tickets()
-
-		if tickets.Count() > 0 {
-			ticket = flows.Context(env, tickets.All()[tickets.Count()-1])
+		if last := r.Contact().Tickets().Last(); last != nil {
+			ticket = flows.Context(env, last)
 		}
 	}
 
@@ -312,20 +310,25 @@ func (r *flowRun) nodeContext(env envs.Environment) map[string]types.XValue {
 func (r *flowRun) EvaluateTemplateValue(template string) (types.XValue, error) {
 	ctx := types.NewXObject(r.RootContext(r.Environment()))
 
-	return excellent.EvaluateTemplateValue(r.Environment(), ctx, template)
+	return r.Session().Engine().ExpressionCache().EvaluateTemplateValue(r.Environment(), ctx, r.functionScope(), template)
 }
 
 // EvaluateTemplateText evaluates the given template as text in the context of this run
 func (r *flowRun) EvaluateTemplateText(template string, escaping excellent.Escaping, truncate bool) (string, error) {
 	ctx := types.NewXObject(r.RootContext(r.Environment()))
 
-	value, err := excellent.EvaluateTemplate(r.Environment(), ctx, template, escaping)
+	value, err := r.Session().Engine().ExpressionCache().EvaluateTemplate(r.Environment(), ctx, r.functionScope(), template, escaping)
 	if truncate {
 		value = utils.TruncateEllipsis(value, r.Session().Engine().MaxTemplateChars())
 	}
 	return value, err
 }
 
+// gets the scope in which functions defined as assets can be called
+func (r *flowRun) functionScope() *excellent.Scope {
+	return r.Session().Assets().Functions().Scope(r.Session().Engine().MaxFunctionDepth(), r.Session().Engine().MaxFunctionCalls())
+}
+
 // EvaluateTemplate is a convenience function for evaluating as text with no escaping
 func (r *flowRun) EvaluateTemplate(template string) (string, error) {
 	return r.EvaluateTemplateText(template, nil, true)
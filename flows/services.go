@@ -21,9 +21,21 @@ type Services interface {
 	Airtime(Session) (AirtimeService, error)
 }
 
+// Email is an email message to be sent by an email service
+type Email struct {
+	To          []string
+	Cc          []string
+	Bcc         []string
+	ReplyTo     string
+	Subject     string
+	Body        string
+	HTML        string
+	Attachments []utils.Attachment
+}
+
 // EmailService provides email functionality to the engine
 type EmailService interface {
-	Send(session Session, addresses []string, subject, body string) error
+	Send(session Session, email *Email, logHTTP HTTPLogCallback) error
 }
 
 // CallStatus represents the status of a call to an external service
@@ -47,7 +59,10 @@ const (
 type WebhookCall struct {
 	*httpx.Trace
 	ResponseJSON    []byte
-	ResponseCleaned bool // whether response had to be cleaned to make it valid JSON
+	ResponseCleaned bool           // whether response had to be cleaned to make it valid JSON
+	Cached          bool           // whether response came from a cache rather than a new request
+	CircuitOpen     bool           // whether request was skipped because the host is failing
+	Redactor        utils.Redactor // masks any secrets when the call is logged, but not in what the flow sees
 }
 
 // WebhookService provides webhook functionality to the engine
@@ -82,6 +97,18 @@ type ClassificationService interface {
 type TicketService interface {
 	// Open tries to open a new ticket
 	Open(session Session, topic *Topic, body string, assignee *User, logHTTP HTTPLogCallback) (*Ticket, error)
+
+	// Close tries to close the given ticket
+	Close(session Session, ticket *Ticket, logHTTP HTTPLogCallback) error
+
+	// Reopen tries to reopen the given ticket
+	Reopen(session Session, ticket *Ticket, logHTTP HTTPLogCallback) error
+
+	// AddNote tries to add an internal note to the given ticket
+	AddNote(session Session, ticket *Ticket, note string, logHTTP HTTPLogCallback) error
+
+	// Assign tries to assign the given ticket to the given user
+	Assign(session Session, ticket *Ticket, assignee *User, logHTTP HTTPLogCallback) error
 }
 
 // AirtimeTransferStatus is a status of a airtime transfer
@@ -129,6 +156,11 @@ func NewHTTPTrace(trace *httpx.Trace, status CallStatus) *HTTPTrace {
 	return newHTTPTraceWithStatus(trace, status, nil)
 }
 
+// NewRedactedHTTPTrace creates a new HTTP log from a trace, masking any secrets with the given redactor which may be nil
+func NewRedactedHTTPTrace(trace *httpx.Trace, status CallStatus, redact utils.Redactor) *HTTPTrace {
+	return newHTTPTraceWithStatus(trace, status, redact)
+}
+
 // HTTPLog describes an HTTP request/response
 type HTTPLog struct {
 	*HTTPTrace
@@ -7,11 +7,28 @@ import (
 	"github.com/developc3ntro/omni-goflow/utils"
 	"github.com/nyaruka/gocommon/jsonx"
 	"github.com/nyaruka/gocommon/uuids"
+
+	"gopkg.in/go-playground/validator.v9"
 )
 
+func init() {
+	utils.RegisterValidatorAlias("ticket_status", "eq=open|eq=closed", func(validator.FieldError) string {
+		return "is not a valid ticket status"
+	})
+}
+
 // TicketUUID is the UUID of a ticket
 type TicketUUID uuids.UUID
 
+// TicketStatus is the status of a ticket
+type TicketStatus string
+
+// possible values for ticket statuses
+const (
+	TicketStatusOpen   TicketStatus = "open"
+	TicketStatusClosed TicketStatus = "closed"
+)
+
 // Ticket is a ticket in a ticketing system
 type Ticket struct {
 	uuid       TicketUUID
@@ -20,9 +37,10 @@ type Ticket struct {
 	body       string
 	externalID string
 	assignee   *User
+	status     TicketStatus
 }
 
-// NewTicket creates a new ticket
+// NewTicket creates a new open ticket
 func NewTicket(uuid TicketUUID, ticketer *Ticketer, topic *Topic, body, externalID string, assignee *User) *Ticket {
 	return &Ticket{
 		uuid:       uuid,
@@ -31,6 +49,7 @@ func NewTicket(uuid TicketUUID, ticketer *Ticketer, topic *Topic, body, external
 		body:       body,
 		externalID: externalID,
 		assignee:   assignee,
+		status:     TicketStatusOpen,
 	}
 }
 
@@ -39,19 +58,23 @@ func OpenTicket(ticketer *Ticketer, topic *Topic, body string, assignee *User) *
 	return NewTicket(TicketUUID(uuids.New()), ticketer, topic, body, "", assignee)
 }
 
-func (t *Ticket) UUID() TicketUUID        { return t.uuid }
-func (t *Ticket) Ticketer() *Ticketer     { return t.ticketer }
-func (t *Ticket) Topic() *Topic           { return t.topic }
-func (t *Ticket) Body() string            { return t.body }
-func (t *Ticket) ExternalID() string      { return t.externalID }
-func (t *Ticket) SetExternalID(id string) { t.externalID = id }
-func (t *Ticket) Assignee() *User         { return t.assignee }
+func (t *Ticket) UUID() TicketUUID         { return t.uuid }
+func (t *Ticket) Ticketer() *Ticketer      { return t.ticketer }
+func (t *Ticket) Topic() *Topic            { return t.topic }
+func (t *Ticket) Body() string             { return t.body }
+func (t *Ticket) ExternalID() string       { return t.externalID }
+func (t *Ticket) SetExternalID(id string)  { t.externalID = id }
+func (t *Ticket) Assignee() *User          { return t.assignee }
+func (t *Ticket) SetAssignee(user *User)   { t.assignee = user }
+func (t *Ticket) Status() TicketStatus     { return t.status }
+func (t *Ticket) SetStatus(s TicketStatus) { t.status = s }
 
 // Context returns the properties available in expressions
 //
 //	uuid:text -> the UUID of the ticket
 //	subject:text -> the subject of the ticket
 //	body:text -> the body of the ticket
+//	status:text -> the status of the ticket, one of open or closed
 //
 // @context ticket
 func (t *Ticket) Context(env envs.Environment) map[string]types.XValue {
@@ -60,6 +83,7 @@ func (t *Ticket) Context(env envs.Environment) map[string]types.XValue {
 		"topic":    Context(env, t.topic),
 		"body":     types.NewXText(t.body),
 		"assignee": Context(env, t.assignee),
+		"status":   types.NewXText(string(t.status)),
 	}
 }
 
@@ -74,6 +98,7 @@ type ticketEnvelope struct {
 	Body       string                    `json:"body"`
 	ExternalID string                    `json:"external_id,omitempty"`
 	Assignee   *assets.UserReference     `json:"assignee,omitempty"     validate:"omitempty,dive"`
+	Status     TicketStatus              `json:"status,omitempty"       validate:"omitempty,ticket_status"`
 }
 
 // ReadTicket decodes a contact from the passed in JSON. If the ticketer or assigned user can't
@@ -106,6 +131,12 @@ func ReadTicket(sa SessionAssets, data []byte, missing assets.MissingCallback) (
 		}
 	}
 
+	// tickets written before statuses were tracked are assumed to be open
+	status := e.Status
+	if status == "" {
+		status = TicketStatusOpen
+	}
+
 	return &Ticket{
 		uuid:       e.UUID,
 		ticketer:   ticketer,
@@ -113,6 +144,7 @@ func ReadTicket(sa SessionAssets, data []byte, missing assets.MissingCallback) (
 		body:       e.Body,
 		externalID: e.ExternalID,
 		assignee:   assignee,
+		status:     status,
 	}, nil
 }
 
@@ -140,6 +172,7 @@ func (t *Ticket) MarshalJSON() ([]byte, error) {
 		Body:       t.body,
 		ExternalID: t.externalID,
 		Assignee:   assigneeRef,
+		Status:     t.status,
 	})
 }
 
@@ -156,7 +189,10 @@ func NewTicketList(tickets []*Ticket) *TicketList {
 // returns a clone of this ticket list
 func (l *TicketList) clone() *TicketList {
 	tickets := make([]*Ticket, len(l.tickets))
-	copy(tickets, l.tickets)
+	for i, t := range l.tickets {
+		cloned := *t
+		tickets[i] = &cloned
+	}
 	return &TicketList{tickets: tickets}
 }
 
@@ -175,6 +211,40 @@ func (l *TicketList) Count() int {
 	return len(l.tickets)
 }
 
+// Get returns the ticket with the given UUID or nil if there's no such ticket
+func (l *TicketList) Get(uuid TicketUUID) *Ticket {
+	for _, t := range l.tickets {
+		if t.uuid == uuid {
+			return t
+		}
+	}
+	return nil
+}
+
+// Open returns the tickets in this ticket list which are open
+func (l *TicketList) Open() []*Ticket {
+	open := make([]*Ticket, 0, len(l.tickets))
+	for _, t := range l.tickets {
+		if t.status == TicketStatusOpen {
+			open = append(open, t)
+		}
+	}
+	return open
+}
+
+// OpenCount returns the number of open tickets
+func (l *TicketList) OpenCount() int {
+	return len(l.Open())
+}
+
+// Last returns the most recently added ticket or nil if there are no tickets
+func (l *TicketList) Last() *Ticket {
+	if len(l.tickets) == 0 {
+		return nil
+	}
+	return l.tickets[len(l.tickets)-1]
+}
+
 // ToXValue returns a representation of this object for use in expressions
 func (l TicketList) ToXValue(env envs.Environment) types.XValue {
 	array := make([]types.XValue, len(l.tickets))
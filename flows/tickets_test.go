@@ -6,8 +6,10 @@ import (
 	"github.com/developc3ntro/omni-goflow/assets"
 	"github.com/developc3ntro/omni-goflow/assets/static"
 	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/excellent/types"
 	"github.com/developc3ntro/omni-goflow/flows"
 	"github.com/developc3ntro/omni-goflow/flows/engine"
+	"github.com/nyaruka/gocommon/jsonx"
 	"github.com/nyaruka/gocommon/uuids"
 
 	"github.com/stretchr/testify/assert"
@@ -140,4 +142,39 @@ func TestTickets(t *testing.T) {
 
 	tickets.Add(ticket3)
 	assert.Equal(t, 3, tickets.Count())
+	assert.Equal(t, ticket3, tickets.Last())
+	assert.Equal(t, ticket2, tickets.Get("5a4af021-d2c2-47fc-9abc-abbb8635d8c0"))
+	assert.Nil(t, tickets.Get("b0b0ab3b-9e88-4cc9-a1e1-32bbc7a3b4ab"))
+
+	// tickets are open by default, including tickets read from JSON without a status
+	assert.Equal(t, flows.TicketStatusOpen, ticket1.Status())
+	assert.Equal(t, flows.TicketStatusOpen, ticket3.Status())
+	assert.Equal(t, 3, tickets.OpenCount())
+
+	ticket2.SetStatus(flows.TicketStatusClosed)
+	ticket3.SetAssignee(nil)
+
+	assert.Equal(t, 2, tickets.OpenCount())
+	assert.Equal(t, []*flows.Ticket{ticket1, ticket3}, tickets.Open())
+	assert.Nil(t, ticket3.Assignee())
+
+	// status is included in the context
+	status, _ := flows.Context(env, ticket2).(*types.XObject).Get("status")
+	assert.Equal(t, types.NewXText("closed"), status)
+
+	// and round-trips through JSON
+	marshaled, err := jsonx.Marshal(ticket2)
+	require.NoError(t, err)
+
+	ticket4, err := flows.ReadTicket(sa, marshaled, missing)
+	require.NoError(t, err)
+	assert.Equal(t, flows.TicketStatusClosed, ticket4.Status())
+
+	_, err = flows.ReadTicket(sa, []byte(`{
+		"uuid": "5a4af021-d2c2-47fc-9abc-abbb8635d8c0", 
+		"ticketer": {"uuid": "d605bb96-258d-4097-ad0a-080937db2212", "name": "Support Tickets"},
+		"body": "Where are my shoes?",
+		"status": "pending"
+	}`), missing)
+	assert.EqualError(t, err, "field 'status' is not a valid ticket status")
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/services/airtime"
 	"github.com/developc3ntro/omni-goflow/utils"
 	"github.com/nyaruka/gocommon/httpx"
 	"github.com/nyaruka/gocommon/urns"
@@ -64,31 +65,16 @@ func (s *service) Transfer(session flows.Session, sender urns.URN, recipient urn
 		return transfer, errors.Wrap(err, "product fetch failed")
 	}
 
-	// closest product for each currency we have a desired amount for
-	closestProducts := make(map[string]*Product, len(amounts))
-
-	for currency, desiredAmount := range amounts {
-		for _, product := range products {
-			if product.Destination.Unit == currency {
-				closest := closestProducts[currency]
-				prodAmount := product.Destination.Amount
-
-				if (closest == nil || prodAmount.GreaterThan(closest.Destination.Amount)) && prodAmount.LessThanOrEqual(desiredAmount) {
-					closestProducts[currency] = product
-				}
-			}
-		}
-	}
-	if len(closestProducts) == 0 {
-		return transfer, errors.Errorf("unable to find a suitable product for operator '%s'", operator.Name)
+	candidates := make([]airtime.Product, len(products))
+	for i, p := range products {
+		candidates[i] = airtime.Product{Currency: p.Destination.Unit, Amount: p.Destination.Amount}
 	}
 
-	// it's possible we have more than one supported currency/product.. use any
-	var product *Product
-	for i := range closestProducts {
-		product = closestProducts[i]
-		break
+	selected := airtime.SelectProduct(candidates, amounts)
+	if selected < 0 {
+		return transfer, errors.Errorf("unable to find a suitable product for operator '%s'", operator.Name)
 	}
+	product := products[selected]
 
 	transfer.Currency = product.Destination.Unit
 	transfer.DesiredAmount = amounts[transfer.Currency]
@@ -0,0 +1,121 @@
+package fake
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/services/airtime"
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/jsonx"
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/gocommon/uuids"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Operator is a mobile operator known to the fake service
+//
+//   {
+//     "name": "Claro",
+//     "prefixes": ["+59397"],
+//     "products": [{"currency": "USD", "amount": 1}, {"currency": "USD", "amount": 5}]
+//   }
+type Operator struct {
+	Name     string            `json:"name"     validate:"required"`
+	Prefixes []string          `json:"prefixes" validate:"required,min=1"`
+	Products []airtime.Product `json:"products"`
+	Fail     bool              `json:"fail,omitempty"`
+}
+
+// ReadOperators reads a table of operators from the given JSON
+func ReadOperators(data json.RawMessage) ([]*Operator, error) {
+	var operators []*Operator
+	if err := jsonx.Unmarshal(data, &operators); err != nil {
+		return nil, err
+	}
+	for _, o := range operators {
+		if err := utils.Validate(o); err != nil {
+			return nil, err
+		}
+	}
+	return operators, nil
+}
+
+// Service is a deterministic in-memory airtime service which selects products from a table of operators
+// without making any network requests. Operators are identified by the longest prefix which matches the
+// recipient's number, and transfers to operators marked as failing always fail.
+type Service struct {
+	operators []*Operator
+
+	mutex     sync.Mutex
+	transfers []*flows.AirtimeTransfer
+}
+
+// NewService creates a new fake airtime service with the given operators
+func NewService(operators []*Operator) *Service {
+	return &Service{operators: operators}
+}
+
+// Transfers returns the successful transfers made by this service
+func (s *Service) Transfers() []*flows.AirtimeTransfer {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]*flows.AirtimeTransfer(nil), s.transfers...)
+}
+
+func (s *Service) Transfer(session flows.Session, sender urns.URN, recipient urns.URN, amounts map[string]decimal.Decimal, logHTTP flows.HTTPLogCallback) (*flows.AirtimeTransfer, error) {
+	transfer := &flows.AirtimeTransfer{
+		UUID:          uuids.New(),
+		Sender:        sender,
+		Recipient:     recipient,
+		DesiredAmount: decimal.Zero,
+		ActualAmount:  decimal.Zero,
+	}
+
+	operator := s.lookupOperator(recipient.Path())
+	if operator == nil {
+		return transfer, errors.Errorf("unable to find operator for number %s", recipient.Path())
+	}
+
+	selected := airtime.SelectProduct(operator.Products, amounts)
+	if selected < 0 {
+		return transfer, errors.Errorf("unable to find a suitable product for operator '%s'", operator.Name)
+	}
+	product := operator.Products[selected]
+
+	transfer.Currency = product.Currency
+	transfer.DesiredAmount = amounts[product.Currency]
+
+	if operator.Fail {
+		return transfer, errors.Errorf("transaction to send %s %s on operator '%s' failed", product.Amount, product.Currency, operator.Name)
+	}
+
+	transfer.ActualAmount = product.Amount
+
+	s.mutex.Lock()
+	s.transfers = append(s.transfers, transfer)
+	s.mutex.Unlock()
+
+	return transfer, nil
+}
+
+// finds the operator with the longest prefix matching the given number
+func (s *Service) lookupOperator(number string) *Operator {
+	var match *Operator
+	var matchLen int
+
+	for _, o := range s.operators {
+		for _, prefix := range o.Prefixes {
+			if strings.HasPrefix(number, prefix) && len(prefix) > matchLen {
+				match, matchLen = o, len(prefix)
+			}
+		}
+	}
+	return match
+}
+
+var _ flows.AirtimeService = (*Service)(nil)
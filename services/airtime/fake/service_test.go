@@ -0,0 +1,93 @@
+package fake_test
+
+import (
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/services/airtime/fake"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/gocommon/uuids"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOperators(t *testing.T) {
+	operators, err := fake.ReadOperators([]byte(`[
+		{"name": "Claro", "prefixes": ["+59397"], "products": [{"currency": "USD", "amount": 1}, {"currency": "USD", "amount": 5}]},
+		{"name": "MTN", "prefixes": ["+25078", "+25079"], "products": [{"currency": "RWF", "amount": 500}], "fail": true}
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(operators))
+	assert.Equal(t, "Claro", operators[0].Name)
+	assert.Equal(t, []string{"+59397"}, operators[0].Prefixes)
+	assert.Equal(t, "USD", operators[0].Products[1].Currency)
+	assert.Equal(t, "5", operators[0].Products[1].Amount.String())
+	assert.False(t, operators[0].Fail)
+	assert.True(t, operators[1].Fail)
+
+	_, err = fake.ReadOperators([]byte(`[{"name": "Claro", "prefixes": []}]`))
+	assert.EqualError(t, err, "field 'prefixes' must have a minimum of 1 items")
+
+	_, err = fake.ReadOperators([]byte(`{}`))
+	assert.Error(t, err)
+}
+
+func TestService(t *testing.T) {
+	session, _, err := test.CreateTestSession("", envs.RedactionPolicyNone)
+	require.NoError(t, err)
+
+	defer uuids.SetGenerator(uuids.DefaultGenerator)
+	uuids.SetGenerator(uuids.NewSeededGenerator(12345))
+
+	d := decimal.RequireFromString
+
+	operators, err := fake.ReadOperators([]byte(`[
+		{"name": "Claro", "prefixes": ["+593"], "products": [{"currency": "USD", "amount": 1}, {"currency": "USD", "amount": 5}]},
+		{"name": "Movistar", "prefixes": ["+59398"], "products": [{"currency": "USD", "amount": 2}]},
+		{"name": "MTN", "prefixes": ["+25078"], "products": [{"currency": "RWF", "amount": 500}], "fail": true}
+	]`))
+	require.NoError(t, err)
+
+	svc := fake.NewService(operators)
+	httpLogger := &flows.HTTPLogger{}
+
+	// longest matching prefix is used to identify the operator
+	transfer, err := svc.Transfer(session, urns.NilURN, urns.URN("tel:+593979123456"), map[string]decimal.Decimal{"USD": d("3"), "RWF": d("1000")}, httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, &flows.AirtimeTransfer{
+		UUID:          uuids.UUID("1ae96956-4b34-433e-8d1a-f05fe6923d6d"),
+		Recipient:     urns.URN("tel:+593979123456"),
+		Currency:      "USD",
+		DesiredAmount: d("3"),
+		ActualAmount:  d("1"),
+	}, transfer)
+
+	transfer, err = svc.Transfer(session, urns.NilURN, urns.URN("tel:+593989123456"), map[string]decimal.Decimal{"USD": d("3")}, httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", transfer.Currency)
+	assert.Equal(t, d("2"), transfer.ActualAmount)
+
+	// no operator for number
+	transfer, err = svc.Transfer(session, urns.NilURN, urns.URN("tel:+12065551212"), map[string]decimal.Decimal{"USD": d("3")}, httpLogger.Log)
+	assert.EqualError(t, err, "unable to find operator for number +12065551212")
+	assert.Equal(t, decimal.Zero, transfer.ActualAmount)
+
+	// no suitable product
+	transfer, err = svc.Transfer(session, urns.NilURN, urns.URN("tel:+593979123456"), map[string]decimal.Decimal{"USD": d("0.5")}, httpLogger.Log)
+	assert.EqualError(t, err, "unable to find a suitable product for operator 'Claro'")
+	assert.Equal(t, decimal.Zero, transfer.ActualAmount)
+
+	// failing operator
+	transfer, err = svc.Transfer(session, urns.NilURN, urns.URN("tel:+250781234567"), map[string]decimal.Decimal{"RWF": d("600")}, httpLogger.Log)
+	assert.EqualError(t, err, "transaction to send 500 RWF on operator 'MTN' failed")
+	assert.Equal(t, "RWF", transfer.Currency)
+	assert.Equal(t, d("600"), transfer.DesiredAmount)
+	assert.Equal(t, decimal.Zero, transfer.ActualAmount)
+
+	assert.Equal(t, 2, len(svc.Transfers()))
+	assert.Equal(t, 0, len(httpLogger.Logs))
+}
@@ -0,0 +1,44 @@
+package airtime
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Product is a fixed value airtime product offered by an operator
+type Product struct {
+	Currency string          `json:"currency"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+// SelectProduct selects the product to send given the desired amounts in different currencies, returning
+// its index or -1 if no product is suitable. For each currency we look for the largest product which doesn't
+// exceed the desired amount. If products are found in more than one currency, we use the first currency in
+// alphabetical order so that selection is always deterministic.
+func SelectProduct(products []Product, amounts map[string]decimal.Decimal) int {
+	closest := make(map[string]int, len(amounts))
+
+	for currency, desiredAmount := range amounts {
+		for i, product := range products {
+			if product.Currency == currency && product.Amount.LessThanOrEqual(desiredAmount) {
+				c, found := closest[currency]
+				if !found || product.Amount.GreaterThan(products[c].Amount) {
+					closest[currency] = i
+				}
+			}
+		}
+	}
+
+	if len(closest) == 0 {
+		return -1
+	}
+
+	currencies := make([]string, 0, len(closest))
+	for currency := range closest {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	return closest[currencies[0]]
+}
@@ -0,0 +1,43 @@
+package airtime_test
+
+import (
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/services/airtime"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectProduct(t *testing.T) {
+	d := decimal.RequireFromString
+
+	products := []airtime.Product{
+		{Currency: "USD", Amount: d("1")},
+		{Currency: "USD", Amount: d("5")},
+		{Currency: "USD", Amount: d("2.5")},
+		{Currency: "RWF", Amount: d("500")},
+		{Currency: "RWF", Amount: d("1000")},
+		{Currency: "EUR", Amount: d("5")},
+	}
+
+	tcs := []struct {
+		amounts  map[string]decimal.Decimal
+		expected int
+	}{
+		{map[string]decimal.Decimal{}, -1},
+		{map[string]decimal.Decimal{"USD": d("0.5")}, -1},
+		{map[string]decimal.Decimal{"KES": d("100")}, -1},
+		{map[string]decimal.Decimal{"USD": d("1")}, 0},
+		{map[string]decimal.Decimal{"USD": d("3")}, 2},
+		{map[string]decimal.Decimal{"USD": d("100")}, 1},
+		{map[string]decimal.Decimal{"RWF": d("999")}, 3},
+		{map[string]decimal.Decimal{"RWF": d("1000"), "KES": d("100")}, 4},
+		{map[string]decimal.Decimal{"RWF": d("1000"), "USD": d("3")}, 4},
+		{map[string]decimal.Decimal{"RWF": d("100"), "USD": d("3"), "EUR": d("10")}, 5},
+	}
+
+	for _, tc := range tcs {
+		assert.Equal(t, tc.expected, airtime.SelectProduct(products, tc.amounts), "product mismatch for amounts %v", tc.amounts)
+	}
+}
@@ -0,0 +1,207 @@
+package reloadly
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/jsonx"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	authURL       = "https://auth.reloadly.com/oauth/token"
+	apiURL        = "https://topups.reloadly.com/"
+	sandboxAPIURL = "https://topups-sandbox.reloadly.com/"
+
+	// see https://developers.reloadly.com/airtime/
+	DenominationFixed = "FIXED"
+	DenominationRange = "RANGE"
+
+	StatusSuccessful = "SUCCESSFUL"
+	StatusPending    = "PENDING"
+	StatusRefunded   = "REFUNDED"
+	StatusFailed     = "FAILED"
+)
+
+// Client is a Reloadly airtime client, see https://developers.reloadly.com/ for API docs
+type Client struct {
+	httpClient   *http.Client
+	httpRetries  *httpx.RetryConfig
+	clientID     string
+	clientSecret string
+	baseURL      string
+	accessToken  string
+}
+
+// NewClient creates a new Reloadly client which uses the sandbox API if sandbox is true
+func NewClient(httpClient *http.Client, httpRetries *httpx.RetryConfig, clientID, clientSecret string, sandbox bool) *Client {
+	baseURL := apiURL
+	if sandbox {
+		baseURL = sandboxAPIURL
+	}
+
+	return &Client{httpClient: httpClient, httpRetries: httpRetries, clientID: clientID, clientSecret: clientSecret, baseURL: baseURL}
+}
+
+// error response contains the error message when a request fails
+type errorResponse struct {
+	Message   string `json:"message"`
+	ErrorCode string `json:"errorCode"`
+}
+
+func (e *errorResponse) Error() string {
+	if e.Message == "" {
+		return "unknown error"
+	}
+	return e.Message
+}
+
+// Token is an access token
+type Token struct {
+	AccessToken string `json:"access_token"`
+	Scope       string `json:"scope"`
+	ExpiresIn   int    `json:"expires_in"`
+	TokenType   string `json:"token_type"`
+}
+
+// Authenticate fetches an access token which is used for all other requests
+func (c *Client) Authenticate() (*Token, *httpx.Trace, error) {
+	var response *Token
+
+	payload := &struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+		GrantType    string `json:"grant_type"`
+		Audience     string `json:"audience"`
+	}{
+		ClientID:     c.clientID,
+		ClientSecret: c.clientSecret,
+		GrantType:    "client_credentials",
+		Audience:     c.baseURL[:len(c.baseURL)-1],
+	}
+
+	trace, err := c.request("POST", authURL, payload, &response)
+	if err != nil {
+		return nil, trace, err
+	}
+
+	c.accessToken = response.AccessToken
+
+	return response, trace, nil
+}
+
+// AccessToken returns the current access token, if the client has authenticated
+func (c *Client) AccessToken() string { return c.accessToken }
+
+// Operator is a mobile operator
+type Operator struct {
+	ID                      int               `json:"operatorId"`
+	Name                    string            `json:"name"`
+	DenominationType        string            `json:"denominationType"`
+	DestinationCurrencyCode string            `json:"destinationCurrencyCode"`
+	LocalMinAmount          decimal.Decimal   `json:"localMinAmount"`
+	LocalMaxAmount          decimal.Decimal   `json:"localMaxAmount"`
+	LocalFixedAmounts       []decimal.Decimal `json:"localFixedAmounts"`
+	Country                 struct {
+		ISOName string `json:"isoName"`
+		Name    string `json:"name"`
+	} `json:"country"`
+}
+
+// DetectOperator see https://developers.reloadly.com/airtime/operators#auto-detect-an-operator
+func (c *Client) DetectOperator(phone, countryCode string) (*Operator, *httpx.Trace, error) {
+	var response *Operator
+
+	trace, err := c.request("GET", c.baseURL+fmt.Sprintf("operators/auto-detect/phone/%s/countries/%s", phone, countryCode), nil, &response)
+	if err != nil {
+		return nil, trace, err
+	}
+
+	return response, trace, nil
+}
+
+// Transaction is a completed top up
+type Transaction struct {
+	TransactionID               int64           `json:"transactionId"`
+	Status                      string          `json:"status"`
+	OperatorTransactionID       string          `json:"operatorTransactionId"`
+	CustomIdentifier            string          `json:"customIdentifier"`
+	RecipientPhone              string          `json:"recipientPhone"`
+	DeliveredAmount             decimal.Decimal `json:"deliveredAmount"`
+	DeliveredAmountCurrencyCode string          `json:"deliveredAmountCurrencyCode"`
+}
+
+// Topup sends the given amount in the operator's local currency, see https://developers.reloadly.com/airtime/top-ups
+func (c *Client) Topup(customIdentifier string, operatorID int, amount decimal.Decimal, phone, countryCode string) (*Transaction, *httpx.Trace, error) {
+	var response *Transaction
+
+	type recipientPhone struct {
+		CountryCode string `json:"countryCode"`
+		Number      string `json:"number"`
+	}
+
+	payload := &struct {
+		OperatorID       int             `json:"operatorId"`
+		Amount           decimal.Decimal `json:"amount"`
+		UseLocalAmount   bool            `json:"useLocalAmount"`
+		CustomIdentifier string          `json:"customIdentifier"`
+		RecipientPhone   recipientPhone  `json:"recipientPhone"`
+	}{
+		OperatorID:       operatorID,
+		Amount:           amount,
+		UseLocalAmount:   true,
+		CustomIdentifier: customIdentifier,
+		RecipientPhone:   recipientPhone{CountryCode: countryCode, Number: phone},
+	}
+
+	trace, err := c.request("POST", c.baseURL+"topups", payload, &response)
+	if err != nil {
+		return nil, trace, err
+	}
+
+	return response, trace, nil
+}
+
+func (c *Client) request(method, url string, payload interface{}, response interface{}) (*httpx.Trace, error) {
+	headers := map[string]string{}
+	var body io.Reader
+
+	if payload != nil {
+		data, err := jsonx.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+		headers["Content-Type"] = "application/json"
+	}
+
+	if c.accessToken != "" {
+		headers["Authorization"] = "Bearer " + c.accessToken
+		headers["Accept"] = "application/com.reloadly.topups-v1+json"
+	}
+
+	req, err := httpx.NewRequest(method, url, body, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	trace, err := httpx.DoTrace(c.httpClient, req, c.httpRetries, nil, -1)
+	if err != nil {
+		return trace, err
+	}
+
+	if trace.Response.StatusCode >= 400 {
+		response := &errorResponse{}
+		jsonx.Unmarshal(trace.ResponseBody, response)
+		return trace, response
+	}
+
+	if response != nil {
+		return trace, jsonx.Unmarshal(trace.ResponseBody, response)
+	}
+	return trace, nil
+}
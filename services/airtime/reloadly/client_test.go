@@ -0,0 +1,118 @@
+package reloadly_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/services/airtime/reloadly"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/httpx"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+const tokenResponse = `{
+	"access_token": "eyJraWQiOiI1N2JjZjNhNy1kZTBkIn0",
+	"scope": "send-topups read-operators",
+	"expires_in": 5184000,
+	"token_type": "Bearer"
+}`
+
+const fixedOperatorResponse = `{
+	"operatorId": 129,
+	"name": "Claro Ecuador",
+	"denominationType": "FIXED",
+	"destinationCurrencyCode": "USD",
+	"localMinAmount": null,
+	"localMaxAmount": null,
+	"localFixedAmounts": [1.00, 3.00, 5.00, 10.00],
+	"country": {"isoName": "EC", "name": "Ecuador"}
+}`
+
+const rangeOperatorResponse = `{
+	"operatorId": 341,
+	"name": "MTN Rwanda",
+	"denominationType": "RANGE",
+	"destinationCurrencyCode": "RWF",
+	"localMinAmount": 100.00,
+	"localMaxAmount": 5000.00,
+	"localFixedAmounts": [],
+	"country": {"isoName": "RW", "name": "Rwanda"}
+}`
+
+const topupSuccessfulResponse = `{
+	"transactionId": 4602,
+	"status": "SUCCESSFUL",
+	"operatorTransactionId": "7297929551:7297929551",
+	"customIdentifier": "1ae96956-4b34-433e-8d1a-f05fe6923d6d",
+	"recipientPhone": "593979123456",
+	"deliveredAmount": 3,
+	"deliveredAmountCurrencyCode": "USD"
+}`
+
+const topupFailedResponse = `{
+	"transactionId": 4603,
+	"status": "FAILED",
+	"customIdentifier": "1ae96956-4b34-433e-8d1a-f05fe6923d6d",
+	"recipientPhone": "593979123456",
+	"deliveredAmount": 0,
+	"deliveredAmountCurrencyCode": "USD"
+}`
+
+func errorResp(code, message string) string {
+	return `{"timeStamp": "2021-05-12 14:28:03", "message": "` + message + `", "path": "/topups", "errorCode": "` + code + `", "infoLink": null, "details": []}`
+}
+
+func TestClient(t *testing.T) {
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"https://auth.reloadly.com/oauth/token": {
+			httpx.NewMockResponse(401, nil, errorResp("INVALID_CREDENTIALS", "Access Denied")),
+			httpx.NewMockResponse(200, nil, tokenResponse),
+		},
+		"https://topups-sandbox.reloadly.com/operators/auto-detect/phone/+593979123456/countries/EC": {
+			httpx.NewMockResponse(404, nil, errorResp("COULD_NOT_AUTO_DETECT_OPERATOR", "Could not retrieve operator")),
+			httpx.NewMockResponse(200, nil, fixedOperatorResponse),
+		},
+		"https://topups-sandbox.reloadly.com/topups": {
+			httpx.NewMockResponse(400, nil, errorResp("INSUFFICIENT_BALANCE", "Insufficient funds in the wallet")),
+			httpx.NewMockResponse(200, nil, topupSuccessfulResponse),
+		},
+	}))
+
+	client := reloadly.NewClient(http.DefaultClient, nil, "ID123", "sesame", true)
+
+	_, _, err := client.Authenticate()
+	assert.EqualError(t, err, "Access Denied")
+	assert.Equal(t, "", client.AccessToken())
+
+	token, trace, err := client.Authenticate()
+	assert.NoError(t, err)
+	test.AssertSnapshot(t, "authenticate", string(trace.RequestTrace))
+	assert.Equal(t, "eyJraWQiOiI1N2JjZjNhNy1kZTBkIn0", token.AccessToken)
+	assert.Equal(t, "eyJraWQiOiI1N2JjZjNhNy1kZTBkIn0", client.AccessToken())
+
+	_, _, err = client.DetectOperator("+593979123456", "EC")
+	assert.EqualError(t, err, "Could not retrieve operator")
+
+	operator, trace, err := client.DetectOperator("+593979123456", "EC")
+	assert.NoError(t, err)
+	test.AssertSnapshot(t, "detect_operator", string(trace.RequestTrace))
+	assert.Equal(t, 129, operator.ID)
+	assert.Equal(t, "Claro Ecuador", operator.Name)
+	assert.Equal(t, reloadly.DenominationFixed, operator.DenominationType)
+	assert.Equal(t, 4, len(operator.LocalFixedAmounts))
+	assert.Equal(t, "EC", operator.Country.ISOName)
+
+	_, _, err = client.Topup("1ae96956-4b34-433e-8d1a-f05fe6923d6d", 129, decimal.RequireFromString("3"), "+593979123456", "EC")
+	assert.EqualError(t, err, "Insufficient funds in the wallet")
+
+	tx, trace, err := client.Topup("1ae96956-4b34-433e-8d1a-f05fe6923d6d", 129, decimal.RequireFromString("3"), "+593979123456", "EC")
+	assert.NoError(t, err)
+	test.AssertSnapshot(t, "topup", string(trace.RequestTrace))
+	assert.Equal(t, int64(4602), tx.TransactionID)
+	assert.Equal(t, reloadly.StatusSuccessful, tx.Status)
+	assert.Equal(t, "3", tx.DeliveredAmount.String())
+}
@@ -0,0 +1,123 @@
+package reloadly
+
+import (
+	"net/http"
+
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/services/airtime"
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/gocommon/uuids"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+type service struct {
+	httpClient   *http.Client
+	httpRetries  *httpx.RetryConfig
+	clientID     string
+	clientSecret string
+	sandbox      bool
+}
+
+// NewService creates a new Reloadly airtime service
+func NewService(httpClient *http.Client, httpRetries *httpx.RetryConfig, clientID, clientSecret string, sandbox bool) flows.AirtimeService {
+	return &service{
+		httpClient:   httpClient,
+		httpRetries:  httpRetries,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		sandbox:      sandbox,
+	}
+}
+
+func (s *service) Transfer(session flows.Session, sender urns.URN, recipient urns.URN, amounts map[string]decimal.Decimal, logHTTP flows.HTTPLogCallback) (*flows.AirtimeTransfer, error) {
+	transfer := &flows.AirtimeTransfer{
+		UUID:          uuids.New(),
+		Sender:        sender,
+		Recipient:     recipient,
+		DesiredAmount: decimal.Zero,
+		ActualAmount:  decimal.Zero,
+	}
+
+	client := NewClient(s.httpClient, s.httpRetries, s.clientID, s.clientSecret, s.sandbox)
+	redactor := utils.NewRedactor(flows.RedactionMask, s.clientSecret)
+
+	token, trace, err := client.Authenticate()
+	if token != nil {
+		redactor = utils.NewRedactor(flows.RedactionMask, s.clientSecret, token.AccessToken)
+	}
+	if trace != nil {
+		logHTTP(flows.NewHTTPLog(trace, flows.HTTPStatusFromCode, redactor))
+	}
+	if err != nil {
+		return transfer, errors.Wrap(err, "authentication failed")
+	}
+
+	number := recipient.Path()
+	country := envs.DeriveCountryFromTel(number)
+	if country == envs.NilCountry {
+		return transfer, errors.Errorf("unable to determine country for number %s", number)
+	}
+
+	operator, trace, err := client.DetectOperator(number, string(country))
+	if trace != nil {
+		logHTTP(flows.NewHTTPLog(trace, flows.HTTPStatusFromCode, redactor))
+	}
+	if err != nil {
+		return transfer, errors.Wrap(err, "operator detection failed")
+	}
+
+	products := operatorProducts(operator, amounts)
+
+	selected := airtime.SelectProduct(products, amounts)
+	if selected < 0 {
+		return transfer, errors.Errorf("unable to find a suitable product for operator '%s'", operator.Name)
+	}
+	product := products[selected]
+
+	transfer.Currency = product.Currency
+	transfer.DesiredAmount = amounts[transfer.Currency]
+
+	tx, trace, err := client.Topup(string(transfer.UUID), operator.ID, product.Amount, number, string(country))
+	if trace != nil {
+		logHTTP(flows.NewHTTPLog(trace, flows.HTTPStatusFromCode, redactor))
+	}
+	if err != nil {
+		return transfer, errors.Wrap(err, "top up failed")
+	}
+
+	if tx.Status != StatusSuccessful && tx.Status != StatusPending {
+		return transfer, errors.Errorf("top up of %s %s on operator %d ended with status %s", product.Amount, product.Currency, operator.ID, tx.Status)
+	}
+
+	transfer.ActualAmount = product.Amount
+
+	return transfer, nil
+}
+
+// gets the products offered by an operator in its local currency. Operators with a range of amounts are
+// treated as offering a product for the desired amount, limited to their maximum amount.
+func operatorProducts(operator *Operator, amounts map[string]decimal.Decimal) []airtime.Product {
+	currency := operator.DestinationCurrencyCode
+
+	if operator.DenominationType == DenominationRange {
+		desired, hasDesired := amounts[currency]
+		if !hasDesired || desired.LessThan(operator.LocalMinAmount) {
+			return nil
+		}
+		if !operator.LocalMaxAmount.IsZero() && desired.GreaterThan(operator.LocalMaxAmount) {
+			desired = operator.LocalMaxAmount
+		}
+		return []airtime.Product{{Currency: currency, Amount: desired}}
+	}
+
+	products := make([]airtime.Product, len(operator.LocalFixedAmounts))
+	for i, amount := range operator.LocalFixedAmounts {
+		products[i] = airtime.Product{Currency: currency, Amount: amount}
+	}
+	return products
+}
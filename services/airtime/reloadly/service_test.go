@@ -0,0 +1,124 @@
+package reloadly_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/services/airtime/reloadly"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/urns"
+	"github.com/nyaruka/gocommon/uuids"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceWithSuccessfulTransfer(t *testing.T) {
+	session, _, err := test.CreateTestSession("", envs.RedactionPolicyNone)
+	require.NoError(t, err)
+
+	defer uuids.SetGenerator(uuids.DefaultGenerator)
+	defer dates.SetNowSource(dates.DefaultNowSource)
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	uuids.SetGenerator(uuids.NewSeededGenerator(12345))
+	dates.SetNowSource(dates.NewSequentialNowSource(time.Date(2019, 10, 7, 15, 21, 30, 123456789, time.UTC)))
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"https://auth.reloadly.com/oauth/token": {
+			httpx.NewMockResponse(200, nil, tokenResponse),
+		},
+		"https://topups.reloadly.com/operators/auto-detect/phone/+593979123456/countries/EC": {
+			httpx.NewMockResponse(200, nil, fixedOperatorResponse),
+		},
+		"https://topups.reloadly.com/topups": {
+			httpx.NewMockResponse(200, nil, topupSuccessfulResponse),
+		},
+	}))
+
+	svc := reloadly.NewService(http.DefaultClient, nil, "ID123", "sesame", false)
+
+	httpLogger := &flows.HTTPLogger{}
+
+	transfer, err := svc.Transfer(
+		session,
+		urns.URN("tel:+593979000000"),
+		urns.URN("tel:+593979123456"),
+		map[string]decimal.Decimal{"RWF": decimal.RequireFromString("1000"), "USD": decimal.RequireFromString("3.5")},
+		httpLogger.Log,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, &flows.AirtimeTransfer{
+		UUID:          uuids.UUID("1ae96956-4b34-433e-8d1a-f05fe6923d6d"),
+		Sender:        urns.URN("tel:+593979000000"),
+		Recipient:     urns.URN("tel:+593979123456"),
+		Currency:      "USD",
+		DesiredAmount: decimal.RequireFromString("3.5"),
+		ActualAmount:  decimal.RequireFromString("3.00"),
+	}, transfer)
+
+	assert.Equal(t, 3, len(httpLogger.Logs))
+
+	// check that the client secret and access token are redacted everywhere
+	for _, log := range httpLogger.Logs {
+		assert.NotContains(t, log.Request, "sesame")
+		assert.NotContains(t, log.Request, "eyJraWQiOiI1N2JjZjNhNy1kZTBkIn0")
+		assert.NotContains(t, log.Response, "eyJraWQiOiI1N2JjZjNhNy1kZTBkIn0")
+	}
+	assert.Contains(t, httpLogger.Logs[2].Request, `"amount":3,`)
+}
+
+func TestServiceWithRangeOperator(t *testing.T) {
+	session, _, err := test.CreateTestSession("", envs.RedactionPolicyNone)
+	require.NoError(t, err)
+
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"https://auth.reloadly.com/oauth/token": {
+			httpx.NewMockResponse(200, nil, tokenResponse),
+			httpx.NewMockResponse(200, nil, tokenResponse),
+			httpx.NewMockResponse(200, nil, tokenResponse),
+			httpx.NewMockResponse(200, nil, tokenResponse),
+		},
+		"https://topups.reloadly.com/operators/auto-detect/phone/+250781234567/countries/RW": {
+			httpx.NewMockResponse(200, nil, rangeOperatorResponse),
+			httpx.NewMockResponse(200, nil, rangeOperatorResponse),
+			httpx.NewMockResponse(200, nil, rangeOperatorResponse),
+		},
+		"https://topups.reloadly.com/topups": {
+			httpx.NewMockResponse(200, nil, topupSuccessfulResponse),
+			httpx.NewMockResponse(200, nil, topupFailedResponse),
+		},
+	}))
+
+	svc := reloadly.NewService(http.DefaultClient, nil, "ID123", "sesame", false)
+	httpLogger := &flows.HTTPLogger{}
+
+	// desired amount is above the operator's maximum
+	transfer, err := svc.Transfer(session, urns.NilURN, urns.URN("tel:+250781234567"), map[string]decimal.Decimal{"RWF": decimal.RequireFromString("8000")}, httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, "RWF", transfer.Currency)
+	assert.Equal(t, decimal.RequireFromString("8000"), transfer.DesiredAmount)
+	assert.Equal(t, decimal.RequireFromString("5000.00"), transfer.ActualAmount)
+
+	// transaction fails
+	transfer, err = svc.Transfer(session, urns.NilURN, urns.URN("tel:+250781234567"), map[string]decimal.Decimal{"RWF": decimal.RequireFromString("1000")}, httpLogger.Log)
+	assert.EqualError(t, err, "top up of 1000 RWF on operator 341 ended with status FAILED")
+	assert.Equal(t, decimal.Zero, transfer.ActualAmount)
+
+	// desired amount is below the operator's minimum
+	transfer, err = svc.Transfer(session, urns.NilURN, urns.URN("tel:+250781234567"), map[string]decimal.Decimal{"RWF": decimal.RequireFromString("50")}, httpLogger.Log)
+	assert.EqualError(t, err, "unable to find a suitable product for operator 'MTN Rwanda'")
+	assert.Equal(t, decimal.Zero, transfer.ActualAmount)
+
+	// number with no country
+	transfer, err = svc.Transfer(session, urns.NilURN, urns.URN("tel:12345"), map[string]decimal.Decimal{"RWF": decimal.RequireFromString("50")}, httpLogger.Log)
+	assert.EqualError(t, err, "unable to determine country for number 12345")
+	assert.Equal(t, decimal.Zero, transfer.ActualAmount)
+}
@@ -0,0 +1,105 @@
+package openai
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/jsonx"
+
+	"github.com/pkg/errors"
+)
+
+// Message is a message in a chat completion
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ResponseFormat constrains the format of a chat completion
+type ResponseFormat struct {
+	Type string `json:"type"`
+}
+
+// ChatRequest is a request to create a chat completion
+type ChatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []*Message      `json:"messages"`
+	Temperature    float64         `json:"temperature"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// Choice is a possible completion
+type Choice struct {
+	Index        int      `json:"index"`
+	Message      *Message `json:"message"       validate:"required"`
+	FinishReason string   `json:"finish_reason"`
+}
+
+// ChatResponse is the response from a /chat/completions request
+type ChatResponse struct {
+	ID      string    `json:"id"`
+	Model   string    `json:"model"`
+	Choices []*Choice `json:"choices" validate:"required,min=1,dive"`
+}
+
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+// Client is a basic client for any API which is compatible with the OpenAI chat completions API
+type Client struct {
+	httpClient  *http.Client
+	httpRetries *httpx.RetryConfig
+	baseURL     string
+	headers     map[string]string
+}
+
+// NewClient creates a new client for the API at the given base URL, e.g. https://api.openai.com/v1
+func NewClient(httpClient *http.Client, httpRetries *httpx.RetryConfig, baseURL, apiKey string) *Client {
+	return &Client{
+		httpClient:  httpClient,
+		httpRetries: httpRetries,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		headers: map[string]string{
+			"Authorization": fmt.Sprintf("Bearer %s", apiKey),
+			"Content-Type":  "application/json",
+		},
+	}
+}
+
+// ChatCompletion creates a completion for the given chat
+func (c *Client) ChatCompletion(chat *ChatRequest) (*ChatResponse, *httpx.Trace, error) {
+	endpoint := fmt.Sprintf("%s/chat/completions", c.baseURL)
+
+	request, err := httpx.NewRequest("POST", endpoint, bytes.NewReader(jsonx.MustMarshal(chat)), c.headers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trace, err := httpx.DoTrace(c.httpClient, request, c.httpRetries, nil, -1)
+	if err != nil {
+		return nil, trace, err
+	}
+
+	if trace.Response != nil && trace.Response.StatusCode == 200 {
+		response := &ChatResponse{}
+		if err := utils.UnmarshalAndValidate(trace.ResponseBody, response); err != nil {
+			return nil, trace, err
+		}
+		return response, trace, nil
+	}
+
+	errResponse := &errorResponse{}
+	if jsonx.Unmarshal(trace.ResponseBody, errResponse) == nil && errResponse.Error.Message != "" {
+		return nil, trace, errors.Errorf("chat completion request failed: %s", errResponse.Error.Message)
+	}
+
+	return nil, trace, errors.New("chat completion request failed")
+}
@@ -0,0 +1,65 @@
+package openai_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/services/classification/openai"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/httpx"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatCompletion(t *testing.T) {
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"https://llm.example.com/v1/chat/completions": {
+			httpx.NewMockResponse(200, nil, `xx`),              // non-JSON response
+			httpx.NewMockResponse(200, nil, `{"choices": []}`), // invalid JSON response
+			httpx.NewMockResponse(401, nil, `{"error": {"message": "Incorrect API key provided", "type": "invalid_request_error"}}`),
+			httpx.NewMockResponse(500, nil, `Internal Server Error`),
+			httpx.NewMockResponse(200, nil, `{
+				"id": "chatcmpl-123",
+				"model": "gpt-4o-mini",
+				"choices": [
+					{"index": 0, "message": {"role": "assistant", "content": "{\"intents\": []}"}, "finish_reason": "stop"}
+				]
+			}`),
+		},
+	}))
+
+	client := openai.NewClient(http.DefaultClient, nil, "https://llm.example.com/v1/", "sk-3246231")
+	chat := &openai.ChatRequest{
+		Model:    "gpt-4o-mini",
+		Messages: []*openai.Message{{Role: "user", Content: "Hello"}},
+	}
+
+	response, trace, err := client.ChatCompletion(chat)
+	assert.EqualError(t, err, `invalid character 'x' looking for beginning of value`)
+	test.AssertSnapshot(t, "chat_completion_request", string(trace.RequestTrace))
+	assert.Equal(t, "xx", string(trace.ResponseBody))
+	assert.Nil(t, response)
+
+	response, trace, err = client.ChatCompletion(chat)
+	assert.EqualError(t, err, `field 'choices' must have a minimum of 1 items`)
+	assert.NotNil(t, trace)
+	assert.Nil(t, response)
+
+	response, trace, err = client.ChatCompletion(chat)
+	assert.EqualError(t, err, `chat completion request failed: Incorrect API key provided`)
+	assert.NotNil(t, trace)
+	assert.Nil(t, response)
+
+	response, trace, err = client.ChatCompletion(chat)
+	assert.EqualError(t, err, `chat completion request failed`)
+	assert.NotNil(t, trace)
+	assert.Nil(t, response)
+
+	response, trace, err = client.ChatCompletion(chat)
+	assert.NoError(t, err)
+	assert.NotNil(t, trace)
+	assert.Equal(t, "chatcmpl-123", response.ID)
+	assert.Equal(t, &openai.Message{Role: "assistant", Content: `{"intents": []}`}, response.Choices[0].Message)
+}
@@ -0,0 +1,100 @@
+package openai
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/jsonx"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+const systemPrompt = `You are an intent classifier. Classify the user's message into zero or more of the following intents: %s.
+
+Respond only with a JSON object of the form:
+{"intents": [{"name": "<intent>", "confidence": <0 to 1>}], "entities": {"<entity>": [{"value": "<value>", "confidence": <0 to 1>}]}}`
+
+// the JSON content we ask the model to respond with
+type classification struct {
+	Intents []struct {
+		Name       string          `json:"name"`
+		Confidence decimal.Decimal `json:"confidence"`
+	} `json:"intents"`
+	Entities map[string][]struct {
+		Value      string          `json:"value"`
+		Confidence decimal.Decimal `json:"confidence"`
+	} `json:"entities"`
+}
+
+// a classification service implementation which prompts a model over an OpenAI compatible API
+type service struct {
+	client     *Client
+	classifier *flows.Classifier
+	model      string
+	redactor   utils.Redactor
+}
+
+// NewService creates a new classification service
+func NewService(httpClient *http.Client, httpRetries *httpx.RetryConfig, classifier *flows.Classifier, baseURL, apiKey, model string) flows.ClassificationService {
+	return &service{
+		client:     NewClient(httpClient, httpRetries, baseURL, apiKey),
+		classifier: classifier,
+		model:      model,
+		redactor:   utils.NewRedactor(flows.RedactionMask, apiKey),
+	}
+}
+
+func (s *service) Classify(session flows.Session, input string, logHTTP flows.HTTPLogCallback) (*flows.Classification, error) {
+	response, trace, err := s.client.ChatCompletion(&ChatRequest{
+		Model: s.model,
+		Messages: []*Message{
+			{Role: "system", Content: fmt.Sprintf(systemPrompt, strings.Join(s.classifier.Intents(), ", "))},
+			{Role: "user", Content: input},
+		},
+		ResponseFormat: &ResponseFormat{Type: "json_object"},
+	})
+	if trace != nil {
+		logHTTP(flows.NewHTTPLog(trace, flows.HTTPStatusFromCode, s.redactor))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &classification{}
+	if err := jsonx.Unmarshal([]byte(response.Choices[0].Message.Content), parsed); err != nil {
+		return nil, errors.Wrap(err, "unable to parse classification from completion")
+	}
+
+	// models can invent intents so only include those which the classifier knows about
+	known := utils.StringSet(s.classifier.Intents())
+
+	result := &flows.Classification{
+		Intents:  make([]flows.ExtractedIntent, 0, len(parsed.Intents)),
+		Entities: make(map[string][]flows.ExtractedEntity, len(parsed.Entities)),
+	}
+
+	for _, intent := range parsed.Intents {
+		if known[intent.Name] {
+			result.Intents = append(result.Intents, flows.ExtractedIntent{Name: intent.Name, Confidence: intent.Confidence})
+		}
+	}
+	sort.SliceStable(result.Intents, func(i, j int) bool { return result.Intents[i].Confidence.GreaterThan(result.Intents[j].Confidence) })
+
+	for name, matches := range parsed.Entities {
+		entities := make([]flows.ExtractedEntity, 0, len(matches))
+		for _, match := range matches {
+			entities = append(entities, flows.ExtractedEntity{Value: match.Value, Confidence: match.Confidence})
+		}
+		result.Entities[name] = entities
+	}
+
+	return result, nil
+}
+
+var _ flows.ClassificationService = (*service)(nil)
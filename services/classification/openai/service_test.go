@@ -0,0 +1,76 @@
+package openai_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/services/classification/openai"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/uuids"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService(t *testing.T) {
+	session, _ := test.NewSessionBuilder().MustBuild()
+
+	defer uuids.SetGenerator(uuids.DefaultGenerator)
+	defer dates.SetNowSource(dates.DefaultNowSource)
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	uuids.SetGenerator(uuids.NewSeededGenerator(12345))
+	dates.SetNowSource(dates.NewSequentialNowSource(time.Date(2019, 10, 7, 15, 21, 30, 123456789, time.UTC)))
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"https://llm.example.com/v1/chat/completions": {
+			httpx.NewMockResponse(200, nil, `{
+				"id": "chatcmpl-123",
+				"choices": [
+					{"index": 0, "message": {"role": "assistant", "content": "{\"intents\": [{\"name\": \"book_hotel\", \"confidence\": 0.1}, {\"name\": \"book_flight\", \"confidence\": 0.92}, {\"name\": \"book_car\", \"confidence\": 0.5}], \"entities\": {\"city\": [{\"value\": \"Quito\", \"confidence\": 0.95}]}}"}}
+				]
+			}`),
+			httpx.NewMockResponse(200, nil, `{
+				"id": "chatcmpl-124",
+				"choices": [
+					{"index": 0, "message": {"role": "assistant", "content": "I think you want to book a flight"}}
+				]
+			}`),
+		},
+	}))
+
+	svc := openai.NewService(
+		http.DefaultClient,
+		nil,
+		test.NewClassifier("Booking", "openai", []string{"book_flight", "book_hotel"}),
+		"https://llm.example.com/v1",
+		"sk-23532624376",
+		"gpt-4o-mini",
+	)
+
+	httpLogger := &flows.HTTPLogger{}
+
+	classification, err := svc.Classify(session, "book flight to Quito", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, []flows.ExtractedIntent{
+		{Name: "book_flight", Confidence: decimal.RequireFromString(`0.92`)},
+		{Name: "book_hotel", Confidence: decimal.RequireFromString(`0.1`)},
+	}, classification.Intents)
+	assert.Equal(t, map[string][]flows.ExtractedEntity{
+		"city": {{Value: "Quito", Confidence: decimal.RequireFromString(`0.95`)}},
+	}, classification.Entities)
+
+	assert.Equal(t, 1, len(httpLogger.Logs))
+	assert.Equal(t, "https://llm.example.com/v1/chat/completions", httpLogger.Logs[0].URL)
+	assert.Contains(t, httpLogger.Logs[0].Request, "Authorization: Bearer ****************\r\n")
+	assert.Contains(t, httpLogger.Logs[0].Request, `following intents: book_flight, book_hotel.`)
+	assert.NotContains(t, httpLogger.Logs[0].Request, "23532624376")
+
+	classification, err = svc.Classify(session, "book flight to Quito", httpLogger.Log)
+	assert.EqualError(t, err, "unable to parse classification from completion: invalid character 'I' looking for beginning of value")
+	assert.Nil(t, classification)
+	assert.Equal(t, 2, len(httpLogger.Logs))
+}
@@ -0,0 +1,94 @@
+package rasa
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/jsonx"
+
+	"github.com/shopspring/decimal"
+)
+
+// Intent is a possible intent match
+type Intent struct {
+	Name       string          `json:"name"`
+	Confidence decimal.Decimal `json:"confidence"`
+}
+
+// Entity is an extracted entity. Values can be numbers or objects as well as text, e.g. from Duckling.
+type Entity struct {
+	Entity     string          `json:"entity"`
+	Value      json.RawMessage `json:"value"`
+	Role       string          `json:"role,omitempty"`
+	Start      int             `json:"start"`
+	End        int             `json:"end"`
+	Confidence decimal.Decimal `json:"confidence_entity"`
+	Extractor  string          `json:"extractor"`
+}
+
+// ParseResponse is the response from a /model/parse request
+type ParseResponse struct {
+	Text          string    `json:"text"`
+	Intent        *Intent   `json:"intent"          validate:"required"`
+	IntentRanking []*Intent `json:"intent_ranking"`
+	Entities      []*Entity `json:"entities"`
+}
+
+type parseRequest struct {
+	Text string `json:"text"`
+}
+
+// Client is a basic client for the HTTP API of a Rasa NLU server
+type Client struct {
+	httpClient  *http.Client
+	httpRetries *httpx.RetryConfig
+	endpoint    string
+	token       string
+}
+
+// NewClient creates a new client for the Rasa server at the given endpoint, e.g. http://localhost:5005
+func NewClient(httpClient *http.Client, httpRetries *httpx.RetryConfig, endpoint, token string) *Client {
+	return &Client{
+		httpClient:  httpClient,
+		httpRetries: httpRetries,
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		token:       token,
+	}
+}
+
+// Parse gets the intents and entities of the given text
+func (c *Client) Parse(text string) (*ParseResponse, *httpx.Trace, error) {
+	endpoint := fmt.Sprintf("%s/model/parse", c.endpoint)
+	if c.token != "" {
+		endpoint += "?token=" + url.QueryEscape(c.token)
+	}
+
+	body := jsonx.MustMarshal(&parseRequest{Text: text})
+
+	request, err := httpx.NewRequest("POST", endpoint, bytes.NewReader(body), map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trace, err := httpx.DoTrace(c.httpClient, request, c.httpRetries, nil, -1)
+	if err != nil {
+		return nil, trace, err
+	}
+
+	if trace.Response != nil && trace.Response.StatusCode == 200 {
+		response := &ParseResponse{}
+		if err := utils.UnmarshalAndValidate(trace.ResponseBody, response); err != nil {
+			return nil, trace, err
+		}
+		return response, trace, nil
+	}
+
+	return nil, trace, errors.New("Rasa API request failed")
+}
@@ -0,0 +1,69 @@
+package rasa_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/services/classification/rasa"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/httpx"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"http://nlu.example.com/model/parse?token=3246231": {
+			httpx.NewMockResponse(200, nil, `xx`), // non-JSON response
+			httpx.NewMockResponse(200, nil, `{}`), // invalid JSON response
+			httpx.NewMockResponse(401, nil, `{"status": "failure", "reason": "NotAuthenticated"}`),
+			httpx.NewMockResponse(200, nil, `{
+				"text": "book a flight to Quito",
+				"intent": {"name": "book_flight", "confidence": 0.9321},
+				"entities": [
+					{"entity": "city", "start": 17, "end": 22, "confidence_entity": 0.9812, "value": "Quito", "extractor": "DIETClassifier"}
+				],
+				"intent_ranking": [
+					{"name": "book_flight", "confidence": 0.9321},
+					{"name": "book_hotel", "confidence": 0.0412}
+				]
+			}`),
+		},
+	}))
+
+	client := rasa.NewClient(http.DefaultClient, nil, "http://nlu.example.com/", "3246231")
+
+	response, trace, err := client.Parse("book a flight to Quito")
+	assert.EqualError(t, err, `invalid character 'x' looking for beginning of value`)
+	test.AssertSnapshot(t, "parse_request", string(trace.RequestTrace))
+	assert.Equal(t, "HTTP/1.0 200 OK\r\nContent-Length: 2\r\n\r\n", string(trace.ResponseTrace))
+	assert.Equal(t, "xx", string(trace.ResponseBody))
+	assert.Nil(t, response)
+
+	response, trace, err = client.Parse("book a flight to Quito")
+	assert.EqualError(t, err, `field 'intent' is required`)
+	assert.NotNil(t, trace)
+	assert.Nil(t, response)
+
+	response, trace, err = client.Parse("book a flight to Quito")
+	assert.EqualError(t, err, `Rasa API request failed`)
+	assert.NotNil(t, trace)
+	assert.Nil(t, response)
+
+	response, trace, err = client.Parse("book a flight to Quito")
+	assert.NoError(t, err)
+	assert.NotNil(t, trace)
+	assert.Equal(t, "book a flight to Quito", response.Text)
+	assert.Equal(t, &rasa.Intent{Name: "book_flight", Confidence: decimal.RequireFromString(`0.9321`)}, response.Intent)
+	assert.Equal(t, []*rasa.Intent{
+		{Name: "book_flight", Confidence: decimal.RequireFromString(`0.9321`)},
+		{Name: "book_hotel", Confidence: decimal.RequireFromString(`0.0412`)},
+	}, response.IntentRanking)
+	assert.Equal(t, []*rasa.Entity{
+		{Entity: "city", Value: json.RawMessage(`"Quito"`), Start: 17, End: 22, Confidence: decimal.RequireFromString(`0.9812`), Extractor: "DIETClassifier"},
+	}, response.Entities)
+}
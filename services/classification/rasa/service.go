@@ -0,0 +1,85 @@
+package rasa
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/httpx"
+
+	"github.com/shopspring/decimal"
+)
+
+// a classification service implementation for a self-hosted Rasa NLU server
+type service struct {
+	client     *Client
+	classifier *flows.Classifier
+	redactor   utils.Redactor
+}
+
+// NewService creates a new classification service
+func NewService(httpClient *http.Client, httpRetries *httpx.RetryConfig, classifier *flows.Classifier, endpoint, token string) flows.ClassificationService {
+	return &service{
+		client:     NewClient(httpClient, httpRetries, endpoint, token),
+		classifier: classifier,
+		redactor:   utils.NewRedactor(flows.RedactionMask, token, url.QueryEscape(token)), // token is sent in the query string
+	}
+}
+
+func (s *service) Classify(session flows.Session, input string, logHTTP flows.HTTPLogCallback) (*flows.Classification, error) {
+	response, trace, err := s.client.Parse(input)
+	if trace != nil {
+		logHTTP(flows.NewHTTPLog(trace, flows.HTTPStatusFromCode, s.redactor))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// older servers only return the top intent
+	ranking := response.IntentRanking
+	if len(ranking) == 0 && response.Intent.Name != "" {
+		ranking = []*Intent{response.Intent}
+	}
+
+	result := &flows.Classification{
+		Intents:  make([]flows.ExtractedIntent, 0, len(ranking)),
+		Entities: make(map[string][]flows.ExtractedEntity),
+	}
+
+	for _, intent := range ranking {
+		result.Intents = append(result.Intents, flows.ExtractedIntent{Name: intent.Name, Confidence: intent.Confidence})
+	}
+	sort.SliceStable(result.Intents, func(i, j int) bool { return result.Intents[i].Confidence.GreaterThan(result.Intents[j].Confidence) })
+
+	for _, entity := range response.Entities {
+		// rule based extractors (e.g. regexes, lookup tables) don't report a confidence
+		confidence := entity.Confidence
+		if confidence.IsZero() {
+			confidence = decimal.New(1, 0)
+		}
+
+		result.Entities[entity.Entity] = append(result.Entities[entity.Entity], flows.ExtractedEntity{Value: entityValue(entity.Value), Confidence: confidence})
+	}
+
+	return result, nil
+}
+
+// converts an entity value to text, with values which aren't strings kept as JSON, e.g. 12.5 or {"value": 10, "unit": "USD"}
+func entityValue(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, raw); err != nil {
+		return string(raw)
+	}
+	return compacted.String()
+}
+
+var _ flows.ClassificationService = (*service)(nil)
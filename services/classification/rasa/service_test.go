@@ -0,0 +1,109 @@
+package rasa_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/services/classification/rasa"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/uuids"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService(t *testing.T) {
+	session, _ := test.NewSessionBuilder().MustBuild()
+
+	defer uuids.SetGenerator(uuids.DefaultGenerator)
+	defer dates.SetNowSource(dates.DefaultNowSource)
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	uuids.SetGenerator(uuids.NewSeededGenerator(12345))
+	dates.SetNowSource(dates.NewSequentialNowSource(time.Date(2019, 10, 7, 15, 21, 30, 123456789, time.UTC)))
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"http://nlu.example.com/model/parse?token=23532624376": {
+			httpx.NewMockResponse(503, nil, `{"status": "failure", "reason": "ServiceUnavailable"}`),
+			httpx.NewMockResponse(200, nil, `{
+				"text": "book flight to Quito",
+				"intent": {"name": "book_flight", "confidence": 0.9321},
+				"entities": [
+					{"entity": "city", "start": 15, "end": 20, "confidence_entity": 0.9812, "value": "Quito", "extractor": "DIETClassifier"},
+					{"entity": "city", "start": 15, "end": 20, "value": "quito", "extractor": "RegexEntityExtractor"},
+					{"entity": "number", "start": 21, "end": 22, "value": 2, "extractor": "DucklingEntityExtractor"},
+					{"entity": "amount-of-money", "start": 23, "end": 29, "value": {"value": 10.5, "unit": "USD"}, "extractor": "DucklingEntityExtractor"}
+				],
+				"intent_ranking": [
+					{"name": "book_hotel", "confidence": 0.0412},
+					{"name": "book_flight", "confidence": 0.9321}
+				]
+			}`),
+		},
+		"http://nlu.example.com/model/parse?token=open%2Fsesame%3D": {
+			httpx.NewMockResponse(200, nil, `{"text": "hi", "intent": {"name": "greet", "confidence": 0.9}, "entities": []}`),
+		},
+		"http://nlu.example.com/model/parse": {
+			httpx.NewMockResponse(200, nil, `{"text": "hi", "intent": {"name": "greet", "confidence": 0.9}, "entities": []}`),
+		},
+	}))
+
+	// parsing is side-effect free so it's safe to retry even though it's a POST
+	retries := httpx.NewFixedRetries(1 * time.Millisecond)
+	retries.ShouldRetry = func(r *http.Request, s *http.Response, d time.Duration) bool { return s.StatusCode == 503 }
+
+	svc := rasa.NewService(
+		http.DefaultClient,
+		retries,
+		test.NewClassifier("Booking", "rasa", []string{"book_flight", "book_hotel"}),
+		"http://nlu.example.com",
+		"23532624376",
+	)
+
+	httpLogger := &flows.HTTPLogger{}
+
+	classification, err := svc.Classify(session, "book flight to Quito", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, []flows.ExtractedIntent{
+		{Name: "book_flight", Confidence: decimal.RequireFromString(`0.9321`)},
+		{Name: "book_hotel", Confidence: decimal.RequireFromString(`0.0412`)},
+	}, classification.Intents)
+	assert.Equal(t, map[string][]flows.ExtractedEntity{
+		"city": {
+			{Value: "Quito", Confidence: decimal.RequireFromString(`0.9812`)},
+			{Value: "quito", Confidence: decimal.RequireFromString(`1`)},
+		},
+		"number": {
+			{Value: "2", Confidence: decimal.RequireFromString(`1`)},
+		},
+		"amount-of-money": {
+			{Value: `{"value":10.5,"unit":"USD"}`, Confidence: decimal.RequireFromString(`1`)},
+		},
+	}, classification.Entities)
+
+	assert.Equal(t, 1, len(httpLogger.Logs))
+	assert.Equal(t, "http://nlu.example.com/model/parse?token=****************", httpLogger.Logs[0].URL)
+	assert.Equal(t, 1, httpLogger.Logs[0].Retries)
+	assert.Equal(t, "POST /model/parse?token=**************** HTTP/1.1\r\nHost: nlu.example.com\r\nUser-Agent: Go-http-client/1.1\r\nContent-Length: 31\r\nContent-Type: application/json\r\nAccept-Encoding: gzip\r\n\r\n{\"text\":\"book flight to Quito\"}", httpLogger.Logs[0].Request)
+
+	// tokens are redacted in their escaped form too
+	svc = rasa.NewService(http.DefaultClient, nil, test.NewClassifier("Booking", "rasa", []string{"greet"}), "http://nlu.example.com", "open/sesame=")
+	httpLogger = &flows.HTTPLogger{}
+
+	_, err = svc.Classify(session, "hi", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://nlu.example.com/model/parse?token=****************", httpLogger.Logs[0].URL)
+	assert.NotContains(t, httpLogger.Logs[0].Request, "sesame")
+
+	// and servers without tokens don't have anything redacted
+	svc = rasa.NewService(http.DefaultClient, nil, test.NewClassifier("Booking", "rasa", []string{"greet"}), "http://nlu.example.com", "")
+	httpLogger = &flows.HTTPLogger{}
+
+	_, err = svc.Classify(session, "hi", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://nlu.example.com/model/parse", httpLogger.Logs[0].URL)
+	assert.Equal(t, "POST /model/parse HTTP/1.1\r\nHost: nlu.example.com\r\nUser-Agent: Go-http-client/1.1\r\nContent-Length: 13\r\nContent-Type: application/json\r\nAccept-Encoding: gzip\r\n\r\n{\"text\":\"hi\"}", httpLogger.Logs[0].Request)
+}
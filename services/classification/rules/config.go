@@ -0,0 +1,159 @@
+package rules
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/utils"
+
+	"github.com/pkg/errors"
+)
+
+// Config is the configuration of a rules based classifier, e.g.
+//
+//   {
+//     "threshold": 0.5,
+//     "intents": [
+//       {"name": "book_flight", "keywords": ["flight", "fly"], "patterns": ["^book .* to \\w+$"], "examples": ["I want to fly to Quito"]}
+//     ],
+//     "entities": [
+//       {"name": "destination", "patterns": ["to (\\w+)"]},
+//       {"name": "class", "values": {"business": ["biz", "business class"], "economy": ["coach"]}}
+//     ]
+//   }
+type Config struct {
+	Threshold float64         `json:"threshold,omitempty" validate:"min=0,max=1"`
+	Intents   []*IntentConfig `json:"intents"             validate:"dive"`
+	Entities  []*EntityConfig `json:"entities,omitempty"  validate:"dive"`
+}
+
+// IntentConfig configures how an intent is matched
+type IntentConfig struct {
+	Name     string   `json:"name"               validate:"required"`
+	Keywords []string `json:"keywords,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+	Examples []string `json:"examples,omitempty"`
+}
+
+// EntityConfig configures how an entity is extracted. Patterns extract their first capture group, or the
+// whole match if they don't have one. Values map canonical values to their synonyms.
+type EntityConfig struct {
+	Name     string              `json:"name"               validate:"required"`
+	Patterns []string            `json:"patterns,omitempty"`
+	Values   map[string][]string `json:"values,omitempty"`
+}
+
+// ReadConfig reads and validates a rules classifier configuration
+func ReadConfig(data json.RawMessage) (*Config, error) {
+	if len(data) == 0 {
+		return nil, errors.New("classifier has no config")
+	}
+
+	c := &Config{}
+	if err := utils.UnmarshalAndValidate(data, c); err != nil {
+		return nil, errors.Wrap(err, "unable to read classifier config")
+	}
+	return c, nil
+}
+
+type intent struct {
+	name     string
+	keywords []*regexp.Regexp
+	patterns []*regexp.Regexp
+	examples [][]string
+}
+
+type entity struct {
+	name     string
+	patterns []*regexp.Regexp
+	synonyms []*synonym
+}
+
+type synonym struct {
+	value   string
+	matcher *regexp.Regexp
+}
+
+// compiles the given config into matchers
+func compile(c *Config) ([]*intent, []*entity, error) {
+	intents := make([]*intent, len(c.Intents))
+	for i, ic := range c.Intents {
+		in := &intent{name: ic.Name}
+
+		for _, k := range ic.Keywords {
+			re, err := wordMatcher(k)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "invalid keyword for intent '%s'", ic.Name)
+			}
+			in.keywords = append(in.keywords, re)
+		}
+		for _, p := range ic.Patterns {
+			re, err := compilePattern(p)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "invalid pattern for intent '%s'", ic.Name)
+			}
+			in.patterns = append(in.patterns, re)
+		}
+		for _, e := range ic.Examples {
+			in.examples = append(in.examples, tokenize(e))
+		}
+
+		intents[i] = in
+	}
+
+	entities := make([]*entity, len(c.Entities))
+	for i, ec := range c.Entities {
+		en := &entity{name: ec.Name}
+
+		for _, p := range ec.Patterns {
+			re, err := compilePattern(p)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "invalid pattern for entity '%s'", ec.Name)
+			}
+			en.patterns = append(en.patterns, re)
+		}
+
+		values := make([]string, 0, len(ec.Values))
+		for value := range ec.Values {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+
+		for _, value := range values {
+			for _, s := range append([]string{value}, ec.Values[value]...) {
+				re, err := wordMatcher(s)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "invalid value for entity '%s'", ec.Name)
+				}
+				en.synonyms = append(en.synonyms, &synonym{value: value, matcher: re})
+			}
+		}
+
+		entities[i] = en
+	}
+
+	return intents, entities, nil
+}
+
+// patterns are always case-insensitive
+func compilePattern(p string) (*regexp.Regexp, error) {
+	return regexp.Compile(`(?i)` + p)
+}
+
+// creates a case-insensitive matcher for the given word or phrase. We can't use \b or \W to anchor the match as they
+// only treat ASCII letters as word characters, and \b won't match before or after non-word characters, e.g. in "#help"
+// or "c++". An empty word would match anything.
+func wordMatcher(s string) (*regexp.Regexp, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("can't be empty")
+	}
+	return regexp.MustCompile(`(?i)(^|[^\p{L}\p{N}_])` + regexp.QuoteMeta(s) + `([^\p{L}\p{N}_]|$)`), nil
+}
+
+// tokenizes the given text into lowercase words
+func tokenize(s string) []string {
+	return utils.TokenizeString(strings.ToLower(s))
+}
@@ -0,0 +1,182 @@
+package rules
+
+import (
+	"math"
+	"sort"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+
+	"github.com/shopspring/decimal"
+)
+
+// TypeRules is the classifier type for rules based classifiers which are evaluated locally
+const TypeRules = "rules"
+
+// confidences assigned to different kinds of matches
+const (
+	patternConfidence       = 1.0
+	keywordConfidence       = 0.8
+	extraKeywordConfidence  = 0.05
+	maxKeywordConfidence    = 0.95
+	defaultIntentThreshold  = 0.1
+	entityPatternConfidence = 1.0
+	entityValueConfidence   = 0.9
+)
+
+// a classification service implementation which matches input against keywords, patterns and examples
+// configured on the classifier itself, so it doesn't require any external service
+type service struct {
+	classifier *flows.Classifier
+	threshold  float64
+	intents    []*intent
+	entities   []*entity
+}
+
+// NewService creates a new classification service from the config of the given classifier
+func NewService(classifier *flows.Classifier) (flows.ClassificationService, error) {
+	config, err := ReadConfig(classifier.Config())
+	if err != nil {
+		return nil, err
+	}
+
+	intents, entities, err := compile(config)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := config.Threshold
+	if threshold == 0 {
+		threshold = defaultIntentThreshold
+	}
+
+	return &service{classifier: classifier, threshold: threshold, intents: intents, entities: entities}, nil
+}
+
+func (s *service) Classify(session flows.Session, input string, logHTTP flows.HTTPLogCallback) (*flows.Classification, error) {
+	result := &flows.Classification{
+		Intents:  make([]flows.ExtractedIntent, 0),
+		Entities: make(map[string][]flows.ExtractedEntity),
+	}
+
+	tokens := tokenize(input)
+
+	type scored struct {
+		name       string
+		confidence float64
+	}
+	matches := make([]scored, 0, len(s.intents))
+	for _, in := range s.intents {
+		if confidence := in.score(input, tokens); confidence >= s.threshold {
+			matches = append(matches, scored{in.name, confidence})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].confidence != matches[j].confidence {
+			return matches[i].confidence > matches[j].confidence
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	for _, m := range matches {
+		result.Intents = append(result.Intents, flows.ExtractedIntent{Name: m.name, Confidence: toDecimal(m.confidence)})
+	}
+
+	for _, en := range s.entities {
+		if extracted := en.extract(input); len(extracted) > 0 {
+			result.Entities[en.name] = extracted
+		}
+	}
+
+	return result, nil
+}
+
+// scores the given input against this intent, returning the best confidence of any kind of match
+func (i *intent) score(input string, tokens []string) float64 {
+	best := 0.0
+
+	for _, p := range i.patterns {
+		if p.MatchString(input) {
+			return patternConfidence
+		}
+	}
+
+	keywords := 0
+	for _, k := range i.keywords {
+		if k.MatchString(input) {
+			keywords++
+		}
+	}
+	if keywords > 0 {
+		best = math.Min(keywordConfidence+float64(keywords-1)*extraKeywordConfidence, maxKeywordConfidence)
+	}
+
+	for _, example := range i.examples {
+		best = math.Max(best, similarity(tokens, example))
+	}
+
+	return best
+}
+
+// extracts values of this entity from the given input
+func (e *entity) extract(input string) []flows.ExtractedEntity {
+	extracted := make([]flows.ExtractedEntity, 0)
+	seen := make(map[string]bool)
+
+	add := func(value string, confidence float64) {
+		if value != "" && !seen[value] {
+			extracted = append(extracted, flows.ExtractedEntity{Value: value, Confidence: toDecimal(confidence)})
+			seen[value] = true
+		}
+	}
+
+	for _, p := range e.patterns {
+		for _, match := range p.FindAllStringSubmatch(input, -1) {
+			if len(match) > 1 {
+				add(match[1], entityPatternConfidence)
+			} else {
+				add(match[0], entityPatternConfidence)
+			}
+		}
+	}
+
+	for _, s := range e.synonyms {
+		if s.matcher.MatchString(input) {
+			add(s.value, entityValueConfidence)
+		}
+	}
+
+	return extracted
+}
+
+// calculates the Jaccard similarity of two sets of tokens
+func similarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	setA := make(map[string]bool, len(a))
+	for _, t := range a {
+		setA[t] = true
+	}
+	setB := make(map[string]bool, len(b))
+	for _, t := range b {
+		setB[t] = true
+	}
+
+	intersection := 0
+	for t := range setA {
+		if setB[t] {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+
+	return float64(intersection) / float64(union)
+}
+
+func toDecimal(f float64) decimal.Decimal {
+	return decimal.NewFromFloat(math.Round(f*10000) / 10000)
+}
+
+var _ flows.ClassificationService = (*service)(nil)
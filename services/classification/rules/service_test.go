@@ -0,0 +1,132 @@
+package rules_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/assets"
+	"github.com/developc3ntro/omni-goflow/assets/static"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/services/classification/rules"
+	"github.com/developc3ntro/omni-goflow/test"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClassifier(config string) *flows.Classifier {
+	return flows.NewClassifier(static.NewClassifierWithConfig(
+		assets.ClassifierUUID("1c06c884-39dd-4ce4-ad9f-9a01cbe6c000"),
+		"Booking",
+		rules.TypeRules,
+		[]string{"book_flight", "book_hotel"},
+		json.RawMessage(config),
+	))
+}
+
+func TestService(t *testing.T) {
+	session, _ := test.NewSessionBuilder().MustBuild()
+
+	svc, err := rules.NewService(newClassifier(`{
+		"threshold": 0.3,
+		"intents": [
+			{"name": "book_flight", "keywords": ["flight", "fly", "plane"], "patterns": ["^book a flight"]},
+			{"name": "book_hotel", "keywords": ["hotel", "room"], "examples": ["I need somewhere to stay tonight"]},
+			{"name": "get_help", "keywords": ["#help", "c++"]},
+			{"name": "order_food", "keywords": ["café", "ni"]}
+		],
+		"entities": [
+			{"name": "destination", "patterns": ["\\b(?:flight|fly) to (\\w+)"]},
+			{"name": "class", "values": {"business": ["biz"], "economy": ["coach"]}}
+		]
+	}`))
+	require.NoError(t, err)
+
+	httpLogger := &flows.HTTPLogger{}
+
+	// pattern match
+	classification, err := svc.Classify(session, "Book a flight to Quito", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, []flows.ExtractedIntent{
+		{Name: "book_flight", Confidence: decimal.RequireFromString(`1`)},
+	}, classification.Intents)
+	assert.Equal(t, map[string][]flows.ExtractedEntity{
+		"destination": {{Value: "Quito", Confidence: decimal.RequireFromString(`1`)}},
+	}, classification.Entities)
+
+	// multiple keyword matches and a synonym
+	classification, err = svc.Classify(session, "I want to fly coach on a plane, and need a hotel", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, []flows.ExtractedIntent{
+		{Name: "book_flight", Confidence: decimal.RequireFromString(`0.85`)},
+		{Name: "book_hotel", Confidence: decimal.RequireFromString(`0.8`)},
+	}, classification.Intents)
+	assert.Equal(t, map[string][]flows.ExtractedEntity{
+		"class": {{Value: "economy", Confidence: decimal.RequireFromString(`0.9`)}},
+	}, classification.Entities)
+
+	// keywords must match whole words
+	classification, err = svc.Classify(session, "I love my flighty roommates", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, []flows.ExtractedIntent{}, classification.Intents)
+	assert.Equal(t, map[string][]flows.ExtractedEntity{}, classification.Entities)
+
+	// keywords can start or end with non-word characters
+	classification, err = svc.Classify(session, "#help", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, []flows.ExtractedIntent{
+		{Name: "get_help", Confidence: decimal.RequireFromString(`0.8`)},
+	}, classification.Intents)
+
+	classification, err = svc.Classify(session, "stuck on some C++, send help", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, []flows.ExtractedIntent{
+		{Name: "get_help", Confidence: decimal.RequireFromString(`0.8`)},
+	}, classification.Intents)
+
+	classification, err = svc.Classify(session, "#helpful abc++", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, []flows.ExtractedIntent{}, classification.Intents)
+
+	// and letters outside of ASCII are still part of words
+	classification, err = svc.Classify(session, "un café", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, []flows.ExtractedIntent{
+		{Name: "order_food", Confidence: decimal.RequireFromString(`0.8`)},
+	}, classification.Intents)
+
+	classification, err = svc.Classify(session, "el niño", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, []flows.ExtractedIntent{}, classification.Intents)
+
+	// example similarity
+	classification, err = svc.Classify(session, "need somewhere to stay", httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, []flows.ExtractedIntent{
+		{Name: "book_hotel", Confidence: decimal.RequireFromString(`0.6667`)},
+	}, classification.Intents)
+
+	// nothing is called over HTTP
+	assert.Equal(t, 0, len(httpLogger.Logs))
+}
+
+func TestServiceWithInvalidConfig(t *testing.T) {
+	_, err := rules.NewService(newClassifier(``))
+	assert.EqualError(t, err, "classifier has no config")
+
+	_, err = rules.NewService(newClassifier(`{"intents": [{"keywords": ["flight"]}]}`))
+	assert.EqualError(t, err, "unable to read classifier config: field 'intents[0].name' is required")
+
+	_, err = rules.NewService(newClassifier(`{"intents": [{"name": "book_flight", "patterns": ["(flight"]}]}`))
+	assert.EqualError(t, err, "invalid pattern for intent 'book_flight': error parsing regexp: missing closing ): `(?i)(flight`")
+
+	_, err = rules.NewService(newClassifier(`{"intents": [], "entities": [{"name": "city", "patterns": ["[a-"]}]}`))
+	assert.EqualError(t, err, "invalid pattern for entity 'city': error parsing regexp: missing closing ]: `[a-`")
+
+	_, err = rules.NewService(newClassifier(`{"intents": [{"name": "book_flight", "keywords": ["flight", " "]}]}`))
+	assert.EqualError(t, err, "invalid keyword for intent 'book_flight': can't be empty")
+
+	_, err = rules.NewService(newClassifier(`{"intents": [], "entities": [{"name": "class", "values": {"economy": ["coach", ""]}}]}`))
+	assert.EqualError(t, err, "invalid value for entity 'class': can't be empty")
+}
@@ -0,0 +1,75 @@
+package email
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/httpx"
+
+	"github.com/pkg/errors"
+)
+
+// Attachment is an email attachment whose content has been fetched
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// FetchAttachment downloads the content of the given attachment so that it can be included in an email
+func FetchAttachment(httpClient *http.Client, httpAccess *httpx.AccessConfig, attachment utils.Attachment, maxBytes int, logHTTP flows.HTTPLogCallback) (*Attachment, error) {
+	contentType, attachmentURL := attachment.ToParts()
+
+	request, err := httpx.NewRequest("GET", attachmentURL, nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to fetch attachment %s", attachmentURL)
+	}
+
+	trace, err := httpx.DoTrace(httpClient, request, nil, httpAccess, maxBytes)
+	if trace != nil {
+		logHTTP(flows.NewHTTPLog(trace, flows.HTTPStatusFromCode, nil))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to fetch attachment %s", attachmentURL)
+	}
+	if trace.Response.StatusCode/100 != 2 {
+		return nil, errors.Errorf("unable to fetch attachment %s: got status %d", attachmentURL, trace.Response.StatusCode)
+	}
+
+	// if attachment doesn't specify a full media type, use the one from the response
+	if !strings.Contains(contentType, "/") {
+		contentType = trace.Response.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = httpx.DetectContentType(trace.ResponseBody)
+		}
+	}
+
+	return &Attachment{Filename: attachmentFilename(attachmentURL), ContentType: contentType, Content: trace.ResponseBody}, nil
+}
+
+// FetchAttachments downloads the content of all the given attachments
+func FetchAttachments(httpClient *http.Client, httpAccess *httpx.AccessConfig, attachments []utils.Attachment, maxBytes int, logHTTP flows.HTTPLogCallback) ([]*Attachment, error) {
+	fetched := make([]*Attachment, len(attachments))
+	for i, a := range attachments {
+		f, err := FetchAttachment(httpClient, httpAccess, a, maxBytes, logHTTP)
+		if err != nil {
+			return nil, err
+		}
+		fetched[i] = f
+	}
+	return fetched, nil
+}
+
+// gets a filename for an attachment from the last segment of its URL path
+func attachmentFilename(attachmentURL string) string {
+	if u, err := url.Parse(attachmentURL); err == nil {
+		if name := path.Base(u.Path); name != "." && name != "/" {
+			return name
+		}
+	}
+	return "attachment"
+}
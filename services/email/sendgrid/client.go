@@ -0,0 +1,128 @@
+package sendgrid
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/jsonx"
+
+	"github.com/pkg/errors"
+)
+
+// Address is an email address with an optional display name
+type Address struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+// Personalization is the set of recipients of a message
+type Personalization struct {
+	To  []*Address `json:"to"`
+	Cc  []*Address `json:"cc,omitempty"`
+	Bcc []*Address `json:"bcc,omitempty"`
+}
+
+// Content is a body of a message with a given content type
+type Content struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Attachment is a file attached to a message with base64 encoded content
+type Attachment struct {
+	Content     string `json:"content"`
+	Type        string `json:"type,omitempty"`
+	Filename    string `json:"filename"`
+	Disposition string `json:"disposition,omitempty"`
+}
+
+// SendRequest is a request to send a message
+type SendRequest struct {
+	Personalizations []*Personalization `json:"personalizations"`
+	From             *Address           `json:"from"`
+	ReplyTo          *Address           `json:"reply_to,omitempty"`
+	Subject          string             `json:"subject"`
+	Content          []*Content         `json:"content"`
+	Attachments      []*Attachment      `json:"attachments,omitempty"`
+}
+
+type errorResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+		Field   string `json:"field"`
+	} `json:"errors"`
+}
+
+// Client is a basic client for the SendGrid v3 mail API, or any API compatible with it
+type Client struct {
+	httpClient  *http.Client
+	httpRetries *httpx.RetryConfig
+	httpAccess  *httpx.AccessConfig
+	baseURL     string
+	headers     map[string]string
+}
+
+// NewClient creates a new client for the API at the given base URL, e.g. https://api.sendgrid.com
+func NewClient(httpClient *http.Client, httpRetries *httpx.RetryConfig, httpAccess *httpx.AccessConfig, baseURL, apiKey string) *Client {
+	return &Client{
+		httpClient:  httpClient,
+		httpRetries: httpRetries,
+		httpAccess:  httpAccess,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		headers: map[string]string{
+			"Authorization": fmt.Sprintf("Bearer %s", apiKey),
+			"Content-Type":  "application/json",
+		},
+	}
+}
+
+// Send sends the given message
+func (c *Client) Send(msg *SendRequest) (*httpx.Trace, error) {
+	endpoint := fmt.Sprintf("%s/v3/mail/send", c.baseURL)
+
+	request, err := httpx.NewRequest("POST", endpoint, bytes.NewReader(jsonx.MustMarshal(msg)), c.headers)
+	if err != nil {
+		return nil, err
+	}
+
+	trace, err := httpx.DoTrace(c.httpClient, request, c.httpRetries, c.httpAccess, -1)
+	if err != nil {
+		return trace, err
+	}
+
+	if trace.Response.StatusCode/100 == 2 {
+		return trace, nil
+	}
+
+	errResponse := &errorResponse{}
+	if jsonx.Unmarshal(trace.ResponseBody, errResponse) == nil && len(errResponse.Errors) > 0 {
+		return trace, errors.Errorf("send request failed: %s", errResponse.Errors[0].Message)
+	}
+
+	return trace, errors.New("send request failed")
+}
+
+// ShouldRetry is the function for determining if a send request should be retried. Sends aren't idempotent
+// so the default HTTP retry logic never retries them. A 429 response means the message wasn't accepted, and a 502
+// or 503 means the request never got past the provider's gateway, so those can be safely sent again. Any other 5XX,
+// including a 504 from a gateway which gave up waiting, may come after the message was accepted, so retrying could
+// send it twice.
+func ShouldRetry(request *http.Request, response *http.Response, withDelay time.Duration) bool {
+	if response != nil {
+		switch response.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+			return true
+		}
+	}
+
+	return httpx.DefaultShouldRetry(request, response, withDelay)
+}
+
+// NewRetries creates a new retry config for send requests with the given backoffs
+func NewRetries(backoffs ...time.Duration) *httpx.RetryConfig {
+	return &httpx.RetryConfig{Backoffs: backoffs, ShouldRetry: ShouldRetry}
+}
@@ -0,0 +1,65 @@
+package sendgrid_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/developc3ntro/omni-goflow/services/email/sendgrid"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/httpx"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"https://api.sendgrid.com/v3/mail/send": {
+			httpx.NewMockResponse(400, nil, `{"errors": [{"message": "The from email does not contain a valid address.", "field": "from.email"}]}`),
+			httpx.NewMockResponse(401, nil, `Unauthorized`),
+			httpx.NewMockResponse(503, nil, `Service Unavailable`),
+			httpx.NewMockResponse(429, nil, `Too Many Requests`),
+			httpx.NewMockResponse(202, nil, ``),
+			httpx.MockConnectionError,
+			httpx.NewMockResponse(500, nil, `Internal Server Error`),
+			httpx.NewMockResponse(202, nil, ``),
+		},
+	}))
+
+	client := sendgrid.NewClient(http.DefaultClient, nil, nil, "https://api.sendgrid.com/", "SG.123456")
+	msg := &sendgrid.SendRequest{
+		Personalizations: []*sendgrid.Personalization{{To: []*sendgrid.Address{{Email: "bob@nyaruka.com"}}}},
+		From:             &sendgrid.Address{Email: "updates@temba.io"},
+		Subject:          "Updates",
+		Content:          []*sendgrid.Content{{Type: "text/plain", Value: "Have a great week"}},
+	}
+
+	trace, err := client.Send(msg)
+	assert.EqualError(t, err, `send request failed: The from email does not contain a valid address.`)
+	test.AssertSnapshot(t, "send_request", string(trace.RequestTrace))
+
+	trace, err = client.Send(msg)
+	assert.EqualError(t, err, `send request failed`)
+	assert.Equal(t, 401, trace.Response.StatusCode)
+
+	// with retries, 429 and 503 responses are retried even though sends aren't idempotent
+	client = sendgrid.NewClient(http.DefaultClient, sendgrid.NewRetries(time.Millisecond, time.Millisecond), nil, "https://api.sendgrid.com", "SG.123456")
+
+	trace, err = client.Send(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, 202, trace.Response.StatusCode)
+	assert.Equal(t, 2, trace.Retries)
+
+	// but connection errors aren't as the message may have been accepted
+	trace, err = client.Send(msg)
+	assert.EqualError(t, err, `unable to connect to server`)
+	assert.Equal(t, 0, trace.Retries)
+
+	// and neither are other 5XX responses
+	trace, err = client.Send(msg)
+	assert.EqualError(t, err, `send request failed`)
+	assert.Equal(t, 500, trace.Response.StatusCode)
+	assert.Equal(t, 0, trace.Retries)
+}
@@ -0,0 +1,91 @@
+package sendgrid
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	emails "github.com/developc3ntro/omni-goflow/services/email"
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/httpx"
+)
+
+// an email service implementation which sends via the SendGrid v3 mail API
+type service struct {
+	client             *Client
+	httpClient         *http.Client
+	httpAccess         *httpx.AccessConfig
+	from               string
+	maxAttachmentBytes int
+	redactor           utils.Redactor
+}
+
+// NewService creates a new SendGrid email service which sends messages from the given address. Attachments are
+// fetched from their URLs and are rejected if they exceed maxAttachmentBytes.
+func NewService(httpClient *http.Client, httpRetries *httpx.RetryConfig, httpAccess *httpx.AccessConfig, baseURL, apiKey, from string, maxAttachmentBytes int) flows.EmailService {
+	return &service{
+		client:             NewClient(httpClient, httpRetries, httpAccess, baseURL, apiKey),
+		httpClient:         httpClient,
+		httpAccess:         httpAccess,
+		from:               from,
+		maxAttachmentBytes: maxAttachmentBytes,
+		redactor:           utils.NewRedactor(flows.RedactionMask, apiKey),
+	}
+}
+
+func (s *service) Send(session flows.Session, email *flows.Email, logHTTP flows.HTTPLogCallback) error {
+	attachments, err := emails.FetchAttachments(s.httpClient, s.httpAccess, email.Attachments, s.maxAttachmentBytes, logHTTP)
+	if err != nil {
+		return err
+	}
+
+	body := email.Body
+
+	// sending blank emails is a good way to get flagged as a spammer so use placeholder if body is empty
+	if strings.TrimSpace(body) == "" {
+		body = "(empty body)"
+	}
+
+	msg := &SendRequest{
+		Personalizations: []*Personalization{
+			{To: toAddresses(email.To), Cc: toAddresses(email.Cc), Bcc: toAddresses(email.Bcc)},
+		},
+		From:    &Address{Email: s.from},
+		Subject: email.Subject,
+		Content: []*Content{{Type: "text/plain", Value: body}},
+	}
+	if email.ReplyTo != "" {
+		msg.ReplyTo = &Address{Email: email.ReplyTo}
+	}
+	if email.HTML != "" {
+		msg.Content = append(msg.Content, &Content{Type: "text/html", Value: email.HTML})
+	}
+	for _, a := range attachments {
+		msg.Attachments = append(msg.Attachments, &Attachment{
+			Content:     base64.StdEncoding.EncodeToString(a.Content),
+			Type:        a.ContentType,
+			Filename:    a.Filename,
+			Disposition: "attachment",
+		})
+	}
+
+	trace, err := s.client.Send(msg)
+	if trace != nil {
+		logHTTP(flows.NewHTTPLog(trace, flows.HTTPStatusFromCode, s.redactor))
+	}
+	return err
+}
+
+func toAddresses(emails []string) []*Address {
+	if len(emails) == 0 {
+		return nil
+	}
+	addresses := make([]*Address, len(emails))
+	for i, e := range emails {
+		addresses[i] = &Address{Email: e}
+	}
+	return addresses
+}
+
+var _ flows.EmailService = (*service)(nil)
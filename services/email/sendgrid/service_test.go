@@ -0,0 +1,106 @@
+package sendgrid_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/services/email/sendgrid"
+	"github.com/developc3ntro/omni-goflow/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	var received []*sendgrid.SendRequest
+	failures := 1
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/terms.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte(`%PDF-1.4`))
+		case "/v3/mail/send":
+			if r.Header.Get("Authorization") != "Bearer SG.123456" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"errors": [{"message": "The provided authorization grant is invalid, expired, or revoked"}]}`))
+				return
+			}
+			if failures > 0 {
+				failures--
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			body, _ := io.ReadAll(r.Body)
+			msg := &sendgrid.SendRequest{}
+			require.NoError(t, json.Unmarshal(body, msg))
+			received = append(received, msg)
+
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	retries := sendgrid.NewRetries(time.Millisecond)
+	svc := sendgrid.NewService(http.DefaultClient, retries, nil, server.URL, "SG.123456", "updates@temba.io", 1000)
+
+	httpLogger := &flows.HTTPLogger{}
+
+	err := svc.Send(nil, &flows.Email{
+		To:          []string{"bob@nyaruka.com"},
+		Cc:          []string{"jim@nyaruka.com"},
+		ReplyTo:     "support@nyaruka.com",
+		Subject:     "Updates",
+		Body:        "See attached",
+		HTML:        "<p>See attached</p>",
+		Attachments: []utils.Attachment{utils.Attachment("application/pdf:" + server.URL + "/files/terms.pdf")},
+	}, httpLogger.Log)
+	require.NoError(t, err)
+
+	// first send attempt was retried
+	assert.Len(t, received, 1)
+	assert.Equal(t, []*sendgrid.Personalization{{
+		To: []*sendgrid.Address{{Email: "bob@nyaruka.com"}},
+		Cc: []*sendgrid.Address{{Email: "jim@nyaruka.com"}},
+	}}, received[0].Personalizations)
+	assert.Equal(t, &sendgrid.Address{Email: "updates@temba.io"}, received[0].From)
+	assert.Equal(t, &sendgrid.Address{Email: "support@nyaruka.com"}, received[0].ReplyTo)
+	assert.Equal(t, []*sendgrid.Content{{Type: "text/plain", Value: "See attached"}, {Type: "text/html", Value: "<p>See attached</p>"}}, received[0].Content)
+	assert.Equal(t, []*sendgrid.Attachment{{Content: "JVBERi0xLjQ=", Type: "application/pdf", Filename: "terms.pdf", Disposition: "attachment"}}, received[0].Attachments)
+
+	// one log for the attachment fetch and one for the send which has the API key redacted
+	assert.Len(t, httpLogger.Logs, 2)
+	assert.Equal(t, server.URL+"/files/terms.pdf", httpLogger.Logs[0].URL)
+	assert.Equal(t, server.URL+"/v3/mail/send", httpLogger.Logs[1].URL)
+	assert.Equal(t, flows.CallStatusSuccess, httpLogger.Logs[1].Status)
+	assert.Equal(t, 1, httpLogger.Logs[1].Retries)
+	assert.Contains(t, httpLogger.Logs[1].Request, "Authorization: Bearer ****************")
+	assert.NotContains(t, httpLogger.Logs[1].Request, "SG.123456")
+
+	// blank bodies are replaced with a placeholder
+	err = svc.Send(nil, &flows.Email{To: []string{"bob@nyaruka.com"}, Subject: "Updates", Body: " "}, httpLogger.Log)
+	require.NoError(t, err)
+	assert.Len(t, received, 2)
+	assert.Equal(t, []*sendgrid.Content{{Type: "text/plain", Value: "(empty body)"}}, received[1].Content)
+	assert.Nil(t, received[1].ReplyTo)
+
+	// attachments which can't be fetched prevent sending
+	err = svc.Send(nil, &flows.Email{To: []string{"bob@nyaruka.com"}, Subject: "Updates", Body: "Hi", Attachments: []utils.Attachment{utils.Attachment("image/jpeg:" + server.URL + "/files/missing.jpg")}}, httpLogger.Log)
+	assert.EqualError(t, err, "unable to fetch attachment "+server.URL+"/files/missing.jpg: got status 404")
+	assert.Len(t, received, 2)
+
+	// API errors are returned
+	svc = sendgrid.NewService(http.DefaultClient, retries, nil, server.URL, "SG.654321", "updates@temba.io", 1000)
+
+	err = svc.Send(nil, &flows.Email{To: []string{"bob@nyaruka.com"}, Subject: "Updates", Body: "Hi"}, httpLogger.Log)
+	assert.EqualError(t, err, "send request failed: The provided authorization grant is invalid, expired, or revoked")
+	assert.Equal(t, flows.CallStatusResponseError, httpLogger.Logs[len(httpLogger.Logs)-1].Status)
+}
@@ -1,34 +1,57 @@
 package smtp
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/developc3ntro/omni-goflow/flows"
+	emails "github.com/developc3ntro/omni-goflow/services/email"
 	"github.com/developc3ntro/omni-goflow/utils/smtpx"
+	"github.com/nyaruka/gocommon/httpx"
 )
 
 type service struct {
-	client  *smtpx.Client
-	retries *smtpx.RetryConfig
+	client             *smtpx.Client
+	retries            *smtpx.RetryConfig
+	httpClient         *http.Client
+	httpAccess         *httpx.AccessConfig
+	maxAttachmentBytes int
 }
 
-// NewService creates a new SMTP email service
-func NewService(smtpURL string, retries *smtpx.RetryConfig) (flows.EmailService, error) {
+// NewService creates a new SMTP email service. Attachments are fetched from their URLs using the given
+// HTTP client and access config, and are rejected if they exceed maxAttachmentBytes.
+func NewService(smtpURL string, retries *smtpx.RetryConfig, httpClient *http.Client, httpAccess *httpx.AccessConfig, maxAttachmentBytes int) (flows.EmailService, error) {
 	c, err := smtpx.NewClientFromURL(smtpURL)
 	if err != nil {
 		return nil, err
 	}
 
-	return &service{client: c, retries: retries}, nil
+	return &service{client: c, retries: retries, httpClient: httpClient, httpAccess: httpAccess, maxAttachmentBytes: maxAttachmentBytes}, nil
 }
 
-func (s *service) Send(session flows.Session, addresses []string, subject, body string) error {
+func (s *service) Send(session flows.Session, email *flows.Email, logHTTP flows.HTTPLogCallback) error {
+	body := email.Body
+
 	// sending blank emails is a good way to get flagged as a spammer so use placeholder if body is empty
 	if strings.TrimSpace(body) == "" {
 		body = "(empty body)"
 	}
 
-	m := smtpx.NewMessage(addresses, subject, body, "")
+	fetched, err := emails.FetchAttachments(s.httpClient, s.httpAccess, email.Attachments, s.maxAttachmentBytes, logHTTP)
+	if err != nil {
+		return err
+	}
+
+	attachments := make([]*smtpx.Attachment, len(fetched))
+	for i, a := range fetched {
+		attachments[i] = smtpx.NewAttachment(a.Filename, a.ContentType, a.Content)
+	}
+
+	m := smtpx.NewMessage(email.To, email.Subject, body, email.HTML).
+		WithCc(email.Cc...).
+		WithBcc(email.Bcc...).
+		WithReplyTo(email.ReplyTo).
+		WithAttachments(attachments...)
 
 	return smtpx.Send(s.client, m, s.retries)
 }
@@ -0,0 +1,152 @@
+package helpdesk
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/httpx"
+	"github.com/nyaruka/gocommon/jsonx"
+)
+
+// Client is a client for a generic JSON-over-HTTP helpdesk API
+type Client struct {
+	httpClient  *http.Client
+	httpRetries *httpx.RetryConfig
+	baseURL     string
+	token       string
+}
+
+// NewClient creates a new helpdesk client
+func NewClient(httpClient *http.Client, httpRetries *httpx.RetryConfig, baseURL, token string) *Client {
+	return &Client{
+		httpClient:  httpClient,
+		httpRetries: httpRetries,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		token:       token,
+	}
+}
+
+// error response contains an error message when a request fails
+type errorResponse struct {
+	Error_ string `json:"error"`
+}
+
+func (e *errorResponse) Error() string {
+	return e.Error_
+}
+
+// Requester is the contact who a ticket is being opened for
+type Requester struct {
+	UUID string `json:"uuid"`
+	Name string `json:"name,omitempty"`
+	URN  string `json:"urn,omitempty"`
+}
+
+// TicketRequest is the payload to open a new ticket
+type TicketRequest struct {
+	ExternalRef string     `json:"external_ref"`
+	Subject     string     `json:"subject,omitempty"`
+	Body        string     `json:"body"`
+	Assignee    string     `json:"assignee,omitempty"`
+	Requester   *Requester `json:"requester,omitempty"`
+}
+
+// Ticket is a ticket as returned by the helpdesk
+type Ticket struct {
+	ID          string `json:"id" validate:"required"`
+	ExternalRef string `json:"external_ref"`
+	Status      string `json:"status"`
+}
+
+// NoteRequest is the payload to add a note to a ticket
+type NoteRequest struct {
+	Note string `json:"note"`
+}
+
+// AssignRequest is the payload to assign a ticket
+type AssignRequest struct {
+	Assignee string `json:"assignee"`
+}
+
+// CreateTicket opens a new ticket
+func (c *Client) CreateTicket(ticket *TicketRequest) (*Ticket, *httpx.Trace, error) {
+	response := &Ticket{}
+
+	trace, err := c.request("POST", "tickets", ticket, response)
+	if err != nil {
+		return nil, trace, err
+	}
+
+	return response, trace, nil
+}
+
+// CloseTicket closes the ticket with the given ID
+func (c *Client) CloseTicket(id string) (*httpx.Trace, error) {
+	return c.request("POST", fmt.Sprintf("tickets/%s/close", url.PathEscape(id)), nil, nil)
+}
+
+// ReopenTicket reopens the ticket with the given ID
+func (c *Client) ReopenTicket(id string) (*httpx.Trace, error) {
+	return c.request("POST", fmt.Sprintf("tickets/%s/reopen", url.PathEscape(id)), nil, nil)
+}
+
+// AddNote adds an internal note to the ticket with the given ID
+func (c *Client) AddNote(id, note string) (*httpx.Trace, error) {
+	payload := &NoteRequest{Note: note}
+
+	return c.request("POST", fmt.Sprintf("tickets/%s/notes", url.PathEscape(id)), payload, nil)
+}
+
+// AssignTicket assigns the ticket with the given ID to the user with the given email address
+func (c *Client) AssignTicket(id, assignee string) (*httpx.Trace, error) {
+	payload := &AssignRequest{Assignee: assignee}
+
+	return c.request("POST", fmt.Sprintf("tickets/%s/assign", url.PathEscape(id)), payload, nil)
+}
+
+func (c *Client) request(method, endpoint string, payload interface{}, response interface{}) (*httpx.Trace, error) {
+	endpointURL := fmt.Sprintf("%s/%s", c.baseURL, endpoint)
+	headers := map[string]string{}
+	var body io.Reader
+
+	if c.token != "" {
+		headers["Authorization"] = fmt.Sprintf("Bearer %s", c.token)
+	}
+
+	if payload != nil {
+		data, err := jsonx.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+		headers["Content-Type"] = "application/json"
+	}
+
+	req, err := httpx.NewRequest(method, endpointURL, body, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	trace, err := httpx.DoTrace(c.httpClient, req, c.httpRetries, nil, -1)
+	if err != nil {
+		return trace, err
+	}
+
+	if trace.Response.StatusCode >= 400 {
+		response := &errorResponse{}
+		if err := jsonx.Unmarshal(trace.ResponseBody, response); err != nil || response.Error_ == "" {
+			return trace, fmt.Errorf("helpdesk API request failed with status %d", trace.Response.StatusCode)
+		}
+		return trace, response
+	}
+
+	if response != nil {
+		return trace, utils.UnmarshalAndValidate(trace.ResponseBody, response)
+	}
+	return trace, nil
+}
@@ -0,0 +1,52 @@
+package helpdesk_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/services/tickets/helpdesk"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/httpx"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTicket(t *testing.T) {
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"https://help.example.com/api/tickets": {
+			httpx.MockConnectionError,
+			httpx.NewMockResponse(400, nil, `{"error": "ticket body is required"}`),
+			httpx.NewMockResponse(500, nil, `Internal Server Error`),
+			httpx.NewMockResponse(201, nil, `{}`), // missing ID
+			httpx.NewMockResponse(201, nil, `{"id": "123", "external_ref": "e7187099-7d38-4f60-955c-325957214c42", "status": "open"}`),
+		},
+	}))
+
+	client := helpdesk.NewClient(http.DefaultClient, nil, "https://help.example.com/api/", "sesame")
+	request := &helpdesk.TicketRequest{
+		ExternalRef: "e7187099-7d38-4f60-955c-325957214c42",
+		Subject:     "Computers",
+		Body:        "My computer is broken",
+		Requester:   &helpdesk.Requester{UUID: "5d76d86b-3bb9-4d5a-b822-c9d86f5d8e4f", Name: "Bob", URN: "tel:+12065551212"},
+	}
+
+	_, _, err := client.CreateTicket(request)
+	assert.EqualError(t, err, "unable to connect to server")
+
+	_, trace, err := client.CreateTicket(request)
+	assert.EqualError(t, err, "ticket body is required")
+	assert.Equal(t, 400, trace.Response.StatusCode)
+
+	_, _, err = client.CreateTicket(request)
+	assert.EqualError(t, err, "helpdesk API request failed with status 500")
+
+	_, _, err = client.CreateTicket(request)
+	assert.EqualError(t, err, "field 'id' is required")
+
+	ticket, trace, err := client.CreateTicket(request)
+	assert.NoError(t, err)
+	assert.Equal(t, &helpdesk.Ticket{ID: "123", ExternalRef: "e7187099-7d38-4f60-955c-325957214c42", Status: "open"}, ticket)
+	test.AssertSnapshot(t, "create_ticket_request", string(trace.RequestTrace))
+}
@@ -0,0 +1,145 @@
+package helpdesk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// MockTicket is a ticket stored by a mock helpdesk server
+type MockTicket struct {
+	ID       string
+	Request  *TicketRequest
+	Status   string
+	Assignee string
+	Notes    []string
+}
+
+// MockServer is an in-memory implementation of the helpdesk API backed by an httptest server. It's
+// useful for testing and for running flows locally without a real helpdesk.
+type MockServer struct {
+	*httptest.Server
+
+	token   string
+	mutex   sync.Mutex
+	tickets []*MockTicket
+}
+
+// NewMockServer creates and starts a new mock helpdesk server which requires the given bearer token
+// if it's non-empty. Callers should call Close when done with it.
+func NewMockServer(token string) *MockServer {
+	s := &MockServer{token: token}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tickets", s.handleTickets)
+	mux.HandleFunc("/tickets/", s.handleTicket)
+
+	s.Server = httptest.NewServer(s.authenticate(mux))
+	return s
+}
+
+// Tickets returns the tickets stored by this server
+func (s *MockServer) Tickets() []*MockTicket {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tickets := make([]*MockTicket, len(s.tickets))
+	for i, t := range s.tickets {
+		cloned := *t
+		cloned.Notes = append([]string(nil), t.Notes...)
+		tickets[i] = &cloned
+	}
+	return tickets
+}
+
+func (s *MockServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" && r.Header.Get("Authorization") != "Bearer "+s.token {
+			writeJSON(w, http.StatusUnauthorized, &errorResponse{Error_: "invalid token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *MockServer) handleTickets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error_: "method not allowed"})
+		return
+	}
+
+	request := &TicketRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error_: "invalid request body"})
+		return
+	}
+	if strings.TrimSpace(request.Body) == "" {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error_: "ticket body is required"})
+		return
+	}
+
+	s.mutex.Lock()
+	ticket := &MockTicket{ID: fmt.Sprint(len(s.tickets) + 1), Request: request, Status: "open", Assignee: request.Assignee}
+	s.tickets = append(s.tickets, ticket)
+	s.mutex.Unlock()
+
+	writeJSON(w, http.StatusCreated, &Ticket{ID: ticket.ID, ExternalRef: request.ExternalRef, Status: ticket.Status})
+}
+
+// handles updates to existing tickets, i.e. POST /tickets/{id}/{close|reopen|notes|assign}
+func (s *MockServer) handleTicket(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/tickets/"), "/")
+	if len(parts) != 2 || r.Method != http.MethodPost {
+		writeJSON(w, http.StatusNotFound, &errorResponse{Error_: "not found"})
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var ticket *MockTicket
+	for _, t := range s.tickets {
+		if t.ID == parts[0] {
+			ticket = t
+		}
+	}
+	if ticket == nil {
+		writeJSON(w, http.StatusNotFound, &errorResponse{Error_: "no such ticket"})
+		return
+	}
+
+	switch parts[1] {
+	case "close":
+		ticket.Status = "closed"
+	case "reopen":
+		ticket.Status = "open"
+	case "notes":
+		request := &NoteRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil || request.Note == "" {
+			writeJSON(w, http.StatusBadRequest, &errorResponse{Error_: "note is required"})
+			return
+		}
+		ticket.Notes = append(ticket.Notes, request.Note)
+	case "assign":
+		request := &AssignRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			writeJSON(w, http.StatusBadRequest, &errorResponse{Error_: "invalid request body"})
+			return
+		}
+		ticket.Assignee = request.Assignee
+	default:
+		writeJSON(w, http.StatusNotFound, &errorResponse{Error_: "not found"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &Ticket{ID: ticket.ID, ExternalRef: ticket.Request.ExternalRef, Status: ticket.Status})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,102 @@
+package helpdesk
+
+import (
+	"net/http"
+
+	"github.com/developc3ntro/omni-goflow/envs"
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/httpx"
+
+	"github.com/pkg/errors"
+)
+
+// a ticket service implementation for a generic JSON-over-HTTP helpdesk
+type service struct {
+	client   *Client
+	ticketer *flows.Ticketer
+	redactor utils.Redactor
+}
+
+// NewService creates a new helpdesk ticket service
+func NewService(httpClient *http.Client, httpRetries *httpx.RetryConfig, ticketer *flows.Ticketer, baseURL, token string) flows.TicketService {
+	return &service{
+		client:   NewClient(httpClient, httpRetries, baseURL, token),
+		ticketer: ticketer,
+		redactor: utils.NewRedactor(flows.RedactionMask, token),
+	}
+}
+
+// Open opens a ticket which for this ticketer means creating a new ticket in the helpdesk
+func (s *service) Open(session flows.Session, topic *flows.Topic, body string, assignee *flows.User, logHTTP flows.HTTPLogCallback) (*flows.Ticket, error) {
+	ticket := flows.OpenTicket(s.ticketer, topic, body, assignee)
+
+	request := &TicketRequest{
+		ExternalRef: string(ticket.UUID()),
+		Body:        body,
+	}
+	if topic != nil {
+		request.Subject = topic.Name()
+	}
+	if assignee != nil {
+		request.Assignee = assignee.Email()
+	}
+
+	if contact := session.Contact(); contact != nil {
+		request.Requester = &Requester{UUID: string(contact.UUID()), Name: contact.Name()}
+
+		// only share the contact's URN if the environment allows it
+		if urn := contact.PreferredURN(); urn != nil && session.Environment().RedactionPolicy() != envs.RedactionPolicyURNs {
+			request.Requester.URN = urn.URN().Identity().String()
+		}
+	}
+
+	created, trace, err := s.client.CreateTicket(request)
+	if trace != nil {
+		logHTTP(flows.NewHTTPLog(trace, flows.HTTPStatusFromCode, s.redactor))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ticket.SetExternalID(created.ID)
+	return ticket, nil
+}
+
+// Close closes the ticket in the helpdesk
+func (s *service) Close(session flows.Session, ticket *flows.Ticket, logHTTP flows.HTTPLogCallback) error {
+	return s.update(ticket, logHTTP, s.client.CloseTicket)
+}
+
+// Reopen reopens the ticket in the helpdesk
+func (s *service) Reopen(session flows.Session, ticket *flows.Ticket, logHTTP flows.HTTPLogCallback) error {
+	return s.update(ticket, logHTTP, s.client.ReopenTicket)
+}
+
+// AddNote adds an internal note to the ticket in the helpdesk
+func (s *service) AddNote(session flows.Session, ticket *flows.Ticket, note string, logHTTP flows.HTTPLogCallback) error {
+	return s.update(ticket, logHTTP, func(id string) (*httpx.Trace, error) {
+		return s.client.AddNote(id, note)
+	})
+}
+
+// Assign assigns the ticket in the helpdesk to the given user
+func (s *service) Assign(session flows.Session, ticket *flows.Ticket, assignee *flows.User, logHTTP flows.HTTPLogCallback) error {
+	return s.update(ticket, logHTTP, func(id string) (*httpx.Trace, error) {
+		return s.client.AssignTicket(id, assignee.Email())
+	})
+}
+
+func (s *service) update(ticket *flows.Ticket, logHTTP flows.HTTPLogCallback, fn func(string) (*httpx.Trace, error)) error {
+	if ticket.ExternalID() == "" {
+		return errors.Errorf("ticket %s has no external ID", ticket.UUID())
+	}
+
+	trace, err := fn(ticket.ExternalID())
+	if trace != nil {
+		logHTTP(flows.NewHTTPLog(trace, flows.HTTPStatusFromCode, s.redactor))
+	}
+	return err
+}
+
+var _ flows.TicketService = (*service)(nil)
@@ -0,0 +1,109 @@
+package helpdesk_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/services/tickets/helpdesk"
+	"github.com/developc3ntro/omni-goflow/test"
+	"github.com/nyaruka/gocommon/uuids"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	session, _, err := test.NewSessionBuilder().Build()
+	require.NoError(t, err)
+
+	server := helpdesk.NewMockServer("sesame")
+	defer server.Close()
+
+	ticketer := session.Assets().Ticketers().Get("19dc6346-9623-4fe4-be80-538d493ecdf5")
+	topic := session.Assets().Topics().Get("daa356b6-32af-44f0-9d35-6126d55ec3e9")
+	assignee := session.Assets().Users().Get("bob@nyaruka.com")
+
+	svc := helpdesk.NewService(http.DefaultClient, nil, ticketer, server.URL, "sesame")
+
+	httpLogger := &flows.HTTPLogger{}
+
+	ticket, err := svc.Open(session, topic, "My computer is broken", assignee, httpLogger.Log)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", ticket.ExternalID())
+	assert.Equal(t, ticketer, ticket.Ticketer())
+	assert.Equal(t, topic, ticket.Topic())
+	assert.Equal(t, "My computer is broken", ticket.Body())
+	assert.Equal(t, assignee, ticket.Assignee())
+
+	tickets := server.Tickets()
+	assert.Equal(t, 1, len(tickets))
+	assert.Equal(t, "open", tickets[0].Status)
+	assert.Equal(t, string(ticket.UUID()), tickets[0].Request.ExternalRef)
+	assert.Equal(t, "Computers", tickets[0].Request.Subject)
+	assert.Equal(t, "bob@nyaruka.com", tickets[0].Request.Assignee)
+	assert.Equal(t, &helpdesk.Requester{UUID: string(session.Contact().UUID()), Name: "Bob", URN: "tel:+12065551212"}, tickets[0].Request.Requester)
+
+	assert.Equal(t, 1, len(httpLogger.Logs))
+	assert.Equal(t, server.URL+"/tickets", httpLogger.Logs[0].URL)
+	assert.Equal(t, flows.CallStatusSuccess, httpLogger.Logs[0].Status)
+	assert.Contains(t, httpLogger.Logs[0].Request, "Authorization: Bearer ****************")
+	assert.False(t, strings.Contains(httpLogger.Logs[0].Request, "sesame"))
+
+	// ticket can then be updated
+	bob := session.Assets().Users().Get("bob@nyaruka.com")
+	httpLogger = &flows.HTTPLogger{}
+
+	assert.NoError(t, svc.AddNote(session, ticket, "Customer was very polite", httpLogger.Log))
+	assert.NoError(t, svc.Assign(session, ticket, bob, httpLogger.Log))
+	assert.NoError(t, svc.Close(session, ticket, httpLogger.Log))
+
+	tickets = server.Tickets()
+	assert.Equal(t, "closed", tickets[0].Status)
+	assert.Equal(t, []string{"Customer was very polite"}, tickets[0].Notes)
+	assert.Equal(t, "bob@nyaruka.com", tickets[0].Assignee)
+
+	assert.NoError(t, svc.Reopen(session, ticket, httpLogger.Log))
+	assert.Equal(t, "open", server.Tickets()[0].Status)
+
+	assert.Equal(t, 4, len(httpLogger.Logs))
+	assert.Equal(t, server.URL+"/tickets/1/notes", httpLogger.Logs[0].URL)
+	assert.Equal(t, server.URL+"/tickets/1/assign", httpLogger.Logs[1].URL)
+	assert.Equal(t, server.URL+"/tickets/1/close", httpLogger.Logs[2].URL)
+	assert.Equal(t, server.URL+"/tickets/1/reopen", httpLogger.Logs[3].URL)
+
+	// tickets which weren't opened in the helpdesk can't be updated
+	unsynced := flows.OpenTicket(ticketer, topic, "Help!", nil)
+	assert.EqualError(t, svc.Close(session, unsynced, httpLogger.Log), fmt.Sprintf("ticket %s has no external ID", unsynced.UUID()))
+
+	// and updates to tickets the helpdesk doesn't know about fail
+	unknown := flows.NewTicket(flows.TicketUUID(uuids.New()), ticketer, topic, "Help!", "234", nil)
+	assert.EqualError(t, svc.Close(session, unknown, httpLogger.Log), "no such ticket")
+
+	// server rejects tickets with empty bodies
+	httpLogger = &flows.HTTPLogger{}
+
+	ticket, err = svc.Open(session, nil, " ", nil, httpLogger.Log)
+	assert.EqualError(t, err, "ticket body is required")
+	assert.Nil(t, ticket)
+	assert.Equal(t, 1, len(httpLogger.Logs))
+	assert.Equal(t, flows.CallStatusResponseError, httpLogger.Logs[0].Status)
+
+	// and requests with the wrong token
+	svc = helpdesk.NewService(http.DefaultClient, nil, ticketer, server.URL, "opensesame")
+
+	_, err = svc.Open(session, nil, "Help!", nil, httpLogger.Log)
+	assert.EqualError(t, err, "invalid token")
+
+	// and with no token, in which case there's nothing to redact in the logs
+	svc = helpdesk.NewService(http.DefaultClient, nil, ticketer, server.URL, "")
+	httpLogger = &flows.HTTPLogger{}
+
+	_, err = svc.Open(session, nil, "Help!", nil, httpLogger.Log)
+	assert.EqualError(t, err, "invalid token")
+	assert.Equal(t, 1, len(httpLogger.Logs))
+	assert.Contains(t, httpLogger.Logs[0].Request, "POST /tickets HTTP/1.1")
+	assert.NotContains(t, httpLogger.Logs[0].Request, "*")
+}
@@ -0,0 +1,91 @@
+package webhooks
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/gocommon/httpx"
+)
+
+// CircuitBreaker tracks failures of calls to each host, shared by all sessions. Once a host has failed the
+// threshold number of times in a row, its circuit opens and calls to it are skipped without a request being
+// made until the cooldown has passed. After that a single trial call is allowed which closes the circuit if
+// it succeeds or re-opens it if it fails.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mutex sync.Mutex
+	hosts map[string]*circuit
+}
+
+type circuit struct {
+	failures int
+	openedOn time.Time
+	trialing bool
+}
+
+// NewCircuitBreaker creates a new circuit breaker
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, hosts: make(map[string]*circuit)}
+}
+
+// IsOpen returns whether the circuit for the given host is currently open
+func (b *CircuitBreaker) IsOpen(host string) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c := b.hosts[strings.ToLower(host)]
+	return c != nil && c.isOpen(b.threshold) && dates.Now().Before(c.openedOn.Add(b.cooldown))
+}
+
+// checks whether a call to the given host is allowed
+func (b *CircuitBreaker) allow(host string) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c := b.hosts[strings.ToLower(host)]
+	if c == nil || !c.isOpen(b.threshold) {
+		return true
+	}
+
+	// once the cooldown has passed, let a single call through to test the host
+	if !c.trialing && !dates.Now().Before(c.openedOn.Add(b.cooldown)) {
+		c.trialing = true
+		return true
+	}
+	return false
+}
+
+// records the result of a call to the given host
+func (b *CircuitBreaker) record(host string, trace *httpx.Trace) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	host = strings.ToLower(host)
+	c := b.hosts[host]
+
+	// connection errors and server errors count as failures, but client errors mean the host is up
+	failed := trace == nil || trace.Response == nil || trace.Response.StatusCode >= 500
+	if !failed {
+		delete(b.hosts, host)
+		return
+	}
+
+	if c == nil {
+		c = &circuit{}
+		b.hosts[host] = c
+	}
+
+	c.failures++
+	c.trialing = false
+	if c.isOpen(b.threshold) {
+		c.openedOn = dates.Now()
+	}
+}
+
+func (c *circuit) isOpen(threshold int) bool {
+	return c.failures >= threshold
+}
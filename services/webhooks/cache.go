@@ -0,0 +1,185 @@
+package webhooks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/nyaruka/gocommon/httpx"
+)
+
+// ResponseCache is an in-memory cache of successful webhook responses, shared by all sessions, which lets
+// identical calls (e.g. from a broadcast) avoid hitting the same endpoint repeatedly. Only GET and HEAD
+// requests are cached, keyed by method, URL, headers and body, so that calls with different credentials never
+// share responses. Responses are cached for the default TTL unless
+// their Cache-Control header specifies a max-age or forbids caching.
+type ResponseCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mutex   sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	trace     *httpx.Trace
+	expiresOn time.Time
+}
+
+// NewResponseCache creates a new response cache with the given default TTL and maximum number of entries
+func NewResponseCache(ttl time.Duration, maxEntries int) *ResponseCache {
+	return &ResponseCache{ttl: ttl, maxEntries: maxEntries, entries: make(map[string]*cacheEntry)}
+}
+
+// Len returns the number of unexpired entries in the cache
+func (c *ResponseCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.removeExpired(dates.Now())
+	return len(c.entries)
+}
+
+// looks up a cached trace for the given request, returning nil if there isn't one
+func (c *ResponseCache) get(request *http.Request, key string) *httpx.Trace {
+	if !isCacheable(request) || hasCacheDirective(request.Header, "no-cache", "no-store") {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry := c.entries[key]
+	if entry == nil || !dates.Now().Before(entry.expiresOn) {
+		return nil
+	}
+	return entry.trace
+}
+
+// caches the given trace if the request and response allow it
+func (c *ResponseCache) put(request *http.Request, key string, trace *httpx.Trace) {
+	if !isCacheable(request) || hasCacheDirective(request.Header, "no-store") {
+		return
+	}
+	if trace.Response == nil || trace.Response.StatusCode/100 != 2 {
+		return
+	}
+	if hasCacheDirective(trace.Response.Header, "no-store", "no-cache", "private") {
+		return
+	}
+
+	ttl := c.ttl
+	if maxAge, found := cacheMaxAge(trace.Response.Header); found {
+		ttl = maxAge
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	now := dates.Now()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.entries) >= c.maxEntries {
+		c.removeExpired(now)
+	}
+	if len(c.entries) >= c.maxEntries {
+		c.removeOldest()
+	}
+
+	c.entries[key] = &cacheEntry{trace: trace, expiresOn: now.Add(ttl)}
+}
+
+func (c *ResponseCache) removeExpired(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresOn) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *ResponseCache) removeOldest() {
+	var oldestKey string
+	var oldest *cacheEntry
+	for key, entry := range c.entries {
+		if oldest == nil || entry.expiresOn.Before(oldest.expiresOn) {
+			oldestKey, oldest = key, entry
+		}
+	}
+	delete(c.entries, oldestKey)
+}
+
+func isCacheable(request *http.Request) bool {
+	return request.Method == http.MethodGet || request.Method == http.MethodHead
+}
+
+// creates the cache key for the given request which should be created before any auth or signing headers are added,
+// as those are the same for all requests to a host, or depend on the time. Headers set by the flow are included as
+// they might be credentials, e.g. Authorization, except for Cache-Control which is a directive for the cache itself.
+func cacheKey(request *http.Request, body []byte) string {
+	names := make([]string, 0, len(request.Header))
+	for name := range request.Header {
+		if name != "Cache-Control" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		for _, value := range request.Header[name] {
+			hash.Write([]byte(name + ": " + value + "\n"))
+		}
+	}
+	hash.Write([]byte("\n"))
+	hash.Write(body)
+
+	return request.Method + " " + request.URL.String() + " " + hex.EncodeToString(hash.Sum(nil))
+}
+
+// parses the Cache-Control header into its directives, e.g. {"max-age": "60", "public": ""}
+func cacheDirectives(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			parts := strings.SplitN(strings.TrimSpace(directive), "=", 2)
+			name := strings.ToLower(parts[0])
+			if len(parts) == 2 {
+				directives[name] = strings.Trim(parts[1], `"`)
+			} else if name != "" {
+				directives[name] = ""
+			}
+		}
+	}
+	return directives
+}
+
+func hasCacheDirective(header http.Header, names ...string) bool {
+	directives := cacheDirectives(header)
+	for _, name := range names {
+		if _, found := directives[name]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// gets the max age from the Cache-Control header, preferring s-maxage as this is a shared cache
+func cacheMaxAge(header http.Header) (time.Duration, bool) {
+	directives := cacheDirectives(header)
+	for _, name := range []string{"s-maxage", "max-age"} {
+		if value, found := directives[name]; found {
+			if seconds, err := strconv.Atoi(value); err == nil {
+				return time.Duration(seconds) * time.Second, true
+			}
+		}
+	}
+	return 0, false
+}
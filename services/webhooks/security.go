@@ -0,0 +1,197 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/dates"
+	"github.com/pkg/errors"
+)
+
+// AuthType is the type of an auth profile
+type AuthType string
+
+// possible values for auth types
+const (
+	AuthTypeBasic  AuthType = "basic"
+	AuthTypeBearer AuthType = "bearer"
+	AuthTypeHeader AuthType = "header"
+)
+
+// AuthProfile is a set of credentials which are added to all requests to a host. These are configured on
+// the service rather than in flow definitions so that secrets never appear in flows.
+type AuthProfile struct {
+	Type     AuthType
+	Username string
+	Password string
+	Token    string
+	Header   string
+	Value    string
+}
+
+// NewBasicAuth creates a new auth profile which uses basic authentication
+func NewBasicAuth(username, password string) *AuthProfile {
+	return &AuthProfile{Type: AuthTypeBasic, Username: username, Password: password}
+}
+
+// NewBearerAuth creates a new auth profile which uses a bearer token
+func NewBearerAuth(token string) *AuthProfile {
+	return &AuthProfile{Type: AuthTypeBearer, Token: token}
+}
+
+// NewHeaderAuth creates a new auth profile which sets a custom header, e.g. X-API-Key
+func NewHeaderAuth(header, value string) *AuthProfile {
+	return &AuthProfile{Type: AuthTypeHeader, Header: header, Value: value}
+}
+
+// applies this profile to the given request unless the request already sets the same header
+func (p *AuthProfile) apply(request *http.Request) {
+	switch p.Type {
+	case AuthTypeBasic:
+		if request.Header.Get("Authorization") == "" {
+			request.SetBasicAuth(p.Username, p.Password)
+		}
+	case AuthTypeBearer:
+		if request.Header.Get("Authorization") == "" {
+			request.Header.Set("Authorization", "Bearer "+p.Token)
+		}
+	case AuthTypeHeader:
+		if request.Header.Get(p.Header) == "" {
+			request.Header.Set(p.Header, p.Value)
+		}
+	}
+}
+
+// returns the secret values of this profile which should be redacted
+func (p *AuthProfile) secrets() []string {
+	switch p.Type {
+	case AuthTypeBasic:
+		return []string{base64.StdEncoding.EncodeToString([]byte(p.Username + ":" + p.Password)), p.Password}
+	case AuthTypeBearer:
+		return []string{p.Token}
+	case AuthTypeHeader:
+		return []string{p.Value}
+	}
+	return nil
+}
+
+// default names of the headers added to signed requests
+const (
+	DefaultSignatureHeader = "X-Webhook-Signature"
+	DefaultTimestampHeader = "X-Webhook-Timestamp"
+)
+
+// Signing configures HMAC-SHA256 signing of request bodies. The signature is calculated over the timestamp
+// header value, a period and the request body, i.e. HMAC(secret, "1570461690.{...}"), and is sent hex encoded
+// as "sha256=<signature>" so that receivers can verify requests and reject old ones.
+type Signing struct {
+	Secret          string
+	SignatureHeader string
+	TimestampHeader string
+}
+
+// NewSigning creates a new signing configuration which uses the default header names
+func NewSigning(secret string) *Signing {
+	return &Signing{Secret: secret, SignatureHeader: DefaultSignatureHeader, TimestampHeader: DefaultTimestampHeader}
+}
+
+// Sign calculates the signature for the given timestamp and body
+func (s *Signing) Sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// signs the given request, replacing its body so that it can still be read
+func (s *Signing) apply(request *http.Request) error {
+	body, err := readBody(request)
+	if err != nil {
+		return err
+	}
+
+	timestamp := fmt.Sprint(dates.Now().Unix())
+
+	request.Header.Set(s.TimestampHeader, timestamp)
+	request.Header.Set(s.SignatureHeader, s.Sign(timestamp, body))
+	return nil
+}
+
+// Security configures how requests are authenticated to their receivers. Auth profiles are keyed by lowercase
+// host name, e.g. api.example.com, and apply to requests to any port on that host.
+type Security struct {
+	Signing  *Signing
+	Profiles map[string]*AuthProfile
+}
+
+// applies this security configuration to the given request
+func (s *Security) apply(request *http.Request) error {
+	if profile := s.Profiles[strings.ToLower(request.URL.Hostname())]; profile != nil {
+		profile.apply(request)
+	}
+	if s.Signing != nil {
+		return s.Signing.apply(request)
+	}
+	return nil
+}
+
+// returns a copy of the given client which doesn't send the custom headers of auth profiles when redirected to a
+// different host, like the client itself does for the Authorization header
+func (s *Security) client(httpClient *http.Client) *http.Client {
+	headers := make([]string, 0, len(s.Profiles))
+	for _, profile := range s.Profiles {
+		if profile.Type == AuthTypeHeader {
+			headers = append(headers, profile.Header)
+		}
+	}
+	if len(headers) == 0 {
+		return httpClient
+	}
+
+	checkRedirect := httpClient.CheckRedirect
+	wrapped := *httpClient
+	wrapped.CheckRedirect = func(request *http.Request, via []*http.Request) error {
+		if !strings.EqualFold(request.URL.Hostname(), via[0].URL.Hostname()) {
+			for _, h := range headers {
+				request.Header.Del(h)
+			}
+		}
+
+		if checkRedirect != nil {
+			return checkRedirect(request, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects") // same as the default policy
+		}
+		return nil
+	}
+	return &wrapped
+}
+
+// creates a redactor which masks all the secrets in this security configuration
+func (s *Security) redactor() utils.Redactor {
+	secrets := make([]string, 0, len(s.Profiles)+1)
+	if s.Signing != nil {
+		secrets = append(secrets, s.Signing.Secret)
+	}
+	for _, profile := range s.Profiles {
+		secrets = append(secrets, profile.secrets()...)
+	}
+
+	// an empty value would be matched everywhere
+	nonEmpty := secrets[:0]
+	for _, secret := range secrets {
+		if secret != "" {
+			nonEmpty = append(nonEmpty, secret)
+		}
+	}
+	return utils.NewRedactor(flows.RedactionMask, nonEmpty...)
+}
@@ -3,11 +3,14 @@ package webhooks
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
+	"net/http/httputil"
 
 	"github.com/developc3ntro/omni-goflow/flows"
 	"github.com/developc3ntro/omni-goflow/flows/engine"
 	"github.com/developc3ntro/omni-goflow/utils"
+	"github.com/nyaruka/gocommon/dates"
 	"github.com/nyaruka/gocommon/httpx"
 )
 
@@ -17,24 +20,56 @@ type service struct {
 	httpAccess     *httpx.AccessConfig
 	defaultHeaders map[string]string
 	maxBodyBytes   int
+	security       *Security
+	redactor       utils.Redactor
+	cache          *ResponseCache
+	breaker        *CircuitBreaker
 }
 
-// NewServiceFactory creates a new webhook service factory
-func NewServiceFactory(httpClient *http.Client, httpRetries *httpx.RetryConfig, httpAccess *httpx.AccessConfig, defaultHeaders map[string]string, maxBodyBytes int) engine.WebhookServiceFactory {
+// Option configures an optional feature of a webhook service
+type Option func(*service)
+
+// WithSecurity returns an option which signs and authenticates requests according to the given security config
+func WithSecurity(security *Security) Option {
+	return func(s *service) {
+		s.security = security
+		if security != nil {
+			s.redactor = security.redactor()
+		}
+	}
+}
+
+// WithCache returns an option which serves identical calls from the given response cache
+func WithCache(cache *ResponseCache) Option {
+	return func(s *service) { s.cache = cache }
+}
+
+// WithBreaker returns an option which skips calls to failing hosts according to the given circuit breaker
+func WithBreaker(breaker *CircuitBreaker) Option {
+	return func(s *service) { s.breaker = breaker }
+}
+
+// NewServiceFactory creates a new webhook service factory. Caches and circuit breakers passed as options are shared
+// by all sessions.
+func NewServiceFactory(httpClient *http.Client, httpRetries *httpx.RetryConfig, httpAccess *httpx.AccessConfig, defaultHeaders map[string]string, maxBodyBytes int, opts ...Option) engine.WebhookServiceFactory {
 	return func(flows.Session) (flows.WebhookService, error) {
-		return NewService(httpClient, httpRetries, httpAccess, defaultHeaders, maxBodyBytes), nil
+		return NewService(httpClient, httpRetries, httpAccess, defaultHeaders, maxBodyBytes, opts...), nil
 	}
 }
 
 // NewService creates a new default webhook service
-func NewService(httpClient *http.Client, httpRetries *httpx.RetryConfig, httpAccess *httpx.AccessConfig, defaultHeaders map[string]string, maxBodyBytes int) flows.WebhookService {
-	return &service{
+func NewService(httpClient *http.Client, httpRetries *httpx.RetryConfig, httpAccess *httpx.AccessConfig, defaultHeaders map[string]string, maxBodyBytes int, opts ...Option) flows.WebhookService {
+	s := &service{
 		httpClient:     httpClient,
 		httpRetries:    httpRetries,
 		httpAccess:     httpAccess,
 		defaultHeaders: defaultHeaders,
 		maxBodyBytes:   maxBodyBytes,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *service) Call(session flows.Session, request *http.Request) (*flows.WebhookCall, error) {
@@ -51,9 +86,52 @@ func (s *service) Call(session flows.Session, request *http.Request) (*flows.Web
 		request.Header.Del("Accept-Encoding")
 	}
 
-	trace, err := httpx.DoTrace(s.httpClient, request, s.httpRetries, s.httpAccess, s.maxBodyBytes)
+	var key string
+	if s.cache != nil {
+		body, err := readBody(request)
+		if err != nil {
+			return nil, err
+		}
+		key = cacheKey(request, body)
+	}
+
+	httpClient := s.httpClient
+	if s.security != nil {
+		if err := s.security.apply(request); err != nil {
+			return nil, err
+		}
+		httpClient = s.security.client(httpClient)
+	}
+
+	if s.cache != nil {
+		if cached := s.cache.get(request, key); cached != nil {
+			return s.newCall(cachedTrace(request, cached), true, false), nil
+		}
+	}
+
+	host := request.URL.Hostname()
+
+	// if this host is failing, skip the request and treat it like a connection error
+	if s.breaker != nil && !s.breaker.allow(host) {
+		trace, err := skippedTrace(request)
+		if err != nil {
+			return nil, err
+		}
+		return s.newCall(trace, false, true), nil
+	}
+
+	trace, err := httpx.DoTrace(httpClient, request, s.httpRetries, s.httpAccess, s.maxBodyBytes)
+
+	if s.breaker != nil {
+		s.breaker.record(host, trace)
+	}
+
 	if trace != nil {
-		call := &flows.WebhookCall{Trace: trace}
+		if s.cache != nil && err == nil {
+			s.cache.put(request, key, trace)
+		}
+
+		call := &flows.WebhookCall{Trace: trace, Redactor: s.redactor}
 
 		// throw away any error that happened prior to getting a response.. these will be surfaced to the user
 		// as connection_error status on the response
@@ -71,6 +149,55 @@ func (s *service) Call(session flows.Session, request *http.Request) (*flows.Web
 	return nil, err
 }
 
+func (s *service) newCall(trace *httpx.Trace, cached, circuitOpen bool) *flows.WebhookCall {
+	call := &flows.WebhookCall{Trace: trace, Cached: cached, CircuitOpen: circuitOpen, Redactor: s.redactor}
+	if len(call.ResponseBody) > 0 {
+		call.ResponseJSON, call.ResponseCleaned = ExtractJSON(call.ResponseBody)
+	}
+	return call
+}
+
+// creates a trace for a cache hit which reuses the cached response but takes no time
+func cachedTrace(request *http.Request, cached *httpx.Trace) *httpx.Trace {
+	now := dates.Now()
+	return &httpx.Trace{
+		Request:       request,
+		RequestTrace:  cached.RequestTrace,
+		Response:      cached.Response,
+		ResponseTrace: cached.ResponseTrace,
+		ResponseBody:  cached.ResponseBody,
+		StartTime:     now,
+		EndTime:       now,
+	}
+}
+
+// creates a trace for a request which was never made so has no response
+func skippedTrace(request *http.Request) (*httpx.Trace, error) {
+	requestTrace, err := httputil.DumpRequestOut(request, true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := dates.Now()
+	return &httpx.Trace{Request: request, RequestTrace: requestTrace, StartTime: now, EndTime: now}, nil
+}
+
+// reads the body of the given request, replacing it so that it can still be sent
+func readBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	request.Body.Close()
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	request.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	return body, nil
+}
+
 func ExtractJSON(body []byte) ([]byte, bool) {
 	// we make a best effort to turn the body into JSON, so we strip out:
 	//  1. any invalid UTF-8 sequences
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/developc3ntro/omni-goflow/envs"
 	"github.com/developc3ntro/omni-goflow/excellent/types"
 	"github.com/developc3ntro/omni-goflow/flows"
+	"github.com/developc3ntro/omni-goflow/flows/events"
 	"github.com/developc3ntro/omni-goflow/services/webhooks"
 	"github.com/developc3ntro/omni-goflow/test"
 	"github.com/nyaruka/gocommon/dates"
@@ -300,3 +302,314 @@ func TestWebhookResponseWithEscapes(t *testing.T) {
 	// check nothing became an escaped NULL
 	assert.NotContains(t, string(jsonx.MustMarshal(session)), `\u0000`)
 }
+
+func TestSecurity(t *testing.T) {
+	defer dates.SetNowSource(dates.DefaultNowSource)
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	dates.SetNowSource(dates.NewFixedNowSource(time.Date(2019, 10, 7, 15, 21, 30, 0, time.UTC)))
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"http://basic.example.com/hook": {
+			httpx.NewMockResponse(200, nil, `{"ok": true}`),
+		},
+		"https://bearer.example.com:8443/hook": {
+			httpx.NewMockResponse(200, nil, `{"token": "sesame-token"}`),
+		},
+		"http://custom.example.com/hook": {
+			httpx.NewMockResponse(200, nil, `{"ok": true}`),
+			httpx.NewMockResponse(200, nil, `{"ok": true}`),
+		},
+		"http://other.example.com/hook": {
+			httpx.NewMockResponse(200, nil, `{"ok": true}`),
+		},
+	}))
+
+	security := &webhooks.Security{
+		Signing: webhooks.NewSigning("s3cr3t"),
+		Profiles: map[string]*webhooks.AuthProfile{
+			"basic.example.com":  webhooks.NewBasicAuth("bob", "pa55word"),
+			"bearer.example.com": webhooks.NewBearerAuth("sesame-token"),
+			"custom.example.com": webhooks.NewHeaderAuth("X-API-Key", "key123"),
+		},
+	}
+	svc := webhooks.NewService(http.DefaultClient, nil, nil, map[string]string{"User-Agent": "goflow-testing"}, 10000, webhooks.WithSecurity(security))
+
+	call := func(method, url, body string, headers map[string]string) *flows.WebhookCall {
+		request, err := httpx.NewRequest(method, url, strings.NewReader(body), headers)
+		require.NoError(t, err)
+		c, err := svc.Call(nil, request)
+		require.NoError(t, err)
+		return c
+	}
+
+	// secrets are only redacted in what gets logged
+	logged := func(c *flows.WebhookCall) *flows.HTTPTrace {
+		return events.NewWebhookCalled(c, flows.CallStatusSuccess, "").HTTPTrace
+	}
+
+	signature := security.Signing.Sign("1570461690", []byte(`{"foo": "bar"}`))
+	assert.Equal(t, "sha256=de9f5dbb3fae8aec4ed3d79f6530273d4badc4154587d3762f6b1b81b9fa0258", signature)
+
+	// basic auth credentials are added and redacted
+	c := call("POST", "http://basic.example.com/hook", `{"foo": "bar"}`, nil)
+	assert.Equal(t, "POST /hook HTTP/1.1\r\nHost: basic.example.com\r\nUser-Agent: goflow-testing\r\nContent-Length: 14\r\nAuthorization: Basic ****************\r\nX-Webhook-Signature: "+signature+"\r\nX-Webhook-Timestamp: 1570461690\r\nAccept-Encoding: gzip\r\n\r\n{\"foo\": \"bar\"}", logged(c).Request)
+
+	// bearer tokens are added and redacted, even when echoed back in the response
+	c = call("POST", "https://bearer.example.com:8443/hook", `{"foo": "bar"}`, nil)
+	assert.Contains(t, logged(c).Request, "Authorization: Bearer ****************\r\n")
+	assert.Contains(t, logged(c).Response, `{"token": "****************"}`)
+	assert.NotContains(t, logged(c).Response, "sesame-token")
+
+	// but the response the flow sees isn't changed
+	assert.Equal(t, `{"token": "sesame-token"}`, string(c.ResponseBody))
+	assert.Equal(t, `{"token": "sesame-token"}`, string(c.ResponseJSON))
+
+	// custom headers are added and redacted
+	c = call("GET", "http://custom.example.com/hook", ``, nil)
+	assert.Equal(t, "GET /hook HTTP/1.1\r\nHost: custom.example.com\r\nUser-Agent: goflow-testing\r\nX-Api-Key: ****************\r\nX-Webhook-Signature: "+security.Signing.Sign("1570461690", nil)+"\r\nX-Webhook-Timestamp: 1570461690\r\nAccept-Encoding: gzip\r\n\r\n", logged(c).Request)
+
+	// unless the flow sets that header itself
+	c = call("GET", "http://custom.example.com/hook", ``, map[string]string{"X-API-Key": "flowkey"})
+	assert.Contains(t, logged(c).Request, "X-Api-Key: flowkey\r\n")
+
+	// hosts without a profile only get signatures
+	c = call("POST", "http://other.example.com/hook", `{"foo": "bar"}`, nil)
+	assert.Equal(t, "POST /hook HTTP/1.1\r\nHost: other.example.com\r\nUser-Agent: goflow-testing\r\nContent-Length: 14\r\nX-Webhook-Signature: "+signature+"\r\nX-Webhook-Timestamp: 1570461690\r\nAccept-Encoding: gzip\r\n\r\n{\"foo\": \"bar\"}", logged(c).Request)
+
+	// the signing secret itself is never logged
+	assert.NotContains(t, logged(c).Request, "s3cr3t")
+}
+
+func TestSecurityWithRedirects(t *testing.T) {
+	var received http.Header
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer target.Close()
+
+	// redirects either to the same host, or to a different host which is the same server by another name
+	redirector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/other" {
+			http.Redirect(w, r, strings.Replace(target.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+		} else {
+			http.Redirect(w, r, target.URL, http.StatusFound)
+		}
+	}))
+	defer redirector.Close()
+
+	security := &webhooks.Security{
+		Profiles: map[string]*webhooks.AuthProfile{
+			"127.0.0.1": webhooks.NewHeaderAuth("X-API-Key", "key123"),
+		},
+	}
+	svc := webhooks.NewService(http.DefaultClient, nil, nil, nil, 10000, webhooks.WithSecurity(security))
+
+	// redirected to a different host so the custom auth header isn't sent
+	request, _ := httpx.NewRequest("GET", redirector.URL+"/other", nil, nil)
+	c, err := svc.Call(nil, request)
+	require.NoError(t, err)
+	assert.Equal(t, 200, c.Response.StatusCode)
+	assert.Equal(t, "", received.Get("X-API-Key"))
+
+	// but it is sent when redirected to the same host
+	request, _ = httpx.NewRequest("GET", redirector.URL+"/same", nil, nil)
+	c, err = svc.Call(nil, request)
+	require.NoError(t, err)
+	assert.Equal(t, 200, c.Response.StatusCode)
+	assert.Equal(t, "key123", received.Get("X-API-Key"))
+}
+
+func TestResponseCache(t *testing.T) {
+	defer dates.SetNowSource(dates.DefaultNowSource)
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	t0 := time.Date(2019, 10, 7, 15, 21, 30, 0, time.UTC)
+	dates.SetNowSource(dates.NewFixedNowSource(t0))
+
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"http://api.example.com/weather": {
+			httpx.NewMockResponse(200, nil, `{"temp": 21}`),
+			httpx.NewMockResponse(200, nil, `{"temp": 22}`),
+			httpx.NewMockResponse(200, nil, `{"temp": 23}`),
+		},
+		"http://api.example.com/news": {
+			httpx.NewMockResponse(200, map[string]string{"Cache-Control": "public, max-age=10"}, `{"headline": "A"}`),
+			httpx.NewMockResponse(200, map[string]string{"Cache-Control": "no-store"}, `{"headline": "B"}`),
+			httpx.NewMockResponse(200, nil, `{"headline": "C"}`),
+		},
+		"http://api.example.com/error": {
+			httpx.NewMockResponse(500, nil, `{"error": "boom"}`),
+			httpx.NewMockResponse(200, nil, `{"ok": true}`),
+		},
+		"http://api.example.com/submit": {
+			httpx.NewMockResponse(200, nil, `{"id": 1}`),
+			httpx.NewMockResponse(200, nil, `{"id": 2}`),
+		},
+		"http://api.example.com/profile": {
+			httpx.NewMockResponse(200, nil, `{"name": "Bob"}`),
+			httpx.NewMockResponse(200, nil, `{"name": "Ann"}`),
+		},
+	}))
+
+	cache := webhooks.NewResponseCache(time.Minute, 10)
+	svc := webhooks.NewService(http.DefaultClient, nil, nil, map[string]string{"User-Agent": "goflow-testing"}, 10000, webhooks.WithCache(cache))
+
+	call := func(method, url, body string, headers map[string]string) *flows.WebhookCall {
+		request, err := httpx.NewRequest(method, url, strings.NewReader(body), headers)
+		require.NoError(t, err)
+		c, err := svc.Call(nil, request)
+		require.NoError(t, err)
+		return c
+	}
+
+	c := call("GET", "http://api.example.com/weather", "", nil)
+	assert.False(t, c.Cached)
+	assert.Equal(t, `{"temp": 21}`, string(c.ResponseBody))
+
+	// second identical call is served from the cache
+	c = call("GET", "http://api.example.com/weather", "", nil)
+	assert.True(t, c.Cached)
+	assert.Equal(t, 200, c.Response.StatusCode)
+	assert.Equal(t, `{"temp": 21}`, string(c.ResponseBody))
+	assert.Equal(t, `{"temp": 21}`, string(c.ResponseJSON))
+	assert.Equal(t, time.Duration(0), c.EndTime.Sub(c.StartTime))
+
+	// and that's visible in the event
+	event := events.NewWebhookCalled(c, flows.CallStatusSuccess, "")
+	assert.True(t, event.Cached)
+	assert.Contains(t, string(jsonx.MustMarshal(event)), `"cached":true`)
+
+	// unless the request asks for a fresh response
+	c = call("GET", "http://api.example.com/weather", "", map[string]string{"Cache-Control": "no-cache"})
+	assert.False(t, c.Cached)
+	assert.Equal(t, `{"temp": 22}`, string(c.ResponseBody))
+
+	// entries expire after the default TTL
+	dates.SetNowSource(dates.NewFixedNowSource(t0.Add(61 * time.Second)))
+	c = call("GET", "http://api.example.com/weather", "", nil)
+	assert.False(t, c.Cached)
+	assert.Equal(t, `{"temp": 23}`, string(c.ResponseBody))
+
+	// response can specify its own max-age
+	c = call("GET", "http://api.example.com/news", "", nil)
+	assert.False(t, c.Cached)
+	c = call("GET", "http://api.example.com/news", "", nil)
+	assert.True(t, c.Cached)
+	assert.Equal(t, `{"headline": "A"}`, string(c.ResponseBody))
+
+	dates.SetNowSource(dates.NewFixedNowSource(t0.Add(72 * time.Second)))
+	c = call("GET", "http://api.example.com/news", "", nil)
+	assert.False(t, c.Cached)
+	assert.Equal(t, `{"headline": "B"}`, string(c.ResponseBody))
+
+	// or that it can't be cached
+	c = call("GET", "http://api.example.com/news", "", nil)
+	assert.False(t, c.Cached)
+	assert.Equal(t, `{"headline": "C"}`, string(c.ResponseBody))
+
+	// error responses aren't cached
+	c = call("GET", "http://api.example.com/error", "", nil)
+	assert.False(t, c.Cached)
+	c = call("GET", "http://api.example.com/error", "", nil)
+	assert.False(t, c.Cached)
+	assert.Equal(t, 200, c.Response.StatusCode)
+
+	// nor are POSTs
+	c = call("POST", "http://api.example.com/submit", `{"name": "Bob"}`, nil)
+	assert.False(t, c.Cached)
+	c = call("POST", "http://api.example.com/submit", `{"name": "Bob"}`, nil)
+	assert.False(t, c.Cached)
+	assert.Equal(t, `{"id": 2}`, string(c.ResponseBody))
+
+	// calls with different credentials never share responses
+	c = call("GET", "http://api.example.com/profile", "", map[string]string{"Authorization": "Token bob"})
+	assert.False(t, c.Cached)
+	assert.Equal(t, `{"name": "Bob"}`, string(c.ResponseBody))
+	c = call("GET", "http://api.example.com/profile", "", map[string]string{"Authorization": "Token ann"})
+	assert.False(t, c.Cached)
+	assert.Equal(t, `{"name": "Ann"}`, string(c.ResponseBody))
+	c = call("GET", "http://api.example.com/profile", "", map[string]string{"Authorization": "Token bob"})
+	assert.True(t, c.Cached)
+	assert.Equal(t, `{"name": "Bob"}`, string(c.ResponseBody))
+
+	assert.Equal(t, 5, cache.Len())
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	defer dates.SetNowSource(dates.DefaultNowSource)
+	defer httpx.SetRequestor(httpx.DefaultRequestor)
+
+	t0 := time.Date(2019, 10, 7, 15, 21, 30, 0, time.UTC)
+	dates.SetNowSource(dates.NewFixedNowSource(t0))
+
+	httpx.SetRequestor(httpx.NewMockRequestor(map[string][]httpx.MockResponse{
+		"http://failing.example.com/hook": {
+			httpx.NewMockResponse(503, nil, `{"error": "down"}`),
+			httpx.MockConnectionError,
+			httpx.NewMockResponse(503, nil, `{"error": "down"}`),
+			httpx.NewMockResponse(200, nil, `{"ok": true}`),
+			httpx.NewMockResponse(200, nil, `{"ok": true}`),
+		},
+		"http://other.example.com/hook": {
+			httpx.NewMockResponse(200, nil, `{"ok": true}`),
+		},
+	}))
+
+	breaker := webhooks.NewCircuitBreaker(2, time.Minute)
+	svc := webhooks.NewService(http.DefaultClient, nil, nil, map[string]string{"User-Agent": "goflow-testing"}, 10000, webhooks.WithBreaker(breaker))
+
+	call := func(url string) *flows.WebhookCall {
+		request, err := httpx.NewRequest("POST", url, strings.NewReader(`{"foo": "bar"}`), nil)
+		require.NoError(t, err)
+		c, _ := svc.Call(nil, request)
+		require.NotNil(t, c)
+		return c
+	}
+
+	c := call("http://failing.example.com/hook")
+	assert.False(t, c.CircuitOpen)
+	assert.Equal(t, 503, c.Response.StatusCode)
+	assert.False(t, breaker.IsOpen("failing.example.com"))
+
+	c = call("http://failing.example.com/hook")
+	assert.False(t, c.CircuitOpen)
+	assert.Nil(t, c.Response)
+	assert.True(t, breaker.IsOpen("failing.example.com"))
+
+	// circuit is now open so calls are skipped
+	c = call("http://failing.example.com/hook")
+	assert.True(t, c.CircuitOpen)
+	assert.Nil(t, c.Response)
+	assert.Equal(t, "POST /hook HTTP/1.1\r\nHost: failing.example.com\r\nUser-Agent: goflow-testing\r\nContent-Length: 14\r\nAccept-Encoding: gzip\r\n\r\n{\"foo\": \"bar\"}", string(c.RequestTrace))
+
+	event := events.NewWebhookCalled(c, flows.CallStatusConnectionError, "")
+	assert.True(t, event.CircuitOpen)
+	assert.Equal(t, flows.CallStatusConnectionError, event.Status)
+	assert.Contains(t, string(jsonx.MustMarshal(event)), `"circuit_open":true`)
+
+	// other hosts aren't affected
+	c = call("http://other.example.com/hook")
+	assert.False(t, c.CircuitOpen)
+	assert.Equal(t, 200, c.Response.StatusCode)
+
+	// after the cooldown a trial call is allowed, which fails and re-opens the circuit
+	dates.SetNowSource(dates.NewFixedNowSource(t0.Add(61 * time.Second)))
+	c = call("http://failing.example.com/hook")
+	assert.False(t, c.CircuitOpen)
+	assert.Equal(t, 503, c.Response.StatusCode)
+
+	c = call("http://failing.example.com/hook")
+	assert.True(t, c.CircuitOpen)
+
+	// next trial call succeeds and closes the circuit
+	dates.SetNowSource(dates.NewFixedNowSource(t0.Add(122 * time.Second)))
+	c = call("http://failing.example.com/hook")
+	assert.False(t, c.CircuitOpen)
+	assert.Equal(t, 200, c.Response.StatusCode)
+	assert.False(t, breaker.IsOpen("failing.example.com"))
+
+	c = call("http://failing.example.com/hook")
+	assert.False(t, c.CircuitOpen)
+	assert.Equal(t, 200, c.Response.StatusCode)
+}
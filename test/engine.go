@@ -40,7 +40,7 @@ func newEmailService() *emailService {
 	return &emailService{}
 }
 
-func (s *emailService) Send(session flows.Session, addresses []string, subject, body string) error {
+func (s *emailService) Send(session flows.Session, email *flows.Email, logHTTP flows.HTTPLogCallback) error {
 	return nil
 }
 
@@ -135,6 +135,49 @@ func (s *ticketService) Open(session flows.Session, topic *flows.Topic, body str
 	return ticket, nil
 }
 
+func (s *ticketService) Close(session flows.Session, ticket *flows.Ticket, logHTTP flows.HTTPLogCallback) error {
+	s.logUpdate(ticket, "close", `{}`, logHTTP)
+	return nil
+}
+
+func (s *ticketService) Reopen(session flows.Session, ticket *flows.Ticket, logHTTP flows.HTTPLogCallback) error {
+	s.logUpdate(ticket, "reopen", `{}`, logHTTP)
+	return nil
+}
+
+func (s *ticketService) AddNote(session flows.Session, ticket *flows.Ticket, note string, logHTTP flows.HTTPLogCallback) error {
+	if strings.Contains(note, "fail") {
+		return errors.New("error calling ticket API")
+	}
+
+	s.logUpdate(ticket, "notes", fmt.Sprintf(`{"note":"%s"}`, note), logHTTP)
+	return nil
+}
+
+func (s *ticketService) Assign(session flows.Session, ticket *flows.Ticket, assignee *flows.User, logHTTP flows.HTTPLogCallback) error {
+	s.logUpdate(ticket, "assign", fmt.Sprintf(`{"assignee":"%s"}`, assignee.Email()), logHTTP)
+	return nil
+}
+
+func (s *ticketService) logUpdate(ticket *flows.Ticket, endpoint, body string, logHTTP flows.HTTPLogCallback) {
+	url := fmt.Sprintf("http://nyaruka.tickets.com/tickets/%s/%s.json", ticket.ExternalID(), endpoint)
+
+	logHTTP(&flows.HTTPLog{
+		HTTPTrace: &flows.HTTPTrace{
+			URL:        url,
+			StatusCode: 200,
+			Status:     flows.CallStatusSuccess,
+			Request:    fmt.Sprintf("POST /tickets/%s/%s.json HTTP/1.1\r\nAccept-Encoding: gzip\r\n\r\n%s", ticket.ExternalID(), endpoint, body),
+			Response:   "HTTP/1.0 200 OK\r\nContent-Length: 15\r\n\r\n{\"status\":\"ok\"}",
+			ElapsedMS:  1,
+			Retries:    0,
+		},
+		CreatedOn: time.Date(2019, 10, 16, 13, 59, 30, 123456789, time.UTC),
+	})
+}
+
+var _ flows.TicketService = (*ticketService)(nil)
+
 // implementation of an airtime service for testing which uses a fixed currency
 type airtimeService struct {
 	fixedCurrency string
@@ -1,6 +1,7 @@
 package smtpx
 
 import (
+	"bytes"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -72,11 +73,23 @@ func (c *Client) Send(m *Message) error {
 	mm := gomail.NewMessage()
 	mm.SetHeader("From", c.from)
 	mm.SetHeader("To", m.recipients...)
+	if len(m.cc) > 0 {
+		mm.SetHeader("Cc", m.cc...)
+	}
+	if len(m.bcc) > 0 {
+		mm.SetHeader("Bcc", m.bcc...) // used for envelope recipients but never written to the message
+	}
+	if m.replyTo != "" {
+		mm.SetHeader("Reply-To", m.replyTo)
+	}
 	mm.SetHeader("Subject", m.subject)
 	mm.SetBody("text/plain", m.text)
 	if m.html != "" {
 		mm.AddAlternative("text/html", m.html)
 	}
+	for _, a := range m.attachments {
+		mm.AttachReader(a.Filename, bytes.NewReader(a.Content), gomail.SetHeader(map[string][]string{"Content-Type": {a.ContentType}}))
+	}
 
 	d := gomail.NewDialer(c.host, c.port, c.username, c.password)
 	return d.DialAndSend(mm)
@@ -34,11 +34,16 @@ func (s *MockSender) Send(c *Client, m *Message) error {
 	b := &strings.Builder{}
 	b.WriteString("HELO localhost\n")
 	b.WriteString(fmt.Sprintf("MAIL FROM:<%s>\n", c.from))
-	for _, r := range m.recipients {
-		b.WriteString(fmt.Sprintf("RCPT TO:<%s>\n", r))
+	for _, recipients := range [][]string{m.recipients, m.cc, m.bcc} {
+		for _, r := range recipients {
+			b.WriteString(fmt.Sprintf("RCPT TO:<%s>\n", r))
+		}
 	}
 	b.WriteString("DATA\n")
 	b.WriteString(fmt.Sprintf("%s\n", m.text))
+	for _, a := range m.attachments {
+		b.WriteString(fmt.Sprintf("[%s %s %d bytes]\n", a.Filename, a.ContentType, len(a.Content)))
+	}
 	b.WriteString(".\n")
 	b.WriteString("QUIT\n")
 
@@ -4,12 +4,28 @@ import (
 	"time"
 )
 
+// Attachment is a file attached to an email message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// NewAttachment creates a new attachment
+func NewAttachment(filename, contentType string, content []byte) *Attachment {
+	return &Attachment{Filename: filename, ContentType: contentType, Content: content}
+}
+
 // Message is email message
 type Message struct {
-	recipients []string
-	subject    string
-	text       string
-	html       string
+	recipients  []string
+	cc          []string
+	bcc         []string
+	replyTo     string
+	subject     string
+	text        string
+	html        string
+	attachments []*Attachment
 }
 
 // NewMessage creates a new message
@@ -22,6 +38,30 @@ func NewMessage(recipients []string, subject, text, html string) *Message {
 	}
 }
 
+// WithCc sets the carbon copy recipients of this message
+func (m *Message) WithCc(cc ...string) *Message {
+	m.cc = cc
+	return m
+}
+
+// WithBcc sets the blind carbon copy recipients of this message
+func (m *Message) WithBcc(bcc ...string) *Message {
+	m.bcc = bcc
+	return m
+}
+
+// WithReplyTo sets the reply-to address of this message
+func (m *Message) WithReplyTo(replyTo string) *Message {
+	m.replyTo = replyTo
+	return m
+}
+
+// WithAttachments sets the attachments of this message
+func (m *Message) WithAttachments(attachments ...*Attachment) *Message {
+	m.attachments = attachments
+	return m
+}
+
 // Send an email using SMTP
 func Send(c *Client, m *Message, retries *RetryConfig) error {
 	var err error
@@ -139,13 +139,15 @@ func truncate(s string, limit int, ending string) string {
 // Redactor is a function which can redact the given string
 type Redactor func(s string) string
 
-// NewRedactor creates a new redaction function which replaces the given values
+// NewRedactor creates a new redaction function which replaces the given values. Empty values are ignored as they
+// would otherwise be matched between every character.
 func NewRedactor(mask string, values ...string) Redactor {
 	// convert list of redaction values to list of replacements with mask
-	replacements := make([]string, len(values)*2)
-	for i := range values {
-		replacements[i*2] = values[i]
-		replacements[i*2+1] = mask
+	replacements := make([]string, 0, len(values)*2)
+	for _, v := range values {
+		if v != "" {
+			replacements = append(replacements, v, mask)
+		}
 	}
 	return strings.NewReplacer(replacements...).Replace
 }
@@ -185,6 +185,8 @@ func TestRedactor(t *testing.T) {
 	assert.Equal(t, "", utils.NewRedactor("****", "abc")(""))                                        // empty input
 	assert.Equal(t, "**** def **** def", utils.NewRedactor("****", "abc")("abc def abc def"))        // all instances redacted
 	assert.Equal(t, "**** def **** jkl", utils.NewRedactor("****", "abc", "ghi")("abc def ghi jkl")) // all values redacted
+	assert.Equal(t, "hello", utils.NewRedactor("****", "")("hello"))                                 // empty values ignored
+	assert.Equal(t, "**** def", utils.NewRedactor("****", "", "abc")("abc def"))
 }
 
 func TestReplaceEscapedNulls(t *testing.T) {